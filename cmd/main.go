@@ -1,13 +1,24 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	timetracker "time-tracker"
 	"time-tracker/pkg/handler"
 	"time-tracker/pkg/repository"
+	"time-tracker/pkg/repository/repotest"
 	"time-tracker/pkg/service"
+	"time-tracker/pkg/songinfo"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
@@ -16,12 +27,22 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	storagePostgres = "postgres"
+	storageSQLite   = "sqlite"
+	storageMemory   = "memory"
+)
+
 // @title Music-Library
 // @version 1.0
 // @description API Server for Music-library Application
 
 // @host localhost:8000
 // @BasePath
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 const longText = `[Intro]
 Is this the real life? Is this just fantasy?
 Caught in a landslide, no escape from reality
@@ -98,6 +119,85 @@ func main() {
 		logger.WithError(err).Fatal("Error occurred while initializing config")
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			logger.WithError(err).Fatal("Error occurred while exporting library")
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		if err := runCheck(); err != nil {
+			logger.WithError(err).Fatal("Repository contract check failed")
+		}
+		logger.Info("Repository contract check passed")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		services *service.Service
+		m        *migrate.Migrate
+	)
+	switch storage := viper.GetString("STORAGE"); storage {
+	case storagePostgres, "":
+		var db *sqlx.DB
+		db, m = openPostgres(logger)
+		services = service.NewService(repository.NewRepository(db), songInfoClient(), authConfig())
+		workers := viper.GetInt("JOB_WORKERS")
+		if workers <= 0 {
+			workers = 4
+		}
+		services.Job.StartWorkers(ctx, workers)
+	case storageSQLite:
+		// Nothing runs the job queue without Postgres, so new songs are not
+		// enriched.
+		repos := repository.NewSQLiteRepository(openSQLite(logger))
+		if err := seedIfEmpty(ctx, repos); err != nil {
+			logger.WithError(err).Fatal("Failed to populate the SQLite database with test data")
+		}
+		services = service.NewService(repos, nil, authConfig())
+	case storageMemory:
+		repos := repository.NewMemoryRepository()
+		if err := seedRepository(ctx, repos); err != nil {
+			logger.WithError(err).Fatal("Failed to populate the in-memory storage with test data")
+		}
+		logger.Info("Using in-memory storage, data is lost on exit")
+		services = service.NewService(repos, nil, authConfig())
+	default:
+		logger.WithField("storage", storage).Fatal("Unknown STORAGE, want postgres, sqlite or memory")
+	}
+	services.Similar.StartIndexer(ctx)
+	if key := viper.GetString("ADMIN_API_KEY"); key != "" {
+		if err := services.User.EnsureAdminKey(ctx, key); err != nil {
+			logger.WithError(err).Fatal("Failed to register ADMIN_API_KEY")
+		}
+	} else {
+		logger.Warn("ADMIN_API_KEY is not set, only existing API keys can change the library")
+	}
+	handlers := handler.NewHandler(services, handlerConfig())
+	logger.Info("Repositories and services initialized")
+
+	srv := new(timetracker.Server)
+	port := viper.GetString("port")
+	logger.Infof("Starting server on port %s", port)
+	if err := srv.Run(port, handlers.InitRoutes()); err != nil {
+		logger.WithError(err).Fatal("Error occurred while running HTTP server")
+	}
+	logger.Info("Server started successfully")
+
+	if m != nil {
+		if err := m.Down(); err != nil && err != migrate.ErrNoChange {
+			logger.WithError(err).Fatal("Error occurred while migrating database")
+		}
+		logger.Info("Database migration down")
+	}
+}
+
+// openPostgres migrates the database, fills an empty one with test data and
+// returns the connection together with the migration instance.
+func openPostgres(logger *logrus.Logger) (*sqlx.DB, *migrate.Migrate) {
 	m, err := migrate.New(
 		"file://migrations",
 		"postgres://"+
@@ -117,14 +217,7 @@ func main() {
 	}
 	logger.Info("Database migration successful")
 
-	db, err := repository.NewPostgresDB(repository.Config{
-		Host:     viper.GetString("DB_HOST"),
-		Port:     viper.GetString("DB_PORT"),
-		Username: viper.GetString("DB_USERNAME"),
-		Password: viper.GetString("DB_PASSWORD"),
-		DBName:   viper.GetString("DB_DBNAME"),
-		SSLMode:  viper.GetString("DB_SSLMODE"),
-	})
+	db, err := repository.NewPostgresDB(dbConfig())
 	if err != nil {
 		logger.WithError(err).Fatal("Error occurred while connecting to database")
 	}
@@ -175,29 +268,201 @@ func main() {
 	} else {
 		logrus.Info("The database is not empty")
 	}
+	logger.Info("Test data inserted successfully")
+	return db, m
+}
 
+// openSQLite applies the SQLite migrations to SQLITE_PATH and opens it.
+// Unlike the Postgres database it is never migrated down, it is meant to
+// keep the library between runs.
+func openSQLite(logger *logrus.Logger) *sqlx.DB {
+	path := viper.GetString("SQLITE_PATH")
+	m, err := migrate.New("file://migrations/sqlite", "sqlite3://"+path)
 	if err != nil {
-		logger.WithError(err).Fatal("Failed to populate the database with test data")
+		logger.WithError(err).Fatal("Error occurred while creating migration instance")
 	}
-	logger.Info("Test data inserted successfully")
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		logger.WithError(err).Fatal("Error occurred while migrating database")
+	}
+	m.Close()
+	logger.Info("Database migration successful")
 
-	repos := repository.NewRepository(db)
-	services := service.NewService(repos)
-	handlers := handler.NewHandler(services)
-	logger.Info("Repositories and services initialized")
+	db, err := repository.NewSQLiteDB(path)
+	if err != nil {
+		logger.WithError(err).Fatal("Error occurred while connecting to database")
+	}
+	return db
+}
 
-	srv := new(timetracker.Server)
-	port := viper.GetString("port")
-	logger.Infof("Starting server on port %s", port)
-	if err := srv.Run(port, handlers.InitRoutes()); err != nil {
-		logger.WithError(err).Fatal("Error occurred while running HTTP server")
+// seedIfEmpty fills repos with test data unless it already has songs.
+func seedIfEmpty(ctx context.Context, repos *repository.Repository) error {
+	songs, err := repos.GetAllSongs(ctx)
+	if err != nil {
+		return err
 	}
-	logger.Info("Server started successfully")
+	if len(songs) > 0 {
+		logrus.Info("The database is not empty")
+		return nil
+	}
+	logrus.Info("The database is empty, populating with test data")
+	return seedRepository(ctx, repos)
+}
 
-	if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-		logger.WithError(err).Fatal("Error occurred while migrating database")
+// seedRepository fills an empty storage backend with the same test data the
+// Postgres database starts with.
+func seedRepository(ctx context.Context, repos *repository.Repository) error {
+	for _, seed := range []struct {
+		group, song string
+		details     timetracker.UpdateSongDetailsInput
+	}{
+		{"Metallica", "Enter Sandman", timetracker.UpdateSongDetailsInput{
+			ReleaseDate: "1991-07-29", Text: "Say your prayers, little one...", Link: "https://example.com/enter-sandman"}},
+		{"Nirvana", "Smells Like Teen Spirit", timetracker.UpdateSongDetailsInput{
+			ReleaseDate: "1991-09-10", Text: "Load up on guns, bring your friends...", Link: "https://example.com/smells-like-teen-spirit"}},
+		{"Queen", "Bohemian Rhapsody", timetracker.UpdateSongDetailsInput{
+			ReleaseDate: "1975-10-31", Text: longText, Link: "https://example.com/bohemian-rhapsody"}},
+	} {
+		groupId, err := repos.CreateGroup(ctx, timetracker.Group{GroupName: seed.group})
+		if err != nil {
+			return err
+		}
+		songId, err := repos.CreateSong(ctx, timetracker.Song{SongName: seed.song, GroupId: groupId})
+		if err != nil {
+			return err
+		}
+		if err := repos.UpdateSongDetails(ctx, songId, seed.details); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCheck implements "check", which runs the repository contract against
+// the storage backend selected by STORAGE. It cleans up after itself, but
+// should still not be pointed at a production database.
+func runCheck() error {
+	var repos *repository.Repository
+	switch storage := viper.GetString("STORAGE"); storage {
+	case storagePostgres, "":
+		db, err := repository.NewPostgresDB(dbConfig())
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		repos = repository.NewRepository(db)
+	case storageSQLite:
+		db := openSQLite(logrus.StandardLogger())
+		defer db.Close()
+		repos = repository.NewSQLiteRepository(db)
+	case storageMemory:
+		repos = repository.NewMemoryRepository()
+	default:
+		return fmt.Errorf("unknown STORAGE %q", storage)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return repotest.Run(ctx, repos)
+}
+
+// runExport implements "export [-format ndjson|csv|archive] [-o file]". It
+// writes the library to stdout unless an output file is given.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", service.ExportFormatNDJSON, "export format: ndjson, csv or archive")
+	output := flags.String("o", "", "output file, defaults to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := repository.NewPostgresDB(dbConfig())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	out := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	w := bufio.NewWriter(out)
+	services := service.NewService(repository.NewRepository(db), nil, service.AuthConfig{})
+	if err := services.Export.Export(ctx, *format, w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// songInfoClient returns the song info API client configured by SONG_INFO_URL,
+// or nil when it is not set and songs should not be enriched.
+func songInfoClient() service.SongInfo {
+	baseURL := viper.GetString("SONG_INFO_URL")
+	if baseURL == "" {
+		logrus.Info("SONG_INFO_URL is not set, song enrichment is disabled")
+		return nil
+	}
+	cfg := songinfo.DefaultConfig(baseURL)
+	if viper.IsSet("SONG_INFO_TIMEOUT") {
+		cfg.Timeout = viper.GetDuration("SONG_INFO_TIMEOUT")
+	}
+	if viper.IsSet("SONG_INFO_RETRIES") {
+		cfg.MaxRetries = viper.GetInt("SONG_INFO_RETRIES")
+	}
+	logrus.WithField("url", baseURL).Info("Song enrichment enabled")
+	return songinfo.NewClient(cfg)
+}
+
+// authConfig returns the access token settings. Without JWT_SECRET a random
+// secret is used, so tokens do not survive a restart.
+func authConfig() service.AuthConfig {
+	cfg := service.AuthConfig{
+		Secret:   []byte(viper.GetString("JWT_SECRET")),
+		TokenTTL: service.DefaultTokenTTL,
+	}
+	if viper.IsSet("JWT_TTL") {
+		cfg.TokenTTL = viper.GetDuration("JWT_TTL")
+	}
+	if len(cfg.Secret) == 0 {
+		cfg.Secret = make([]byte, 32)
+		if _, err := rand.Read(cfg.Secret); err != nil {
+			logrus.WithError(err).Fatal("Failed to generate a JWT secret")
+		}
+		logrus.Warn("JWT_SECRET is not set, access tokens are invalidated on restart")
+	}
+	return cfg
+}
+
+func handlerConfig() handler.Config {
+	cfg := handler.Config{QueryTimeout: handler.DefaultQueryTimeout}
+	if viper.IsSet("QUERY_TIMEOUT") {
+		cfg.QueryTimeout = viper.GetDuration("QUERY_TIMEOUT")
+	}
+	routes, err := handler.ParseRouteTimeouts(viper.GetString("ROUTE_TIMEOUTS"))
+	if err != nil {
+		logrus.WithError(err).Fatal("Invalid ROUTE_TIMEOUTS")
+	}
+	cfg.RouteTimeouts = routes
+	return cfg
+}
+
+func dbConfig() repository.Config {
+	return repository.Config{
+		Host:     viper.GetString("DB_HOST"),
+		Port:     viper.GetString("DB_PORT"),
+		Username: viper.GetString("DB_USERNAME"),
+		Password: viper.GetString("DB_PASSWORD"),
+		DBName:   viper.GetString("DB_DBNAME"),
+		SSLMode:  viper.GetString("DB_SSLMODE"),
 	}
-	logger.Info("Database migration down")
 }
 
 func initConfig() error {
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time-tracker/pkg/songinfo"
+
+	"github.com/sirupsen/logrus"
+)
+
+// A local stand-in for the song info API, point SONG_INFO_URL at it to run
+// song enrichment offline.
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	flag.Parse()
+
+	stub := songinfo.NewStub()
+	stub.Add("Muse", "Supermassive Black Hole", songinfo.Info{
+		ReleaseDate: "16.07.2006",
+		Text:        "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\nYou caught me under false pretenses\nHow long before you let me go?\n\nOoh\nYou set my soul alight\nOoh\nYou set my soul alight",
+		Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	})
+	stub.Add("Metallica", "Nothing Else Matters", songinfo.Info{
+		ReleaseDate: "20.04.1992",
+		Text:        "So close, no matter how far\nCouldn't be much more from the heart\nForever trusting who we are\nAnd nothing else matters",
+		Link:        "https://example.com/nothing-else-matters",
+	})
+	stub.Add("Nirvana", "Come as You Are", songinfo.Info{
+		ReleaseDate: "02.03.1992",
+		Text:        "Come as you are, as you were\nAs I want you to be\nAs a friend, as a friend\nAs an old enemy",
+		Link:        "https://example.com/come-as-you-are",
+	})
+
+	logrus.Infof("Song info stub listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, stub); err != nil {
+		logrus.WithError(err).Fatal("Song info stub stopped")
+	}
+}
@@ -0,0 +1,82 @@
+package musiclibrary
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kinds of domain errors. Repositories and services return an *Error of one
+// of these kinds, so callers can tell them apart with errors.Is without
+// knowing which layer or storage backend produced them.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrForeignKey  = errors.New("referenced record does not exist")
+	ErrUnsupported = errors.New("not supported")
+	// ErrUnauthorized is a missing or invalid credential, ErrForbidden a
+	// valid one whose role does not allow the request.
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// FieldError explains why a single input field was rejected.
+type FieldError struct {
+	Field   string `json:"field" example:"releaseDate"`
+	Message string `json:"message" example:"must be YYYY-MM-DD"`
+}
+
+// Error is a domain error. Message is safe to show to API clients, Err is the
+// underlying cause if there is one.
+type Error struct {
+	Kind    error
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Kind.Error()
+	}
+	if len(e.Fields) > 0 {
+		fields := make([]string, len(e.Fields))
+		for i, f := range e.Fields {
+			fields[i] = f.Field + " " + f.Message
+		}
+		msg += ": " + strings.Join(fields, ", ")
+	}
+	return msg
+}
+
+// Is reports whether target is the kind of e.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewNotFoundError(format string, args ...any) *Error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewConflictError(format string, args ...any) *Error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewForeignKeyError(format string, args ...any) *Error {
+	return &Error{Kind: ErrForeignKey, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewValidationError reports a single invalid field.
+func NewValidationError(field, message string) *Error {
+	return &Error{
+		Kind:    ErrValidation,
+		Message: "invalid input",
+		Fields:  []FieldError{{Field: field, Message: message}},
+	}
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_album_tracks_song_id;
+DROP INDEX IF EXISTS idx_album_title;
+
+DROP TABLE IF EXISTS albumTracks;
+DROP TABLE IF EXISTS albums;
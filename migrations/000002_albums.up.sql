@@ -0,0 +1,24 @@
+CREATE TABLE albums
+(
+    id serial PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    releaseDate DATE,
+    label VARCHAR(255) NOT NULL DEFAULT '',
+    coverLink VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE albumTracks
+(
+    albumId INT NOT NULL,
+    songId INT NOT NULL,
+    discNumber INT NOT NULL DEFAULT 1,
+    trackNumber INT NOT NULL,
+    PRIMARY KEY (albumId, songId),
+    UNIQUE (albumId, discNumber, trackNumber),
+    FOREIGN KEY (albumId) REFERENCES albums(id) ON DELETE CASCADE,
+    FOREIGN KEY (songId) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_album_title ON albums(title);
+
+CREATE INDEX idx_album_tracks_song_id ON albumTracks(songId);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_group_members_artist_id;
+DROP INDEX IF EXISTS idx_group_members_group_id;
+DROP INDEX IF EXISTS idx_artist_name;
+
+DROP TABLE IF EXISTS groupMembers;
+DROP TABLE IF EXISTS artists;
@@ -0,0 +1,25 @@
+CREATE TABLE artists
+(
+    id serial PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    birthDate DATE
+);
+
+CREATE TABLE groupMembers
+(
+    id serial PRIMARY KEY,
+    groupId INT NOT NULL,
+    artistId INT NOT NULL,
+    role VARCHAR(255) NOT NULL DEFAULT '',
+    startDate DATE,
+    endDate DATE,
+    FOREIGN KEY (groupId) REFERENCES groupss(id) ON DELETE CASCADE,
+    FOREIGN KEY (artistId) REFERENCES artists(id) ON DELETE CASCADE,
+    CHECK (endDate IS NULL OR startDate IS NULL OR endDate >= startDate)
+);
+
+CREATE INDEX idx_artist_name ON artists(name);
+
+CREATE INDEX idx_group_members_group_id ON groupMembers(groupId);
+
+CREATE INDEX idx_group_members_artist_id ON groupMembers(artistId);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_group_tags_tag_id;
+DROP INDEX IF EXISTS idx_song_tags_tag_id;
+DROP INDEX IF EXISTS idx_group_genres_genre_id;
+DROP INDEX IF EXISTS idx_song_genres_genre_id;
+DROP INDEX IF EXISTS idx_genre_parent_id;
+
+DROP TABLE IF EXISTS groupTags;
+DROP TABLE IF EXISTS songTags;
+DROP TABLE IF EXISTS groupGenres;
+DROP TABLE IF EXISTS songGenres;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS genres;
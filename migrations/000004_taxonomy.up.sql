@@ -0,0 +1,59 @@
+CREATE TABLE genres
+(
+    id serial PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    parentId INT,
+    FOREIGN KEY (parentId) REFERENCES genres(id) ON DELETE SET NULL
+);
+
+CREATE TABLE tags
+(
+    id serial PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE songGenres
+(
+    songId INT NOT NULL,
+    genreId INT NOT NULL,
+    PRIMARY KEY (songId, genreId),
+    FOREIGN KEY (songId) REFERENCES songs(id) ON DELETE CASCADE,
+    FOREIGN KEY (genreId) REFERENCES genres(id) ON DELETE CASCADE
+);
+
+CREATE TABLE groupGenres
+(
+    groupId INT NOT NULL,
+    genreId INT NOT NULL,
+    PRIMARY KEY (groupId, genreId),
+    FOREIGN KEY (groupId) REFERENCES groupss(id) ON DELETE CASCADE,
+    FOREIGN KEY (genreId) REFERENCES genres(id) ON DELETE CASCADE
+);
+
+CREATE TABLE songTags
+(
+    songId INT NOT NULL,
+    tagId INT NOT NULL,
+    PRIMARY KEY (songId, tagId),
+    FOREIGN KEY (songId) REFERENCES songs(id) ON DELETE CASCADE,
+    FOREIGN KEY (tagId) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE TABLE groupTags
+(
+    groupId INT NOT NULL,
+    tagId INT NOT NULL,
+    PRIMARY KEY (groupId, tagId),
+    FOREIGN KEY (groupId) REFERENCES groupss(id) ON DELETE CASCADE,
+    FOREIGN KEY (tagId) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_genre_parent_id ON genres(parentId);
+
+CREATE INDEX idx_song_genres_genre_id ON songGenres(genreId);
+
+CREATE INDEX idx_group_genres_genre_id ON groupGenres(genreId);
+
+CREATE INDEX idx_song_tags_tag_id ON songTags(tagId);
+
+CREATE INDEX idx_group_tags_tag_id ON groupTags(tagId);
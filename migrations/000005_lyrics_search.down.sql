@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_song_details_search_vector;
+
+ALTER TABLE songDetails DROP COLUMN IF EXISTS searchVector;
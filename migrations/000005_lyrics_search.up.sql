@@ -0,0 +1,5 @@
+ALTER TABLE songDetails
+    ADD COLUMN searchVector tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', COALESCE(text, ''))) STORED;
+
+CREATE INDEX idx_song_details_search_vector ON songDetails USING GIN (searchVector);
@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_group_name_trgm ON groupss USING GIN (groupName gin_trgm_ops);
+
+CREATE INDEX idx_song_name_trgm ON songs USING GIN (songName gin_trgm_ops);
@@ -0,0 +1,7 @@
+CREATE TABLE songLrc
+(
+    songId INT PRIMARY KEY,
+    lrc TEXT NOT NULL,
+    updatedAt TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (songId) REFERENCES songs(id) ON DELETE CASCADE
+);
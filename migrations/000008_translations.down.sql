@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_song_translations_original;
+
+DROP TABLE IF EXISTS songTranslations;
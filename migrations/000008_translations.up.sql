@@ -0,0 +1,13 @@
+CREATE TABLE songTranslations
+(
+    id serial PRIMARY KEY,
+    songId INT NOT NULL,
+    language VARCHAR(35) NOT NULL,
+    isOriginal BOOLEAN NOT NULL DEFAULT FALSE,
+    translator VARCHAR(255) NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    UNIQUE (songId, language),
+    FOREIGN KEY (songId) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_song_translations_original ON songTranslations(songId) WHERE isOriginal;
@@ -0,0 +1,14 @@
+CREATE TABLE songDetailsRevisions
+(
+    id serial PRIMARY KEY,
+    songId INT NOT NULL,
+    revision INT NOT NULL,
+    releaseDate DATE,
+    text TEXT,
+    link VARCHAR(255),
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    createdAt TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (songId, revision),
+    FOREIGN KEY (songId) REFERENCES songs(id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+CREATE TABLE jobs
+(
+    id serial PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(16) NOT NULL DEFAULT 'queued',
+    attempts INT NOT NULL DEFAULT 0,
+    maxAttempts INT NOT NULL DEFAULT 5,
+    lastError TEXT NOT NULL DEFAULT '',
+    runAt TIMESTAMP NOT NULL DEFAULT now(),
+    lockedUntil TIMESTAMP,
+    createdAt TIMESTAMP NOT NULL DEFAULT now(),
+    updatedAt TIMESTAMP NOT NULL DEFAULT now(),
+    CHECK (status IN ('queued', 'running', 'done', 'dead'))
+);
+
+CREATE INDEX jobs_runnable_idx ON jobs (runAt, id) WHERE status IN ('queued', 'running');
+CREATE INDEX jobs_status_idx ON jobs (status, id);
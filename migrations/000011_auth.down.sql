@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS apiKeys;
+DROP TABLE IF EXISTS users;
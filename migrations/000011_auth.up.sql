@@ -0,0 +1,23 @@
+CREATE TABLE users
+(
+    id serial PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    role VARCHAR(16) NOT NULL,
+    createdAt TIMESTAMP NOT NULL DEFAULT now(),
+    CHECK (role IN ('viewer', 'editor', 'admin'))
+);
+
+-- Only a SHA-256 hash of each key is kept, prefix is the start of the key
+-- so users can tell their keys apart.
+CREATE TABLE apiKeys
+(
+    id serial PRIMARY KEY,
+    userId INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    keyHash CHAR(64) NOT NULL UNIQUE,
+    createdAt TIMESTAMP NOT NULL DEFAULT now(),
+    lastUsedAt TIMESTAMP
+);
+
+CREATE INDEX apikeys_userid_idx ON apiKeys (userId);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS playlistItems;
+DROP TABLE IF EXISTS playlists;
+DROP TABLE IF EXISTS songRatings;
+DROP TABLE IF EXISTS favorites;
@@ -0,0 +1,45 @@
+CREATE TABLE favorites
+(
+    userId INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    songId INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    createdAt TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (userId, songId)
+);
+
+CREATE INDEX favorites_songid_idx ON favorites (songId);
+
+CREATE TABLE songRatings
+(
+    userId INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    songId INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL,
+    updatedAt TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (userId, songId),
+    CHECK (rating BETWEEN 1 AND 5)
+);
+
+CREATE INDEX songratings_songid_idx ON songRatings (songId);
+
+CREATE TABLE playlists
+(
+    id serial PRIMARY KEY,
+    userId INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    createdAt TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX playlists_userid_idx ON playlists (userId);
+
+-- position only orders the items of a playlist. Deleting a song leaves a
+-- gap, so positions are counted when reading and renumbered on every change.
+CREATE TABLE playlistItems
+(
+    id serial PRIMARY KEY,
+    playlistId INT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    songId INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    position INT NOT NULL
+);
+
+CREATE INDEX playlistitems_playlistid_idx ON playlistItems (playlistId, position);
+CREATE INDEX playlistitems_songid_idx ON playlistItems (songId);
@@ -0,0 +1,19 @@
+-- A play is recorded once per user, song and time, so clients can submit
+-- offline plays again without counting them twice. scrobble is decided
+-- when the play is recorded and statistics only count scrobbles.
+CREATE TABLE plays
+(
+    id serial PRIMARY KEY,
+    userId INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    songId INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    playedAt TIMESTAMP NOT NULL,
+    duration INT NOT NULL,
+    songDuration INT NOT NULL DEFAULT 0,
+    client VARCHAR(255) NOT NULL DEFAULT '',
+    scrobble BOOLEAN NOT NULL,
+    UNIQUE (userId, songId, playedAt),
+    CHECK (duration >= 0 AND songDuration >= 0)
+);
+
+CREATE INDEX plays_playedat_idx ON plays (playedAt) WHERE scrobble;
+CREATE INDEX plays_songid_idx ON plays (songId, playedAt) WHERE scrobble;
@@ -0,0 +1,8 @@
+-- Term counts of the lyrics of each song, as JSON, so the similar songs
+-- index does not have to tokenize the whole library when the server starts.
+-- Changing the text of a song deletes its row and the song is indexed again.
+CREATE TABLE songVectors
+(
+    songId INT PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+    terms TEXT NOT NULL
+);
@@ -0,0 +1,4 @@
+-- claims counts the times a job was handed to a worker. Unlike attempts it
+-- is never reset, so a worker recording the outcome of an earlier run of a
+-- job cannot be mistaken for the one running it now.
+ALTER TABLE jobs ADD COLUMN claims INT NOT NULL DEFAULT 0;
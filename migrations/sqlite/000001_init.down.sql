@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS songdetails;
+DROP TABLE IF EXISTS songs;
+DROP TABLE IF EXISTS groupss;
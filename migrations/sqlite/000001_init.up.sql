@@ -0,0 +1,33 @@
+-- SQLite keeps column names as written, so they are lower case here to
+-- match what Postgres returns for the unquoted names in ../000001_init.up.sql.
+CREATE TABLE groupss
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    groupname TEXT NOT NULL
+);
+CREATE TABLE songs
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    songname TEXT NOT NULL,
+    groupid INTEGER NOT NULL,
+    FOREIGN KEY (groupid) REFERENCES groupss(id) ON DELETE CASCADE
+);
+
+CREATE TABLE songdetails
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    releasedate TEXT CHECK (releasedate IS NULL OR date(releasedate) IS releasedate),
+    text TEXT DEFAULT 'N/A',
+    link TEXT DEFAULT 'N/A',
+    songid INTEGER NOT NULL UNIQUE,
+    FOREIGN KEY (songid) REFERENCES songs(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_group_name ON groupss(groupname);
+
+CREATE INDEX idx_song_name ON songs(songname);
+
+CREATE INDEX idx_song_group_id ON songs(groupid);
+
+CREATE INDEX idx_song_details_release_date ON songdetails(releasedate);
+
+CREATE INDEX idx_song_details_link ON songdetails(link);
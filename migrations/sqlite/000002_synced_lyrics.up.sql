@@ -0,0 +1,7 @@
+CREATE TABLE songlrc
+(
+    songid INTEGER PRIMARY KEY,
+    lrc TEXT NOT NULL,
+    updatedat TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%S', 'now')),
+    FOREIGN KEY (songid) REFERENCES songs(id) ON DELETE CASCADE
+);
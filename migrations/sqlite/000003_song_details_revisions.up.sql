@@ -0,0 +1,14 @@
+CREATE TABLE songdetailsrevisions
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    songid INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    releasedate TEXT,
+    text TEXT,
+    link TEXT,
+    author TEXT NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    createdat TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%S', 'now')),
+    UNIQUE (songid, revision),
+    FOREIGN KEY (songid) REFERENCES songs(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS playlistitems;
+DROP TABLE IF EXISTS playlists;
+DROP TABLE IF EXISTS songratings;
+DROP TABLE IF EXISTS favorites;
@@ -0,0 +1,50 @@
+CREATE TABLE favorites
+(
+    userid INTEGER NOT NULL,
+    songid INTEGER NOT NULL,
+    createdat TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%S', 'now')),
+    PRIMARY KEY (userid, songid),
+    FOREIGN KEY (userid) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (songid) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX favorites_songid_idx ON favorites (songid);
+
+CREATE TABLE songratings
+(
+    userid INTEGER NOT NULL,
+    songid INTEGER NOT NULL,
+    rating INTEGER NOT NULL,
+    updatedat TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%S', 'now')),
+    PRIMARY KEY (userid, songid),
+    CHECK (rating BETWEEN 1 AND 5),
+    FOREIGN KEY (userid) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (songid) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX songratings_songid_idx ON songratings (songid);
+
+CREATE TABLE playlists
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    userid INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    createdat TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%S', 'now')),
+    FOREIGN KEY (userid) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX playlists_userid_idx ON playlists (userid);
+
+CREATE TABLE playlistitems
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    playlistid INTEGER NOT NULL,
+    songid INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    FOREIGN KEY (playlistid) REFERENCES playlists(id) ON DELETE CASCADE,
+    FOREIGN KEY (songid) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX playlistitems_playlistid_idx ON playlistitems (playlistid, position);
+CREATE INDEX playlistitems_songid_idx ON playlistitems (songid);
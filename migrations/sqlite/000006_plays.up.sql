@@ -0,0 +1,18 @@
+CREATE TABLE plays
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    userid INTEGER NOT NULL,
+    songid INTEGER NOT NULL,
+    playedat TEXT NOT NULL,
+    duration INTEGER NOT NULL,
+    songduration INTEGER NOT NULL DEFAULT 0,
+    client TEXT NOT NULL DEFAULT '',
+    scrobble INTEGER NOT NULL,
+    UNIQUE (userid, songid, playedat),
+    CHECK (duration >= 0 AND songduration >= 0),
+    FOREIGN KEY (userid) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (songid) REFERENCES songs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX plays_playedat_idx ON plays (playedat) WHERE scrobble;
+CREATE INDEX plays_songid_idx ON plays (songid, playedat) WHERE scrobble;
@@ -0,0 +1,6 @@
+CREATE TABLE songvectors
+(
+    songid INTEGER PRIMARY KEY,
+    terms TEXT NOT NULL,
+    FOREIGN KEY (songid) REFERENCES songs(id) ON DELETE CASCADE
+);
@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary CreateAlbum
+// @Tags album
+// @Description Create a new album
+// @ID create-album
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param input body musiclibrary.Album true "Album information"
+// @Success 200 {object} map[string]interface{} "Returns album ID"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to create album"
+// @Router /api/album/ [post]
+func (h *Handler) createAlbum(c *gin.Context) {
+	var album musiclibrary.Album
+	if err := c.ShouldBindJSON(&album); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for create album")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	id, err := h.services.Album.CreateAlbum(c.Request.Context(), album)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create album")
+		handleError(c, err, "Failed to create album")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id": id,
+	}).Info("Album created successfully")
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary GetAllAlbums
+// @Tags album
+// @Description Get a list of all albums
+// @ID get-all-albums
+// @Accept  json
+// @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of albums per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
+// @Success 200 {object} getAllAlbumsResponse "Returns a list of all albums"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 422 {object} errorResponse "Invalid cursor"
+// @Failure 500 {object} errorResponse "Failed to get all albums"
+// @Router /api/album/ [get]
+func (h *Handler) getAllAlbums(c *gin.Context) {
+	albumList, err := h.services.Album.GetAllAlbums(c.Request.Context(), pageParams(c, defaultPageLimit))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get all albums")
+		handleError(c, err, "Failed to get all albums")
+		return
+	}
+
+	logrus.Info("Retrieved all albums successfully")
+
+	c.JSON(http.StatusOK, getAllAlbumsResponse{
+		Data:     albumList.Items,
+		pageMeta: pageLinks(c, albumList),
+	})
+}
+
+// @Summary GetAlbumById
+// @Tags album
+// @Description Get an album with its track list
+// @ID get-album-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Album ID"
+// @Success 200 {object} albumResponse "Album with ordered tracks"
+// @Failure 400 {object} errorResponse "Invalid album ID"
+// @Failure 404 {object} errorResponse "Album not found"
+// @Failure 500 {object} errorResponse "Failed to get album"
+// @Router /api/album/{id} [get]
+func (h *Handler) getAlbumById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid album ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid album ID")
+		return
+	}
+
+	album, err := h.services.Album.GetAlbumById(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get album")
+		handleError(c, err, "Failed to get album")
+		return
+	}
+
+	tracks, err := h.services.Album.GetAlbumTracks(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get album tracks")
+		handleError(c, err, "Failed to get album")
+		return
+	}
+
+	c.JSON(http.StatusOK, albumResponse{
+		Data:   album,
+		Tracks: tracks,
+	})
+}
+
+// @Summary UpdateAlbum
+// @Tags album
+// @Description Update an existing album
+// @ID update-album
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Album ID"
+// @Param input body musiclibrary.UpdateAlbumInput true "Album information"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Album not found"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to update album"
+// @Router /api/album/{id} [put]
+func (h *Handler) updateAlbum(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid album ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid album ID")
+		return
+	}
+
+	var input musiclibrary.UpdateAlbumInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for update album")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	err = h.services.Album.UpdateAlbum(c.Request.Context(), id, input)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update album")
+		handleError(c, err, "Failed to update album")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id": id,
+	}).Info("Album updated successfully")
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary DeleteAlbum
+// @Tags album
+// @Description Delete an existing album, its track list is removed with it
+// @ID delete-album
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Album ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid album ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Album not found"
+// @Failure 500 {object} errorResponse "Failed to delete album"
+// @Router /api/album/{id} [delete]
+func (h *Handler) deleteAlbum(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid album ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid album ID")
+		return
+	}
+
+	err = h.services.Album.DeleteAlbum(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete album")
+		handleError(c, err, "Failed to delete album")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"album_id": id,
+	}).Info("Album deleted successfully")
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary AddAlbumTrack
+// @Tags album
+// @Description Put a song on an album at the given disc and track position
+// @ID add-album-track
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Album ID"
+// @Param input body musiclibrary.AlbumTrackInput true "Track position"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 409 {object} errorResponse "Track position already taken"
+// @Failure 422 {object} errorResponse "Invalid fields, album or song"
+// @Failure 500 {object} errorResponse "Failed to add track"
+// @Router /api/album/{id}/tracks [post]
+func (h *Handler) addAlbumTrack(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid album ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid album ID")
+		return
+	}
+
+	var input musiclibrary.AlbumTrackInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for add album track")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	err = h.services.Album.AddTrack(c.Request.Context(), id, input)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add track")
+		handleError(c, err, "Failed to add track")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary RemoveAlbumTrack
+// @Tags album
+// @Description Remove a song from an album
+// @ID remove-album-track
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Album ID"
+// @Param songId path int true "Song ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid album or song ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Album track not found"
+// @Failure 500 {object} errorResponse "Failed to remove track"
+// @Router /api/album/{id}/tracks/{songId} [delete]
+func (h *Handler) removeAlbumTrack(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid album ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid album ID")
+		return
+	}
+
+	songId, err := strconv.Atoi(c.Param("songId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	err = h.services.Album.RemoveTrack(c.Request.Context(), id, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove track")
+		handleError(c, err, "Failed to remove track")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+type getAllAlbumsResponse struct {
+	Data []musiclibrary.Album `json:"data"`
+	pageMeta
+}
+type albumResponse struct {
+	Data   musiclibrary.Album        `json:"data"`
+	Tracks []musiclibrary.AlbumTrack `json:"tracks"`
+}
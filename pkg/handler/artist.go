@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary CreateArtist
+// @Tags artist
+// @Description Create a new artist
+// @ID create-artist
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param input body musiclibrary.Artist true "Artist information"
+// @Success 200 {object} map[string]interface{} "Returns artist ID"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to create artist"
+// @Router /api/artist/ [post]
+func (h *Handler) createArtist(c *gin.Context) {
+	var artist musiclibrary.Artist
+	if err := c.ShouldBindJSON(&artist); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for create artist")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	id, err := h.services.Artist.CreateArtist(c.Request.Context(), artist)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create artist")
+		handleError(c, err, "Failed to create artist")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id": id,
+	}).Info("Artist created successfully")
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary GetAllArtists
+// @Tags artist
+// @Description Get a list of all artists
+// @ID get-all-artists
+// @Accept  json
+// @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of artists per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
+// @Success 200 {object} getAllArtistsResponse "Returns a list of all artists"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 422 {object} errorResponse "Invalid cursor"
+// @Failure 500 {object} errorResponse "Failed to get all artists"
+// @Router /api/artist/ [get]
+func (h *Handler) getAllArtists(c *gin.Context) {
+	artistList, err := h.services.Artist.GetAllArtists(c.Request.Context(), pageParams(c, defaultPageLimit))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get all artists")
+		handleError(c, err, "Failed to get all artists")
+		return
+	}
+
+	logrus.Info("Retrieved all artists successfully")
+
+	c.JSON(http.StatusOK, getAllArtistsResponse{
+		Data:     artistList.Items,
+		pageMeta: pageLinks(c, artistList),
+	})
+}
+
+// @Summary GetArtistById
+// @Tags artist
+// @Description Get an artist by ID
+// @ID get-artist-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Artist ID"
+// @Success 200 {object} artistResponse "Artist data"
+// @Failure 400 {object} errorResponse "Invalid artist ID"
+// @Failure 404 {object} errorResponse "Artist not found"
+// @Failure 500 {object} errorResponse "Failed to get artist"
+// @Router /api/artist/{id} [get]
+func (h *Handler) getArtistById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid artist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid artist ID")
+		return
+	}
+
+	artist, err := h.services.Artist.GetArtistById(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get artist")
+		handleError(c, err, "Failed to get artist")
+		return
+	}
+
+	c.JSON(http.StatusOK, artistResponse{
+		Data: artist,
+	})
+}
+
+// @Summary UpdateArtist
+// @Tags artist
+// @Description Update an existing artist
+// @ID update-artist
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Artist ID"
+// @Param input body musiclibrary.UpdateArtistInput true "Artist information"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Artist not found"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to update artist"
+// @Router /api/artist/{id} [put]
+func (h *Handler) updateArtist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid artist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid artist ID")
+		return
+	}
+
+	var input musiclibrary.UpdateArtistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for update artist")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	err = h.services.Artist.UpdateArtist(c.Request.Context(), id, input)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update artist")
+		handleError(c, err, "Failed to update artist")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id": id,
+	}).Info("Artist updated successfully")
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary DeleteArtist
+// @Tags artist
+// @Description Delete an existing artist together with their memberships
+// @ID delete-artist
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Artist ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid artist ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Artist not found"
+// @Failure 500 {object} errorResponse "Failed to delete artist"
+// @Router /api/artist/{id} [delete]
+func (h *Handler) deleteArtist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid artist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid artist ID")
+		return
+	}
+
+	err = h.services.Artist.DeleteArtist(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete artist")
+		handleError(c, err, "Failed to delete artist")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"artist_id": id,
+	}).Info("Artist deleted successfully")
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+type getAllArtistsResponse struct {
+	Data []musiclibrary.Artist `json:"data"`
+	pageMeta
+}
+type artistResponse struct {
+	Data musiclibrary.Artist `json:"data"`
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// userKey is where authenticate keeps the user of a request in the gin
+// context.
+const userKey = "user"
+
+// authenticate reads the bearer token of a request, if there is one, and
+// keeps its user for requireRole. A request with an invalid or expired token
+// is refused even on routes anyone may use, so the client notices.
+func (h *Handler) authenticate(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		c.Next()
+		return
+	}
+	scheme, token, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_request"`)
+		newErrorResponse(c, http.StatusUnauthorized, "Authorization must be a Bearer token")
+		c.Abort()
+		return
+	}
+	user, err := h.services.User.Authenticate(strings.TrimSpace(token))
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		handleError(c, err, "Failed to authenticate")
+		c.Abort()
+		return
+	}
+	c.Set(userKey, user)
+	c.Next()
+}
+
+// requireRole refuses requests without a token with 401 and those whose
+// user's role does not allow at least role with 403.
+func (h *Handler) requireRole(role musiclibrary.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c)
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			newErrorResponse(c, http.StatusUnauthorized, "Authentication required")
+			c.Abort()
+			return
+		}
+		if !user.Role.Allows(role) {
+			logrus.WithFields(logrus.Fields{
+				"user_id": user.Id,
+				"role":    user.Role,
+				"route":   c.Request.Method + " " + c.FullPath(),
+			}).Warn("Request refused for insufficient role")
+			newErrorResponse(c, http.StatusForbidden, "Requires the "+string(role)+" role")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// protectWrites lets anyone read and requires an editor for everything
+// else, which includes deletes that cascade.
+func (h *Handler) protectWrites(c *gin.Context) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		c.Next()
+	default:
+		h.requireRole(musiclibrary.RoleEditor)(c)
+	}
+}
+
+// currentUser returns the user authenticate found for the request.
+func currentUser(c *gin.Context) (musiclibrary.User, bool) {
+	v, ok := c.Get(userKey)
+	if !ok {
+		return musiclibrary.User{}, false
+	}
+	user, ok := v.(musiclibrary.User)
+	return user, ok
+}
+
+// revisionAuthor is the author recorded for a change to song details: the
+// user making the request, so nobody can edit in someone else's name. The
+// author the client gave is only used for requests without a user.
+func revisionAuthor(c *gin.Context, given string) string {
+	if user, ok := currentUser(c); ok {
+		return user.Name
+	}
+	return given
+}
+
+// @Summary IssueToken
+// @Tags auth
+// @Description Exchange an API key for a short-lived access token, sent as "Authorization: Bearer <token>" to routes that change the library
+// @ID issue-token
+// @Accept  json
+// @Produce  json
+// @Param input body musiclibrary.TokenInput true "API key"
+// @Success 200 {object} musiclibrary.Token "Access token"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Invalid API key"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to issue token"
+// @Router /api/auth/token [post]
+func (h *Handler) issueToken(c *gin.Context) {
+	var input musiclibrary.TokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for issue token")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	token, err := h.services.User.IssueToken(c.Request.Context(), input.APIKey)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to issue token")
+		handleError(c, err, "Failed to issue token")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
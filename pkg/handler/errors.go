@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+// problemContentType is the media type of RFC 7807 problem details.
+const problemContentType = "application/problem+json"
+
+func init() {
+	// Report invalid fields by their JSON names rather than Go field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+	}
+}
+
+// newProblem builds the problem details for status. With the default
+// about:blank type the title is the status text.
+func newProblem(c *gin.Context, status int, detail string) errorResponse {
+	return errorResponse{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+	}
+}
+
+func writeProblem(c *gin.Context, problem any, status int) {
+	body, err := json.Marshal(problem)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to encode problem details")
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, problemContentType, body)
+}
+
+// newErrorResponse answers with a problem of the given status.
+func newErrorResponse(c *gin.Context, status int, detail string) {
+	writeProblem(c, newProblem(c, status, detail), status)
+}
+
+// handleError answers with the status matching the kind of err. Domain errors
+// carry a message meant for clients, anything else is answered with 500 and
+// the given detail so internals do not leak.
+func handleError(c *gin.Context, err error, detail string) {
+	status := errorStatus(err)
+	problem := newProblem(c, status, detail)
+	var domainErr *musiclibrary.Error
+	if status != http.StatusInternalServerError {
+		problem.Detail = err.Error()
+		// Invalid fields are listed in errors rather than repeated in detail.
+		if errors.As(err, &domainErr) && len(domainErr.Fields) > 0 {
+			problem.Detail = domainErr.Message
+			problem.Errors = domainErr.Fields
+		}
+	}
+	writeProblem(c, problem, status)
+}
+
+// errorStatus maps the kinds of domain errors to HTTP statuses. Only the
+// outermost domain error counts, a missing group reported as a foreign key
+// error of the song being created is a 422 even though it wraps a NotFound.
+func errorStatus(err error) int {
+	var domainErr *musiclibrary.Error
+	if errors.As(err, &domainErr) {
+		err = domainErr.Kind
+	}
+	switch {
+	case errors.Is(err, musiclibrary.ErrNotFound), errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, musiclibrary.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, musiclibrary.ErrValidation), errors.Is(err, musiclibrary.ErrForeignKey):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, musiclibrary.ErrUnsupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, musiclibrary.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, musiclibrary.ErrForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// newBindErrorResponse answers a request whose body could not be bound. A
+// body that is not valid JSON is a 400, one that fails validation a 422
+// listing every invalid field.
+func newBindErrorResponse(c *gin.Context, err error) {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		newErrorResponse(c, http.StatusBadRequest, "Invalid input: "+err.Error())
+		return
+	}
+	problem := newProblem(c, http.StatusUnprocessableEntity, "Invalid input")
+	for _, fe := range invalid {
+		problem.Errors = append(problem.Errors, musiclibrary.FieldError{
+			Field:   fe.Field(),
+			Message: validationMessage(fe),
+		})
+	}
+	writeProblem(c, problem, http.StatusUnprocessableEntity)
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of " + fe.Param()
+	}
+	return "failed the " + fe.Tag() + " check"
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"time-tracker/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary ExportLibrary
+// @Tags export
+// @Description Stream every group, song and song detail as NDJSON, as a zip of per-entity CSV files, or as a versioned archive that /api/import can restore
+// @ID export-library
+// @Produce  octet-stream
+// @Param format query string false "Output format, defaults to ndjson" Enums(ndjson, csv, archive)
+// @Success 200 {file} file "Library export"
+// @Failure 422 {object} errorResponse "Unknown format"
+// @Failure 500 {object} errorResponse "Failed to export library"
+// @Failure 501 {object} errorResponse "Storage backend cannot export"
+// @Router /api/export [get]
+func (h *Handler) exportLibrary(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", service.ExportFormatNDJSON))
+	contentType, ext, ok := exportFile(format)
+	if !ok {
+		handleError(c, service.ErrUnknownExportFormat, "")
+		return
+	}
+
+	name := fmt.Sprintf("music-library-%s.%s", time.Now().UTC().Format("20060102-150405"), ext)
+	w := &exportWriter{c: c, contentType: contentType, name: name}
+	buf := bufio.NewWriterSize(w, exportBufferSize)
+	err := h.services.Export.Export(c.Request.Context(), format, buf)
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to export library")
+		if !w.started {
+			handleError(c, err, "Failed to export library")
+			return
+		}
+		// Part of the file is out already. Breaking the connection keeps the
+		// client from taking what it got for a complete export.
+		panic(http.ErrAbortHandler)
+	}
+	w.start()
+
+	logrus.WithField("format", format).Info("Library exported")
+}
+
+// exportBufferSize is how much of an export is held back before the response
+// is committed, errors up to then are still answered with a problem.
+const exportBufferSize = 32 << 10
+
+// exportWriter sends the headers of an export along with its first bytes.
+type exportWriter struct {
+	c           *gin.Context
+	contentType string
+	name        string
+	started     bool
+}
+
+func (w *exportWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.c.Header("Content-Type", w.contentType)
+	w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.name))
+	w.c.Status(http.StatusOK)
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.start()
+	return w.c.Writer.Write(p)
+}
+
+func exportFile(format string) (contentType, ext string, ok bool) {
+	switch format {
+	case service.ExportFormatNDJSON:
+		return "application/x-ndjson", "ndjson", true
+	case service.ExportFormatCSV:
+		return "application/zip", "zip", true
+	case service.ExportFormatArchive:
+		return "application/gzip", "ndjson.gz", true
+	}
+	return "", "", false
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary CreateGenre
+// @Tags genre
+// @Description Create a new genre, optionally as a sub-genre of parentId
+// @ID create-genre
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param input body musiclibrary.Genre true "Genre information"
+// @Success 200 {object} map[string]interface{} "Returns genre ID"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 409 {object} errorResponse "Genre already exists"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to create genre"
+// @Router /api/genre/ [post]
+func (h *Handler) createGenre(c *gin.Context) {
+	var genre musiclibrary.Genre
+	if err := c.ShouldBindJSON(&genre); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for create genre")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	id, err := h.services.Genre.CreateGenre(c.Request.Context(), genre)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create genre")
+		handleError(c, err, "Failed to create genre")
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary GetAllGenres
+// @Tags genre
+// @Description Get a flat list of all genres, the hierarchy is given by parentId
+// @ID get-all-genres
+// @Accept  json
+// @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of genres per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
+// @Success 200 {object} getAllGenresResponse "Returns a list of all genres"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 422 {object} errorResponse "Invalid cursor"
+// @Failure 500 {object} errorResponse "Failed to get all genres"
+// @Router /api/genre/ [get]
+func (h *Handler) getAllGenres(c *gin.Context) {
+	genreList, err := h.services.Genre.GetAllGenres(c.Request.Context(), pageParams(c, defaultPageLimit))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get all genres")
+		handleError(c, err, "Failed to get all genres")
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllGenresResponse{
+		Data:     genreList.Items,
+		pageMeta: pageLinks(c, genreList),
+	})
+}
+
+// @Summary DeleteGenre
+// @Tags genre
+// @Description Delete a genre, its sub-genres become top-level genres
+// @ID delete-genre
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid genre ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Genre not found"
+// @Failure 500 {object} errorResponse "Failed to delete genre"
+// @Router /api/genre/{id} [delete]
+func (h *Handler) deleteGenre(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid genre ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid genre ID")
+		return
+	}
+
+	err = h.services.Genre.DeleteGenre(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete genre")
+		handleError(c, err, "Failed to delete genre")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary GetGenres
+// @Tags genre
+// @Description Get the genres attached to a song or a group
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song or group ID"
+// @Success 200 {object} genresResponse "Returns attached genres"
+// @Failure 400 {object} errorResponse "Invalid ID"
+// @Failure 500 {object} errorResponse "Failed to get genres"
+// @Router /api/song/{id}/genres [get]
+// @Router /api/group/{id}/genres [get]
+func (h *Handler) getGenres(target musiclibrary.Taggable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logrus.WithError(err).Errorf("Invalid %s ID", target)
+			newErrorResponse(c, http.StatusBadRequest, "Invalid "+string(target)+" ID")
+			return
+		}
+
+		genres, err := h.services.Genre.GetGenres(c.Request.Context(), target, id)
+		if err != nil {
+			logrus.WithError(err).Errorf("Failed to get %s genres", target)
+			handleError(c, err, "Failed to get genres")
+			return
+		}
+
+		c.JSON(http.StatusOK, genresResponse{
+			Data: genres,
+		})
+	}
+}
+
+// @Summary AttachGenre
+// @Tags genre
+// @Description Attach a genre to a song or a group
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song or group ID"
+// @Param genreId path int true "Genre ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 422 {object} errorResponse "Genre or target does not exist"
+// @Failure 500 {object} errorResponse "Failed to attach genre"
+// @Router /api/song/{id}/genres/{genreId} [put]
+// @Router /api/group/{id}/genres/{genreId} [put]
+func (h *Handler) attachGenre(target musiclibrary.Taggable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, genreId, ok := genreParams(c, target)
+		if !ok {
+			return
+		}
+
+		if err := h.services.Genre.AttachGenre(c.Request.Context(), target, id, genreId); err != nil {
+			logrus.WithError(err).Errorf("Failed to attach genre to %s", target)
+			handleError(c, err, "Failed to attach genre")
+			return
+		}
+
+		c.JSON(http.StatusOK, statusResponse{
+			Status: "ok",
+		})
+	}
+}
+
+// @Summary DetachGenre
+// @Tags genre
+// @Description Detach a genre from a song or a group
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song or group ID"
+// @Param genreId path int true "Genre ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Genre is not attached"
+// @Failure 500 {object} errorResponse "Failed to detach genre"
+// @Router /api/song/{id}/genres/{genreId} [delete]
+// @Router /api/group/{id}/genres/{genreId} [delete]
+func (h *Handler) detachGenre(target musiclibrary.Taggable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, genreId, ok := genreParams(c, target)
+		if !ok {
+			return
+		}
+
+		if err := h.services.Genre.DetachGenre(c.Request.Context(), target, id, genreId); err != nil {
+			logrus.WithError(err).Errorf("Failed to detach genre from %s", target)
+			handleError(c, err, "Failed to detach genre")
+			return
+		}
+
+		c.JSON(http.StatusOK, statusResponse{
+			Status: "ok",
+		})
+	}
+}
+
+func genreParams(c *gin.Context, target musiclibrary.Taggable) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Errorf("Invalid %s ID", target)
+		newErrorResponse(c, http.StatusBadRequest, "Invalid "+string(target)+" ID")
+		return 0, 0, false
+	}
+	genreId, err := strconv.Atoi(c.Param("genreId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid genre ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid genre ID")
+		return 0, 0, false
+	}
+	return id, genreId, true
+}
+
+type getAllGenresResponse struct {
+	Data []musiclibrary.Genre `json:"data"`
+	pageMeta
+}
+type genresResponse struct {
+	Data []musiclibrary.Genre `json:"data"`
+}
@@ -13,25 +13,29 @@ import (
 // @Tags group
 // @Description Create a new group
 // @ID create-group
+// @Security BearerAuth
 // @Accept  json
 // @Produce  json
 // @Param input body musiclibrary.Group true "Group information"
 // @Success 200 {object} map[string]interface{} "Returns group ID"
-// @Failure 400 {object} errorResponse "Invalid input"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 422 {object} errorResponse "Invalid fields"
 // @Failure 500 {object} errorResponse "Failed to create group"
 // @Router /api/group/ [post]
 func (h *Handler) createGroup(c *gin.Context) {
 	var group musiclibrary.Group
-	if err := c.BindJSON(&group); err != nil {
+	if err := c.ShouldBindJSON(&group); err != nil {
 		logrus.WithError(err).Error("Failed to bind JSON for sign up")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		newBindErrorResponse(c, err)
 		return
 	}
 
-	id, err := h.services.Group.CreateGroup(group)
+	id, err := h.services.Group.CreateGroup(c.Request.Context(), group)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to create group")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create group"})
+		handleError(c, err, "Failed to create group")
 		return
 	}
 
@@ -50,21 +54,28 @@ func (h *Handler) createGroup(c *gin.Context) {
 // @ID get-all-groups
 // @Accept  json
 // @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of groups per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
 // @Success 200 {object} getAllGroupsResponse "Returns a list of all groups"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 422 {object} errorResponse "Invalid cursor"
 // @Failure 500 {object} errorResponse "Failed to get all groups"
 // @Router /api/group/ [get]
 func (h *Handler) getAllGroups(c *gin.Context) {
-	groupList, err := h.services.Group.GetAllGroups()
+	groupList, err := h.services.Group.GetAllGroups(c.Request.Context(), pageParams(c, defaultPageLimit))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get all groups")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get all groups"})
+		handleError(c, err, "Failed to get all groups")
 		return
 	}
 
 	logrus.Info("Retrieved all groups successfully")
 
 	c.JSON(http.StatusOK, getAllGroupsResponse{
-		Data: groupList,
+		Data:     groupList.Items,
+		pageMeta: pageLinks(c, groupList),
 	})
 }
 
@@ -72,33 +83,38 @@ func (h *Handler) getAllGroups(c *gin.Context) {
 // @Tags group
 // @Description Update an existing group
 // @ID update-group
+// @Security BearerAuth
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Group ID"
 // @Param input body musiclibrary.UpdateGroupInput true "Group information"
 // @Success 200 {object} statusResponse "Returns status of the operation"
-// @Failure 400 {object} errorResponse "Invalid input or ID"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Group not found"
+// @Failure 422 {object} errorResponse "Invalid fields"
 // @Failure 500 {object} errorResponse "Failed to update group"
 // @Router /api/group/{id} [put]
 func (h *Handler) updateGroup(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.WithError(err).Error("Invalid group ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		newErrorResponse(c, http.StatusBadRequest, "Invalid group ID")
 		return
 	}
 
 	var input musiclibrary.UpdateGroupInput
-	if err := c.BindJSON(&input); err != nil {
+	if err := c.ShouldBindJSON(&input); err != nil {
 		logrus.WithError(err).Error("Failed to bind JSON for update group")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		newBindErrorResponse(c, err)
 		return
 	}
 
-	err = h.services.Group.UpdateGroup(id, input)
+	err = h.services.Group.UpdateGroup(c.Request.Context(), id, input)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to update group")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update group"})
+		handleError(c, err, "Failed to update group")
 		return
 	}
 
@@ -111,15 +127,53 @@ func (h *Handler) updateGroup(c *gin.Context) {
 	})
 }
 
+// @Summary GetGroupById
+// @Tags group
+// @Description Get a group, include=songs embeds its songs
+// @ID get-group-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Group ID"
+// @Param include query string false "Comma-separated related resources to embed: songs"
+// @Success 200 {object} groupResponse "Group with the included resources"
+// @Failure 400 {object} errorResponse "Invalid group ID"
+// @Failure 404 {object} errorResponse "Group not found"
+// @Failure 422 {object} errorResponse "Unknown include"
+// @Failure 500 {object} errorResponse "Failed to get group"
+// @Router /api/group/{id} [get]
+func (h *Handler) getGroupById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid group ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	group, err := h.services.Group.GetGroupById(c.Request.Context(), id, includeParam(c))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get group")
+		handleError(c, err, "Failed to get group")
+		return
+	}
+
+	c.JSON(http.StatusOK, groupResponse{
+		Data: group,
+	})
+}
+
 // @Summary DeleteGroup
 // @Tags group
 // @Description Delete an existing group
 // @ID delete-group
+// @Security BearerAuth
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Group ID"
 // @Success 200 {object} statusResponse "Returns status of the operation"
 // @Failure 400 {object} errorResponse "Invalid group ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Group not found"
 // @Failure 500 {object} errorResponse "Failed to delete group"
 // @Router /api/group/{id} [delete]
 func (h *Handler) deleteGroup(c *gin.Context) {
@@ -127,14 +181,14 @@ func (h *Handler) deleteGroup(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.WithError(err).Error("Invalid group ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		newErrorResponse(c, http.StatusBadRequest, "Invalid group ID")
 		return
 	}
 
-	err = h.services.Group.DeleteGroup(id)
+	err = h.services.Group.DeleteGroup(c.Request.Context(), id)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to delete group")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete group"})
+		handleError(c, err, "Failed to delete group")
 		return
 	}
 
@@ -154,38 +208,43 @@ func (h *Handler) deleteGroup(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param groupname query string false "Group name filter"
-// @Param page query int false "Page number for pagination"
-// @Param limit query int false "Number of groups per page"
+// @Param genre query string false "Genre name, sub-genres match too"
+// @Param tag query string false "Tag name"
+// @Param match query string false "Set to fuzzy for typo-tolerant name matching ordered by similarity score" Enums(fuzzy)
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of groups per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
 // @Success 200 {object} getAllGroupsResponse "Returns a filtered list of groups"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 422 {object} errorResponse "Invalid cursor"
 // @Failure 500 {object} errorResponse "Failed to get groups"
 // @Router /api/group/filter [get]
 func (h *Handler) getGroupsWithFilter(c *gin.Context) {
 	filters := map[string]string{
 		"groupname": c.Query("groupname"),
+		"genre":     c.Query("genre"),
+		"tag":       c.Query("tag"),
+		"match":     c.Query("match"),
 	}
 
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit < 1 {
-		limit = 10
-	}
-
-	groups, err := h.services.Group.GetGroupsWithFilter(filters, page, limit)
+	groups, err := h.services.Group.GetGroupsWithFilter(c.Request.Context(), filters, pageParams(c, defaultPageLimit))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get groups with filters")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get groups"})
+		handleError(c, err, "Failed to get groups")
 		return
 	}
 
 	c.JSON(http.StatusOK, getAllGroupsResponse{
-		Data: groups,
+		Data:     groups.Items,
+		pageMeta: pageLinks(c, groups),
 	})
 }
 
 type getAllGroupsResponse struct {
 	Data []musiclibrary.Group `json:"data"`
+	pageMeta
+}
+type groupResponse struct {
+	Data musiclibrary.GroupWithRelations `json:"data"`
 }
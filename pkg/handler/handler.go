@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"strings"
+	musiclibrary "time-tracker"
 	"time-tracker/pkg/service"
 
 	_ "time-tracker/docs"
@@ -13,53 +15,193 @@ import (
 
 type Handler struct {
 	services *service.Service
+	config   Config
 }
 
-func NewHandler(services *service.Service) *Handler {
-	return &Handler{services: services}
+func NewHandler(services *service.Service, config Config) *Handler {
+	return &Handler{services: services, config: config}
 }
 
 func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
 
 	router.GET("swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.Use(h.deadline, h.authenticate)
 
 	logrus.Info("Initializing routes")
 
-	group := router.Group("/api/group")
+	group := router.Group("/api/group", h.protectWrites)
 	{
 		group.POST("/", h.createGroup)
 		group.GET("/", h.getAllGroups)
+		group.GET("/:id", h.getGroupById)
 		group.DELETE("/:id", h.deleteGroup)
 		group.PUT("/:id", h.updateGroup)
 		group.GET("/filter", h.getGroupsWithFilter)
+		group.GET("/:id/members", h.getGroupMembers)
+		group.POST("/:id/members", h.addGroupMember)
+		group.DELETE("/:id/members/:membershipId", h.removeGroupMember)
+		group.GET("/:id/genres", h.getGenres(musiclibrary.TaggableGroup))
+		group.PUT("/:id/genres/:genreId", h.attachGenre(musiclibrary.TaggableGroup))
+		group.DELETE("/:id/genres/:genreId", h.detachGenre(musiclibrary.TaggableGroup))
+		group.GET("/:id/tags", h.getTags(musiclibrary.TaggableGroup))
+		group.PUT("/:id/tags/:tag", h.attachTag(musiclibrary.TaggableGroup))
+		group.DELETE("/:id/tags/:tag", h.detachTag(musiclibrary.TaggableGroup))
 	}
 
-	song := router.Group("/api/song")
+	song := router.Group("/api/song", h.protectWrites)
 	{
 		song.POST("/", h.createSong)
 		song.GET("/", h.getAllSongs)
+		song.GET("/:id", h.getSongById)
 		song.DELETE("/:id", h.deleteSong)
 		song.PUT("/:id", h.updateSong)
 		song.GET("/filter", h.getSongsWithFilter)
+		song.GET("/:id/genres", h.getGenres(musiclibrary.TaggableSong))
+		song.PUT("/:id/genres/:genreId", h.attachGenre(musiclibrary.TaggableSong))
+		song.DELETE("/:id/genres/:genreId", h.detachGenre(musiclibrary.TaggableSong))
+		song.GET("/:id/tags", h.getTags(musiclibrary.TaggableSong))
+		song.PUT("/:id/tags/:tag", h.attachTag(musiclibrary.TaggableSong))
+		song.DELETE("/:id/tags/:tag", h.detachTag(musiclibrary.TaggableSong))
+		song.POST("/:id/refresh", h.refreshSongDetails)
+		song.GET("/:id/similar", h.getSimilarSongs)
 	}
 
-	songDetails := router.Group("/api/songDetails")
+	songDetails := router.Group("/api/songDetails", h.protectWrites)
 	{
 		songDetails.GET("/:id", h.getSongDetailsById)
 		songDetails.PUT("/:id", h.updateSongDetails)
+		songDetails.GET("/:id/revisions", h.getRevisions)
+		songDetails.GET("/:id/revisions/diff", h.diffRevisions)
+		songDetails.GET("/:id/revisions/:revision", h.getRevision)
+		songDetails.POST("/:id/revisions/:revision/rollback", h.rollbackRevision)
 	}
 
-	songText := router.Group("/api/songText")
+	songText := router.Group("/api/songText", h.protectWrites)
 	{
 		songText.GET("/:id/filter", h.getSongText)
+		songText.GET("/:id/sections", h.getSongSections)
+		songText.PUT("/:id/lrc", h.uploadLrc)
+		songText.GET("/:id/lrc", h.exportLrc)
+		songText.GET("/:id/synced", h.getSyncedLyrics)
+		songText.GET("/:id/synced/position", h.getSyncedPosition)
+		songText.GET("/:id/translations", h.getTranslations)
+		songText.POST("/:id/translations", h.createTranslation)
+		songText.GET("/:id/translations/:lang", h.getTranslation)
+		songText.PUT("/:id/translations/:lang", h.updateTranslation)
+		songText.DELETE("/:id/translations/:lang", h.deleteTranslation)
+	}
+
+	album := router.Group("/api/album", h.protectWrites)
+	{
+		album.POST("/", h.createAlbum)
+		album.GET("/", h.getAllAlbums)
+		album.GET("/:id", h.getAlbumById)
+		album.DELETE("/:id", h.deleteAlbum)
+		album.PUT("/:id", h.updateAlbum)
+		album.POST("/:id/tracks", h.addAlbumTrack)
+		album.DELETE("/:id/tracks/:songId", h.removeAlbumTrack)
+	}
+
+	artist := router.Group("/api/artist", h.protectWrites)
+	{
+		artist.POST("/", h.createArtist)
+		artist.GET("/", h.getAllArtists)
+		artist.GET("/:id", h.getArtistById)
+		artist.DELETE("/:id", h.deleteArtist)
+		artist.PUT("/:id", h.updateArtist)
+		artist.GET("/:id/groups", h.getArtistGroups)
+	}
+
+	genre := router.Group("/api/genre", h.protectWrites)
+	{
+		genre.POST("/", h.createGenre)
+		genre.GET("/", h.getAllGenres)
+		genre.DELETE("/:id", h.deleteGenre)
+	}
+
+	tag := router.Group("/api/tag", h.protectWrites)
+	{
+		tag.GET("/", h.getAllTags)
+	}
+
+	router.GET("/api/search", h.searchLyrics)
+	router.POST("/api/import", h.requireRole(musiclibrary.RoleEditor), h.importSongs)
+	router.GET("/api/export", h.exportLibrary)
+
+	jobs := router.Group("/api/jobs", h.protectWrites)
+	{
+		jobs.GET("/", h.getJobs)
+		jobs.GET("/:id", h.getJobById)
+		jobs.POST("/:id/retry", h.retryJob)
+	}
+
+	router.POST("/api/auth/token", h.issueToken)
+
+	me := router.Group("/api/me", h.requireRole(musiclibrary.RoleViewer))
+	{
+		me.GET("/favorites", h.getFavorites)
+		me.PUT("/favorites/:songId", h.addFavorite)
+		me.DELETE("/favorites/:songId", h.removeFavorite)
+		me.GET("/ratings", h.getRatings)
+		me.PUT("/ratings/:songId", h.rateSong)
+		me.DELETE("/ratings/:songId", h.removeRating)
+	}
+
+	playlists := router.Group("/api/playlists", h.requireRole(musiclibrary.RoleViewer))
+	{
+		playlists.POST("/", h.createPlaylist)
+		playlists.GET("/", h.getPlaylists)
+		playlists.POST("/import", h.importPlaylist)
+		playlists.GET("/:id", h.getPlaylistById)
+		playlists.PUT("/:id", h.updatePlaylist)
+		playlists.DELETE("/:id", h.deletePlaylist)
+		playlists.POST("/:id/items", h.addPlaylistItem)
+		playlists.PUT("/:id/items", h.reorderPlaylist)
+		playlists.DELETE("/:id/items/:itemId", h.removePlaylistItem)
+		playlists.GET("/:id/export", h.exportPlaylist)
+	}
+
+	router.POST("/api/plays", h.requireRole(musiclibrary.RoleViewer), h.recordPlays)
+
+	stats := router.Group("/api/stats")
+	{
+		stats.GET("/top-songs", h.getTopSongs)
+		stats.GET("/top-groups", h.getTopGroups)
+		stats.GET("/plays-per-day", h.getPlaysPerDay)
+	}
+
+	users := router.Group("/api/users", h.requireRole(musiclibrary.RoleAdmin))
+	{
+		users.POST("/", h.createUser)
+		users.GET("/", h.getUsers)
+		users.POST("/:id/keys", h.createAPIKey)
+		users.GET("/:id/keys", h.getAPIKeys)
+		users.DELETE("/:id/keys/:keyId", h.deleteAPIKey)
 	}
 	logrus.Info("Routes initialized successfully")
 	return router
 }
 
+// includeParam splits the include query parameter, e.g. include=group,details,
+// into the names of the related resources to embed.
+func includeParam(c *gin.Context) []string {
+	if include := c.Query("include"); include != "" {
+		return strings.Split(include, ",")
+	}
+	return nil
+}
+
+// errorResponse is an RFC 7807 problem details object, sent with the
+// application/problem+json content type. Errors lists the invalid fields of
+// a 422 response.
 type errorResponse struct {
-	Message string `json:"message"`
+	Type     string                    `json:"type" example:"about:blank"`
+	Title    string                    `json:"title" example:"Not Found"`
+	Status   int                       `json:"status" example:"404"`
+	Detail   string                    `json:"detail,omitempty" example:"group not found"`
+	Instance string                    `json:"instance,omitempty" example:"/api/group/42"`
+	Errors   []musiclibrary.FieldError `json:"errors,omitempty"`
 }
 type statusResponse struct {
 	Status string `json:"status"`
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const maxImportSize = 64 << 20 //64MB
+
+// @Summary ImportSongs
+// @Tags import
+// @Description Bulk import songs from CSV (group,song,releaseDate,link,text), NDJSON or an archive written by /api/export. Missing groups are created, existing songs are updated or skipped, everything runs in one transaction
+// @ID import-songs
+// @Security BearerAuth
+// @Accept  plain
+// @Produce  json
+// @Param format query string false "Input format, defaults to the request Content-Type" Enums(csv, ndjson, archive)
+// @Param dryRun query bool false "Only report what would happen, nothing is written"
+// @Param input body string true "CSV or NDJSON rows, or an export archive"
+// @Success 200 {object} importResponse "Per-row import report"
+// @Failure 400 {object} errorResponse "Unreadable body"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 413 {object} errorResponse "Import is too large"
+// @Failure 422 {object} errorResponse "Unknown format or invalid archive"
+// @Failure 500 {object} errorResponse "Failed to import songs"
+// @Router /api/import [post]
+func (h *Handler) importSongs(c *gin.Context) {
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = importFormat(c.ContentType())
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	report, err := h.services.Import.Import(c.Request.Context(), format, body, dryRun)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		newErrorResponse(c, http.StatusRequestEntityTooLarge, "Import is too large")
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to import songs")
+		handleError(c, err, "Failed to import songs")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"dry_run": dryRun,
+		"created": report.Created,
+		"updated": report.Updated,
+		"skipped": report.Skipped,
+		"errors":  report.Errors,
+	}).Info("Songs imported")
+
+	c.JSON(http.StatusOK, importResponse{
+		Data: report,
+	})
+}
+
+func importFormat(contentType string) string {
+	switch contentType {
+	case "text/csv":
+		return service.ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return service.ImportFormatNDJSON
+	case "application/gzip", "application/x-gzip":
+		return service.ImportFormatArchive
+	}
+	return ""
+}
+
+type importResponse struct {
+	Data musiclibrary.ImportReport `json:"data"`
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary GetJobs
+// @Tags job
+// @Description List background jobs, newest first
+// @ID get-jobs
+// @Accept  json
+// @Produce  json
+// @Param status query string false "Only jobs in this status" Enums(queued, running, done, dead)
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of jobs per page, at most 100" default(20)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
+// @Success 200 {object} getJobsResponse "List of jobs"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 422 {object} errorResponse "Unknown status"
+// @Failure 500 {object} errorResponse "Failed to get jobs"
+// @Router /api/jobs/ [get]
+func (h *Handler) getJobs(c *gin.Context) {
+	jobs, err := h.services.Job.GetJobs(c.Request.Context(), c.Query("status"), pageParams(c, 20))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get jobs")
+		handleError(c, err, "Failed to get jobs")
+		return
+	}
+
+	c.JSON(http.StatusOK, getJobsResponse{
+		Data:     jobs.Items,
+		pageMeta: pageLinks(c, jobs),
+	})
+}
+
+// @Summary GetJobById
+// @Tags job
+// @Description Get a background job with its attempts and last error
+// @ID get-job-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Job ID"
+// @Success 200 {object} jobResponse "Job data"
+// @Failure 400 {object} errorResponse "Invalid job ID"
+// @Failure 404 {object} errorResponse "Job not found"
+// @Failure 500 {object} errorResponse "Failed to get job"
+// @Router /api/jobs/{id} [get]
+func (h *Handler) getJobById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid job ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	job, err := h.services.Job.GetJobById(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get job")
+		handleError(c, err, "Failed to get job")
+		return
+	}
+
+	c.JSON(http.StatusOK, jobResponse{
+		Data: job,
+	})
+}
+
+// @Summary RetryJob
+// @Tags job
+// @Description Queue a dead job again with a fresh set of attempts
+// @ID retry-job
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Job ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid job ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Job not found"
+// @Failure 409 {object} errorResponse "Job is not dead"
+// @Failure 500 {object} errorResponse "Failed to retry job"
+// @Router /api/jobs/{id}/retry [post]
+func (h *Handler) retryJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid job ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	err = h.services.Job.RetryJob(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to retry job")
+		handleError(c, err, "Failed to retry job")
+		return
+	}
+
+	logrus.WithField("job_id", id).Info("Job queued for retry")
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary RefreshSongDetails
+// @Tags song
+// @Description Queue a job that fills in the song details from the song info API
+// @ID refresh-song-details
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Success 202 {object} map[string]interface{} "Returns job ID"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 503 {object} errorResponse "Song info API is not configured"
+// @Failure 500 {object} errorResponse "Failed to queue refresh"
+// @Router /api/song/{id}/refresh [post]
+func (h *Handler) refreshSongDetails(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	jobId, err := h.services.Job.EnqueueRefreshSongDetails(c.Request.Context(), id)
+	if errors.Is(err, service.ErrSongInfoDisabled) {
+		newErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to queue song details refresh")
+		handleError(c, err, "Failed to queue refresh")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, map[string]interface{}{
+		"jobId": jobId,
+	})
+}
+
+type getJobsResponse struct {
+	Data []musiclibrary.Job `json:"data"`
+	pageMeta
+}
+type jobResponse struct {
+	Data musiclibrary.Job `json:"data"`
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary GetFavorites
+// @Tags me
+// @Description List the songs I starred
+// @ID get-favorites
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of favorites per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Success 200 {object} favoritesResponse "Favorites ordered by song ID"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 422 {object} errorResponse "Invalid cursor"
+// @Failure 500 {object} errorResponse "Failed to get favorites"
+// @Router /api/me/favorites [get]
+func (h *Handler) getFavorites(c *gin.Context) {
+	user, _ := currentUser(c)
+	favorites, err := h.services.Library.GetFavorites(c.Request.Context(), user.Id, pageParams(c, defaultPageLimit))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get favorites")
+		handleError(c, err, "Failed to get favorites")
+		return
+	}
+
+	c.JSON(http.StatusOK, favoritesResponse{
+		Data:     favorites.Items,
+		pageMeta: pageLinks(c, favorites),
+	})
+}
+
+// @Summary AddFavorite
+// @Tags me
+// @Description Star a song. Starring it again changes nothing
+// @ID add-favorite
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param songId path int true "Song ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 422 {object} errorResponse "Song does not exist"
+// @Failure 500 {object} errorResponse "Failed to add favorite"
+// @Router /api/me/favorites/{songId} [put]
+func (h *Handler) addFavorite(c *gin.Context) {
+	songId, err := strconv.Atoi(c.Param("songId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	user, _ := currentUser(c)
+	if err := h.services.Library.AddFavorite(c.Request.Context(), user.Id, songId); err != nil {
+		logrus.WithError(err).Error("Failed to add favorite")
+		handleError(c, err, "Failed to add favorite")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary RemoveFavorite
+// @Tags me
+// @Description Unstar a song
+// @ID remove-favorite
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param songId path int true "Song ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Song is not a favorite"
+// @Failure 500 {object} errorResponse "Failed to remove favorite"
+// @Router /api/me/favorites/{songId} [delete]
+func (h *Handler) removeFavorite(c *gin.Context) {
+	songId, err := strconv.Atoi(c.Param("songId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	user, _ := currentUser(c)
+	if err := h.services.Library.RemoveFavorite(c.Request.Context(), user.Id, songId); err != nil {
+		logrus.WithError(err).Error("Failed to remove favorite")
+		handleError(c, err, "Failed to remove favorite")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary GetRatings
+// @Tags me
+// @Description List the songs I rated
+// @ID get-ratings
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of ratings per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Success 200 {object} ratingsResponse "Ratings ordered by song ID"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 422 {object} errorResponse "Invalid cursor"
+// @Failure 500 {object} errorResponse "Failed to get ratings"
+// @Router /api/me/ratings [get]
+func (h *Handler) getRatings(c *gin.Context) {
+	user, _ := currentUser(c)
+	ratings, err := h.services.Library.GetRatings(c.Request.Context(), user.Id, pageParams(c, defaultPageLimit))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get ratings")
+		handleError(c, err, "Failed to get ratings")
+		return
+	}
+
+	c.JSON(http.StatusOK, ratingsResponse{
+		Data:     ratings.Items,
+		pageMeta: pageLinks(c, ratings),
+	})
+}
+
+// @Summary RateSong
+// @Tags me
+// @Description Rate a song from 1 to 5, replacing my earlier rating of it
+// @ID rate-song
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param songId path int true "Song ID"
+// @Param input body musiclibrary.RatingInput true "Rating"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid song ID or malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 422 {object} errorResponse "Rating out of range or song does not exist"
+// @Failure 500 {object} errorResponse "Failed to rate song"
+// @Router /api/me/ratings/{songId} [put]
+func (h *Handler) rateSong(c *gin.Context) {
+	songId, err := strconv.Atoi(c.Param("songId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	var input musiclibrary.RatingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for rate song")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	user, _ := currentUser(c)
+	if err := h.services.Library.RateSong(c.Request.Context(), user.Id, songId, input); err != nil {
+		logrus.WithError(err).Error("Failed to rate song")
+		handleError(c, err, "Failed to rate song")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary RemoveRating
+// @Tags me
+// @Description Withdraw my rating of a song
+// @ID remove-rating
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param songId path int true "Song ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Song is not rated"
+// @Failure 500 {object} errorResponse "Failed to remove rating"
+// @Router /api/me/ratings/{songId} [delete]
+func (h *Handler) removeRating(c *gin.Context) {
+	songId, err := strconv.Atoi(c.Param("songId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	user, _ := currentUser(c)
+	if err := h.services.Library.RemoveRating(c.Request.Context(), user.Id, songId); err != nil {
+		logrus.WithError(err).Error("Failed to remove rating")
+		handleError(c, err, "Failed to remove rating")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+type favoritesResponse struct {
+	Data []musiclibrary.Favorite `json:"data"`
+	pageMeta
+}
+type ratingsResponse struct {
+	Data []musiclibrary.Rating `json:"data"`
+	pageMeta
+}
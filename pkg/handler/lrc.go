@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const maxLrcSize = 1 << 20 //1MB
+
+// @Summary UploadLrc
+// @Tags songDetails
+// @Description Upload time-synced lyrics in LRC format, word-level enhanced LRC is supported
+// @ID upload-lrc
+// @Security BearerAuth
+// @Accept  plain
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param input body string true "LRC file contents"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid song ID or unreadable body"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 422 {object} errorResponse "LRC has no timestamped lines or the song does not exist"
+// @Failure 500 {object} errorResponse "Failed to save LRC"
+// @Router /api/songText/{id}/lrc [put]
+func (h *Handler) uploadLrc(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxLrcSize))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to read LRC body")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	err = h.services.SongDetails.UploadLrc(c.Request.Context(), id, string(body))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to save LRC")
+		handleError(c, err, "Failed to save LRC")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id": id,
+	}).Info("LRC uploaded successfully")
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary ExportLrc
+// @Tags songDetails
+// @Description Export the time-synced lyrics of a song as an LRC file
+// @ID export-lrc
+// @Produce  plain
+// @Param id path int true "Song ID"
+// @Param enhanced query bool false "Keep word-level timestamps"
+// @Success 200 {string} string "LRC file contents"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 404 {object} errorResponse "Song has no synced lyrics"
+// @Failure 500 {object} errorResponse "Failed to export LRC"
+// @Router /api/songText/{id}/lrc [get]
+func (h *Handler) exportLrc(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+	enhanced, _ := strconv.ParseBool(c.Query("enhanced"))
+
+	lrc, err := h.services.SongDetails.ExportLrc(c.Request.Context(), id, enhanced)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to export LRC")
+		handleError(c, err, "Failed to export LRC")
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=\""+strconv.Itoa(id)+".lrc\"")
+	c.String(http.StatusOK, lrc)
+}
+
+// @Summary GetSyncedLyrics
+// @Tags songDetails
+// @Description Get the parsed time-synced lyrics of a song
+// @ID get-synced-lyrics
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Success 200 {object} syncedLyricsResponse "Lines sorted by time"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 404 {object} errorResponse "Song has no synced lyrics"
+// @Failure 500 {object} errorResponse "Failed to get synced lyrics"
+// @Router /api/songText/{id}/synced [get]
+func (h *Handler) getSyncedLyrics(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	lyrics, err := h.services.SongDetails.GetSyncedLyrics(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get synced lyrics")
+		handleError(c, err, "Failed to get synced lyrics")
+		return
+	}
+
+	c.JSON(http.StatusOK, syncedLyricsResponse{
+		Data: lyrics,
+	})
+}
+
+// @Summary GetSyncedPosition
+// @Tags songDetails
+// @Description Get the lyric line active at a playback position and the lines that follow it
+// @ID get-synced-position
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param at query string true "Playback position, mm:ss.xx or seconds" example(01:23.5)
+// @Param next query int false "Number of upcoming lines" default(3)
+// @Success 200 {object} syncedPositionResponse "Active and upcoming lines"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 422 {object} errorResponse "Invalid position"
+// @Failure 404 {object} errorResponse "Song has no synced lyrics"
+// @Failure 500 {object} errorResponse "Failed to get synced lyrics"
+// @Router /api/songText/{id}/synced/position [get]
+func (h *Handler) getSyncedPosition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	next, err := strconv.Atoi(c.DefaultQuery("next", "3"))
+	if err != nil || next < 0 {
+		next = 3
+	}
+
+	position, err := h.services.SongDetails.GetSyncedPosition(c.Request.Context(), id, c.Query("at"), next)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get synced lyrics")
+		handleError(c, err, "Failed to get synced lyrics")
+		return
+	}
+
+	c.JSON(http.StatusOK, syncedPositionResponse{
+		Data: position,
+	})
+}
+
+type syncedLyricsResponse struct {
+	Data musiclibrary.SyncedLyrics `json:"data"`
+}
+type syncedPositionResponse struct {
+	Data musiclibrary.SyncedPosition `json:"data"`
+}
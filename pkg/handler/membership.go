@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary GetGroupMembers
+// @Tags membership
+// @Description Get the members of a group, optionally only those active at a given year, month or day
+// @ID get-group-members
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Group ID"
+// @Param at query string false "YYYY, YYYY-MM or YYYY-MM-DD"
+// @Success 200 {object} membershipsResponse "Returns group members"
+// @Failure 400 {object} errorResponse "Invalid group ID"
+// @Failure 422 {object} errorResponse "Invalid date"
+// @Failure 500 {object} errorResponse "Failed to get group members"
+// @Router /api/group/{id}/members [get]
+func (h *Handler) getGroupMembers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid group ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	members, err := h.services.Membership.GetGroupMembers(c.Request.Context(), id, c.Query("at"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get group members")
+		handleError(c, err, "Failed to get group members")
+		return
+	}
+
+	c.JSON(http.StatusOK, membershipsResponse{
+		Data: members,
+	})
+}
+
+// @Summary AddGroupMember
+// @Tags membership
+// @Description Record that an artist played in a group
+// @ID add-group-member
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Group ID"
+// @Param input body musiclibrary.MembershipInput true "Membership information"
+// @Success 200 {object} map[string]interface{} "Returns membership ID"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 422 {object} errorResponse "Invalid fields, group or artist"
+// @Failure 500 {object} errorResponse "Failed to add group member"
+// @Router /api/group/{id}/members [post]
+func (h *Handler) addGroupMember(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid group ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	var input musiclibrary.MembershipInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for add group member")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	membershipId, err := h.services.Membership.AddMember(c.Request.Context(), id, input)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add group member")
+		handleError(c, err, "Failed to add group member")
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": membershipId,
+	})
+}
+
+// @Summary RemoveGroupMember
+// @Tags membership
+// @Description Remove a membership record from a group
+// @ID remove-group-member
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Group ID"
+// @Param membershipId path int true "Membership ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid group or membership ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Membership not found"
+// @Failure 500 {object} errorResponse "Failed to remove group member"
+// @Router /api/group/{id}/members/{membershipId} [delete]
+func (h *Handler) removeGroupMember(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid group ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	membershipId, err := strconv.Atoi(c.Param("membershipId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid membership ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid membership ID")
+		return
+	}
+
+	err = h.services.Membership.RemoveMember(c.Request.Context(), id, membershipId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove group member")
+		handleError(c, err, "Failed to remove group member")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary GetArtistGroups
+// @Tags membership
+// @Description Get every group an artist has played in, with roles and dates
+// @ID get-artist-groups
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Artist ID"
+// @Success 200 {object} membershipsResponse "Returns artist memberships"
+// @Failure 400 {object} errorResponse "Invalid artist ID"
+// @Failure 500 {object} errorResponse "Failed to get artist groups"
+// @Router /api/artist/{id}/groups [get]
+func (h *Handler) getArtistGroups(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid artist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid artist ID")
+		return
+	}
+
+	memberships, err := h.services.Membership.GetArtistGroups(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get artist groups")
+		handleError(c, err, "Failed to get artist groups")
+		return
+	}
+
+	c.JSON(http.StatusOK, membershipsResponse{
+		Data: memberships,
+	})
+}
+
+type membershipsResponse struct {
+	Data []musiclibrary.Membership `json:"data"`
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 10
+	// maxPageLimit caps limit so a single request cannot read a whole table.
+	maxPageLimit = 100
+)
+
+// pageParams reads the pagination query parameters of a list: after, the
+// cursor returned as next by the previous page, limit, capped at
+// maxPageLimit, and total=true to count the whole list. The deprecated page
+// parameter still works and skips (page-1)*limit rows after the cursor.
+func pageParams(c *gin.Context, defaultLimit int) musiclibrary.PageRequest {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultLimit
+	}
+	limit = min(limit, maxPageLimit)
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	total, _ := strconv.ParseBool(c.Query("total"))
+	return musiclibrary.PageRequest{
+		After:     c.Query("after"),
+		Limit:     limit,
+		Offset:    (page - 1) * limit,
+		WithTotal: total,
+	}
+}
+
+// pageLinks sets the RFC 5988 Link header of a page, pointing at the first
+// page and, unless this is the last one, the next, and returns the cursor
+// and count for the response body.
+func pageLinks[T any](c *gin.Context, page musiclibrary.Page[T]) pageMeta {
+	links := []string{pageLink(c, "", "first")}
+	if page.Next != "" {
+		links = append(links, pageLink(c, page.Next, "next"))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+	return pageMeta{Next: page.Next, Total: page.Total}
+}
+
+// pageLink is the request URL with its cursor replaced by after.
+func pageLink(c *gin.Context, after, rel string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("page")
+	query.Del("after")
+	if after != "" {
+		query.Set("after", after)
+	}
+	u.RawQuery = query.Encode()
+	return "<" + u.RequestURI() + `>; rel="` + rel + `"`
+}
+
+// pageMeta is embedded in list responses. Next is the cursor to pass as
+// after for the following page, left out on the last one. Total is only
+// set when asked for with total=true.
+type pageMeta struct {
+	Next  string `json:"next,omitempty" example:"WzQyXQ"`
+	Total *int   `json:"total,omitempty" example:"137"`
+}
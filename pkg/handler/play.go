@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const maxPlaysSize = 1 << 20 //1MB
+
+// @Summary RecordPlays
+// @Tags plays
+// @Description Report a play, or an array of up to 1000 plays saved while offline. A play counts as a scrobble once half the song or 4 minutes were listened to, whichever comes first; without songDuration only the 4 minutes count. Plays of the same song at the same time are recorded once, so a batch can safely be sent again
+// @ID record-plays
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param input body musiclibrary.PlayInput true "A play, or an array of them"
+// @Success 200 {object} playReportResponse "Per-play report"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 413 {object} errorResponse "Submission is too large"
+// @Failure 422 {object} errorResponse "Too many plays"
+// @Failure 500 {object} errorResponse "Failed to record plays"
+// @Router /api/plays [post]
+func (h *Handler) recordPlays(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPlaysSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		newErrorResponse(c, http.StatusRequestEntityTooLarge, "Submission is too large")
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to read plays")
+		newErrorResponse(c, http.StatusBadRequest, "Unreadable body")
+		return
+	}
+
+	var inputs []musiclibrary.PlayInput
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		err = json.Unmarshal(body, &inputs)
+	} else {
+		var input musiclibrary.PlayInput
+		err = json.Unmarshal(body, &input)
+		inputs = append(inputs, input)
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for record plays")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	user, _ := currentUser(c)
+	report, err := h.services.Play.RecordPlays(c.Request.Context(), user.Id, inputs)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to record plays")
+		handleError(c, err, "Failed to record plays")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"recorded":   report.Recorded,
+		"scrobbles":  report.Scrobbles,
+		"duplicates": report.Duplicates,
+		"errors":     report.Errors,
+	}).Info("Plays recorded")
+
+	c.JSON(http.StatusOK, playReportResponse{
+		Data: report,
+	})
+}
+
+// @Summary GetTopSongs
+// @Tags stats
+// @Description The most scrobbled songs of a time window, by everyone or only me
+// @ID get-top-songs
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Start of the window, a date or RFC 3339 timestamp, defaults to 30 days before to"
+// @Param to query string false "End of the window, not included, a date or RFC 3339 timestamp, defaults to now"
+// @Param limit query int false "Number of songs, at most 100" default(10)
+// @Param mine query bool false "Set to true to count only my plays, needs a token"
+// @Success 200 {object} topSongsResponse "Songs by number of scrobbles"
+// @Failure 401 {object} errorResponse "My plays asked for without a token"
+// @Failure 422 {object} errorResponse "Invalid window or limit"
+// @Failure 500 {object} errorResponse "Failed to get top songs"
+// @Router /api/stats/top-songs [get]
+func (h *Handler) getTopSongs(c *gin.Context) {
+	query, ok := statsQuery(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.services.Play.GetTopSongs(c.Request.Context(), query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get top songs")
+		handleError(c, err, "Failed to get top songs")
+		return
+	}
+
+	c.JSON(http.StatusOK, topSongsResponse{
+		Data: stats,
+	})
+}
+
+// @Summary GetTopGroups
+// @Tags stats
+// @Description The most scrobbled groups of a time window, by everyone or only me
+// @ID get-top-groups
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Start of the window, a date or RFC 3339 timestamp, defaults to 30 days before to"
+// @Param to query string false "End of the window, not included, a date or RFC 3339 timestamp, defaults to now"
+// @Param limit query int false "Number of groups, at most 100" default(10)
+// @Param mine query bool false "Set to true to count only my plays, needs a token"
+// @Success 200 {object} topGroupsResponse "Groups by number of scrobbles"
+// @Failure 401 {object} errorResponse "My plays asked for without a token"
+// @Failure 422 {object} errorResponse "Invalid window or limit"
+// @Failure 500 {object} errorResponse "Failed to get top groups"
+// @Router /api/stats/top-groups [get]
+func (h *Handler) getTopGroups(c *gin.Context) {
+	query, ok := statsQuery(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.services.Play.GetTopGroups(c.Request.Context(), query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get top groups")
+		handleError(c, err, "Failed to get top groups")
+		return
+	}
+
+	c.JSON(http.StatusOK, topGroupsResponse{
+		Data: stats,
+	})
+}
+
+// @Summary GetPlaysPerDay
+// @Tags stats
+// @Description Scrobbles of each day of a time window of at most 366 days, in UTC, by everyone or only me. Days without any are listed with 0
+// @ID get-plays-per-day
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Start of the window, a date or RFC 3339 timestamp, defaults to 30 days before to"
+// @Param to query string false "End of the window, not included, a date or RFC 3339 timestamp, defaults to now"
+// @Param mine query bool false "Set to true to count only my plays, needs a token"
+// @Success 200 {object} playsPerDayResponse "Days in order"
+// @Failure 401 {object} errorResponse "My plays asked for without a token"
+// @Failure 422 {object} errorResponse "Invalid window"
+// @Failure 500 {object} errorResponse "Failed to get plays per day"
+// @Router /api/stats/plays-per-day [get]
+func (h *Handler) getPlaysPerDay(c *gin.Context) {
+	query, ok := statsQuery(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.services.Play.GetPlaysPerDay(c.Request.Context(), query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get plays per day")
+		handleError(c, err, "Failed to get plays per day")
+		return
+	}
+
+	c.JSON(http.StatusOK, playsPerDayResponse{
+		Data: stats,
+	})
+}
+
+// statsQuery reads the window, limit and mine parameters of the statistics
+// endpoints. It answers the request itself when they are invalid.
+func statsQuery(c *gin.Context) (musiclibrary.StatsQuery, bool) {
+	query := musiclibrary.StatsQuery{From: c.Query("from"), To: c.Query("to")}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit == 0 {
+			handleError(c, musiclibrary.NewValidationError("limit", "must be a positive number"), "")
+			return query, false
+		}
+		query.Limit = limit
+	}
+	if mine, _ := strconv.ParseBool(c.Query("mine")); mine {
+		user, ok := currentUser(c)
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			newErrorResponse(c, http.StatusUnauthorized, "Authentication required for my plays")
+			return query, false
+		}
+		query.UserId = user.Id
+	}
+	return query, true
+}
+
+type playReportResponse struct {
+	Data musiclibrary.PlayReport `json:"data"`
+}
+type topSongsResponse struct {
+	Data []musiclibrary.SongStat `json:"data"`
+}
+type topGroupsResponse struct {
+	Data []musiclibrary.GroupStat `json:"data"`
+}
+type playsPerDayResponse struct {
+	Data []musiclibrary.DayStat `json:"data"`
+}
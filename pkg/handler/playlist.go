@@ -0,0 +1,449 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const maxPlaylistSize = 8 << 20 //8MB
+
+// @Summary CreatePlaylist
+// @Tags playlist
+// @Description Create a playlist of my own
+// @ID create-playlist
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param input body musiclibrary.Playlist true "Playlist name and description"
+// @Success 200 {object} map[string]interface{} "Returns playlist ID"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to create playlist"
+// @Router /api/playlists/ [post]
+func (h *Handler) createPlaylist(c *gin.Context) {
+	var playlist musiclibrary.Playlist
+	if err := c.ShouldBindJSON(&playlist); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for create playlist")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	user, _ := currentUser(c)
+	id, err := h.services.Playlist.CreatePlaylist(c.Request.Context(), user.Id, playlist)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create playlist")
+		handleError(c, err, "Failed to create playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary GetPlaylists
+// @Tags playlist
+// @Description List my playlists
+// @ID get-playlists
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of playlists per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Success 200 {object} playlistsResponse "Playlists without their items"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 422 {object} errorResponse "Invalid cursor"
+// @Failure 500 {object} errorResponse "Failed to get playlists"
+// @Router /api/playlists/ [get]
+func (h *Handler) getPlaylists(c *gin.Context) {
+	user, _ := currentUser(c)
+	playlists, err := h.services.Playlist.GetPlaylists(c.Request.Context(), user.Id, pageParams(c, defaultPageLimit))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get playlists")
+		handleError(c, err, "Failed to get playlists")
+		return
+	}
+
+	c.JSON(http.StatusOK, playlistsResponse{
+		Data:     playlists.Items,
+		pageMeta: pageLinks(c, playlists),
+	})
+}
+
+// @Summary GetPlaylistById
+// @Tags playlist
+// @Description Get one of my playlists with its items in order
+// @ID get-playlist-by-id
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} musiclibrary.PlaylistWithItems "Playlist"
+// @Failure 400 {object} errorResponse "Invalid playlist ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Playlist not found"
+// @Failure 500 {object} errorResponse "Failed to get playlist"
+// @Router /api/playlists/{id} [get]
+func (h *Handler) getPlaylistById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid playlist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	user, _ := currentUser(c)
+	playlist, err := h.services.Playlist.GetPlaylistById(c.Request.Context(), user.Id, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get playlist")
+		handleError(c, err, "Failed to get playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// @Summary UpdatePlaylist
+// @Tags playlist
+// @Description Rename one of my playlists or change its description
+// @ID update-playlist
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Playlist ID"
+// @Param input body musiclibrary.UpdatePlaylistInput true "Playlist information"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Playlist not found"
+// @Failure 500 {object} errorResponse "Failed to update playlist"
+// @Router /api/playlists/{id} [put]
+func (h *Handler) updatePlaylist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid playlist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	var input musiclibrary.UpdatePlaylistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for update playlist")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	user, _ := currentUser(c)
+	if err := h.services.Playlist.UpdatePlaylist(c.Request.Context(), user.Id, id, input); err != nil {
+		logrus.WithError(err).Error("Failed to update playlist")
+		handleError(c, err, "Failed to update playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary DeletePlaylist
+// @Tags playlist
+// @Description Delete one of my playlists
+// @ID delete-playlist
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid playlist ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Playlist not found"
+// @Failure 500 {object} errorResponse "Failed to delete playlist"
+// @Router /api/playlists/{id} [delete]
+func (h *Handler) deletePlaylist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid playlist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	user, _ := currentUser(c)
+	if err := h.services.Playlist.DeletePlaylist(c.Request.Context(), user.Id, id); err != nil {
+		logrus.WithError(err).Error("Failed to delete playlist")
+		handleError(c, err, "Failed to delete playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary AddPlaylistItem
+// @Tags playlist
+// @Description Add a song to one of my playlists at a position, moving the items from there on down, or at the end without one
+// @ID add-playlist-item
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Playlist ID"
+// @Param input body musiclibrary.PlaylistItemInput true "Song and position"
+// @Success 200 {object} map[string]interface{} "Returns playlist item ID"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Playlist not found"
+// @Failure 422 {object} errorResponse "Position out of range or song does not exist"
+// @Failure 500 {object} errorResponse "Failed to add playlist item"
+// @Router /api/playlists/{id}/items [post]
+func (h *Handler) addPlaylistItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid playlist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	var input musiclibrary.PlaylistItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for add playlist item")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	user, _ := currentUser(c)
+	itemId, err := h.services.Playlist.AddPlaylistItem(c.Request.Context(), user.Id, id, input)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add playlist item")
+		handleError(c, err, "Failed to add playlist item")
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": itemId,
+	})
+}
+
+// @Summary RemovePlaylistItem
+// @Tags playlist
+// @Description Remove an item from one of my playlists, moving the items after it up
+// @ID remove-playlist-item
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Playlist ID"
+// @Param itemId path int true "Playlist item ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid playlist or item ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Playlist or item not found"
+// @Failure 500 {object} errorResponse "Failed to remove playlist item"
+// @Router /api/playlists/{id}/items/{itemId} [delete]
+func (h *Handler) removePlaylistItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid playlist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid playlist item ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid playlist item ID")
+		return
+	}
+
+	user, _ := currentUser(c)
+	if err := h.services.Playlist.RemovePlaylistItem(c.Request.Context(), user.Id, id, itemId); err != nil {
+		logrus.WithError(err).Error("Failed to remove playlist item")
+		handleError(c, err, "Failed to remove playlist item")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary ReorderPlaylist
+// @Tags playlist
+// @Description Put the items of one of my playlists in a new order, given as the IDs of all of them
+// @ID reorder-playlist
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Playlist ID"
+// @Param input body musiclibrary.ReorderPlaylistInput true "Item IDs in the new order"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Playlist not found"
+// @Failure 422 {object} errorResponse "Item IDs do not list every item once"
+// @Failure 500 {object} errorResponse "Failed to reorder playlist"
+// @Router /api/playlists/{id}/items [put]
+func (h *Handler) reorderPlaylist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid playlist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	var input musiclibrary.ReorderPlaylistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for reorder playlist")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	user, _ := currentUser(c)
+	if err := h.services.Playlist.ReorderPlaylist(c.Request.Context(), user.Id, id, input); err != nil {
+		logrus.WithError(err).Error("Failed to reorder playlist")
+		handleError(c, err, "Failed to reorder playlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary ExportPlaylist
+// @Tags playlist
+// @Description Download one of my playlists as extended M3U8, XSPF or JSPF, with the links of its songs as their locations
+// @ID export-playlist
+// @Security BearerAuth
+// @Produce  octet-stream
+// @Param id path int true "Playlist ID"
+// @Param format query string false "Output format, defaults to m3u8" Enums(m3u8, xspf, jspf)
+// @Success 200 {file} file "Playlist file"
+// @Failure 400 {object} errorResponse "Invalid playlist ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 404 {object} errorResponse "Playlist not found"
+// @Failure 422 {object} errorResponse "Unknown format"
+// @Failure 500 {object} errorResponse "Failed to export playlist"
+// @Router /api/playlists/{id}/export [get]
+func (h *Handler) exportPlaylist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid playlist ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", service.PlaylistFormatM3U8))
+	contentType, ok := playlistFileType(format)
+	if !ok {
+		handleError(c, service.ErrUnknownPlaylistFormat, "")
+		return
+	}
+
+	// Playlists are small enough to write out whole, so a missing playlist
+	// is still answered with a 404 rather than a cut off file.
+	var file bytes.Buffer
+	user, _ := currentUser(c)
+	if err := h.services.Playlist.ExportPlaylist(c.Request.Context(), user.Id, id, format, &file); err != nil {
+		logrus.WithError(err).Error("Failed to export playlist")
+		handleError(c, err, "Failed to export playlist")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="playlist-%d.%s"`, id, format))
+	c.Data(http.StatusOK, contentType, file.Bytes())
+}
+
+// @Summary ImportPlaylist
+// @Tags playlist
+// @Description Create a playlist of my own from an extended M3U8, XSPF or JSPF file. Entries are matched against songs by location, then by artist and title, ignoring case and punctuation. Entries that match no song are reported and left out
+// @ID import-playlist
+// @Security BearerAuth
+// @Accept  plain
+// @Produce  json
+// @Param format query string false "Input format, defaults to the request Content-Type" Enums(m3u8, xspf, jspf)
+// @Param name query string false "Playlist name, defaults to the title in the file"
+// @Param dryRun query bool false "Only report what would match, no playlist is created"
+// @Param input body string true "Playlist file"
+// @Success 200 {object} playlistImportResponse "Per-entry import report"
+// @Failure 400 {object} errorResponse "Unreadable body"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 413 {object} errorResponse "Playlist is too large"
+// @Failure 422 {object} errorResponse "Unknown format, malformed file or missing name"
+// @Failure 500 {object} errorResponse "Failed to import playlist"
+// @Router /api/playlists/import [post]
+func (h *Handler) importPlaylist(c *gin.Context) {
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = playlistFormat(c.ContentType())
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+
+	user, _ := currentUser(c)
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxPlaylistSize)
+	report, err := h.services.Playlist.ImportPlaylist(c.Request.Context(), user.Id, format, strings.TrimSpace(c.Query("name")), body, dryRun)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		newErrorResponse(c, http.StatusRequestEntityTooLarge, "Playlist is too large")
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to import playlist")
+		handleError(c, err, "Failed to import playlist")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"dry_run":   dryRun,
+		"playlist":  report.PlaylistId,
+		"matched":   report.Matched,
+		"unmatched": report.Unmatched,
+	}).Info("Playlist imported")
+
+	c.JSON(http.StatusOK, playlistImportResponse{
+		Data: report,
+	})
+}
+
+func playlistFileType(format string) (contentType string, ok bool) {
+	switch format {
+	case service.PlaylistFormatM3U8:
+		return "audio/x-mpegurl; charset=utf-8", true
+	case service.PlaylistFormatXSPF:
+		return "application/xspf+xml", true
+	case service.PlaylistFormatJSPF:
+		return "application/jspf+json", true
+	}
+	return "", false
+}
+
+func playlistFormat(contentType string) string {
+	switch contentType {
+	case "audio/x-mpegurl", "audio/mpegurl", "application/x-mpegurl", "application/vnd.apple.mpegurl":
+		return service.PlaylistFormatM3U8
+	case "application/xspf+xml":
+		return service.PlaylistFormatXSPF
+	case "application/jspf+json", "application/json":
+		return service.PlaylistFormatJSPF
+	}
+	return ""
+}
+
+type playlistImportResponse struct {
+	Data musiclibrary.PlaylistImportReport `json:"data"`
+}
+
+type playlistsResponse struct {
+	Data []musiclibrary.Playlist `json:"data"`
+	pageMeta
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary GetRevisions
+// @Tags revision
+// @Description List the saved revisions of a song's details, lyrics are left out
+// @ID get-revisions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Success 200 {object} revisionsResponse "Revisions from oldest to newest"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 500 {object} errorResponse "Failed to get revisions"
+// @Router /api/songDetails/{id}/revisions [get]
+func (h *Handler) getRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	revisions, err := h.services.Revision.GetRevisions(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get revisions")
+		handleError(c, err, "Failed to get revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, revisionsResponse{
+		Data: revisions,
+	})
+}
+
+// @Summary GetRevision
+// @Tags revision
+// @Description Get one revision of a song's details including its lyrics
+// @ID get-revision
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} revisionResponse "Revision data"
+// @Failure 400 {object} errorResponse "Invalid song ID or revision"
+// @Failure 404 {object} errorResponse "Revision not found"
+// @Failure 500 {object} errorResponse "Failed to get revision"
+// @Router /api/songDetails/{id}/revisions/{revision} [get]
+func (h *Handler) getRevision(c *gin.Context) {
+	id, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	rev, err := h.services.Revision.GetRevision(c.Request.Context(), id, revision)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get revision")
+		handleError(c, err, "Failed to get revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, revisionResponse{
+		Data: rev,
+	})
+}
+
+// @Summary DiffRevisions
+// @Tags revision
+// @Description Line-level unified diff between two revisions of a song's details
+// @ID diff-revisions
+// @Produce  plain
+// @Param id path int true "Song ID"
+// @Param from query int true "Old revision number"
+// @Param to query int true "New revision number"
+// @Success 200 {string} string "Unified diff, empty when the revisions are equal"
+// @Failure 400 {object} errorResponse "Invalid song ID or revisions"
+// @Failure 404 {object} errorResponse "Revision not found"
+// @Failure 500 {object} errorResponse "Failed to diff revisions"
+// @Router /api/songDetails/{id}/revisions/diff [get]
+func (h *Handler) diffRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "Invalid from revision")
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "Invalid to revision")
+		return
+	}
+
+	diff, err := h.services.Revision.DiffRevisions(c.Request.Context(), id, from, to)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to diff revisions")
+		handleError(c, err, "Failed to diff revisions")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/x-diff; charset=utf-8", []byte(diff))
+}
+
+// @Summary RollbackRevision
+// @Tags revision
+// @Description Restore the details of an earlier revision, the rollback is saved as a new revision
+// @ID rollback-revision
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param revision path int true "Revision number to restore"
+// @Param input body musiclibrary.RollbackInput false "Who performs the rollback, the user of the token takes precedence"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid input, song ID or revision"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Revision not found"
+// @Failure 500 {object} errorResponse "Failed to roll back"
+// @Router /api/songDetails/{id}/revisions/{revision}/rollback [post]
+func (h *Handler) rollbackRevision(c *gin.Context) {
+	id, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	var input musiclibrary.RollbackInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		logrus.WithError(err).Error("Failed to bind JSON for rollback")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	err := h.services.Revision.RollbackRevision(c.Request.Context(), id, revision, revisionAuthor(c, input.Author))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to roll back")
+		handleError(c, err, "Failed to roll back")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"song_id":  id,
+		"revision": revision,
+	}).Info("Song details rolled back successfully")
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+func revisionParams(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return 0, 0, false
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid revision")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid revision")
+		return 0, 0, false
+	}
+	return id, revision, true
+}
+
+type revisionsResponse struct {
+	Data []musiclibrary.Revision `json:"data"`
+}
+type revisionResponse struct {
+	Data musiclibrary.Revision `json:"data"`
+}
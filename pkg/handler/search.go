@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary SearchLyrics
+// @Tags search
+// @Description Full-text search over song lyrics. Supports quoted phrases, OR and negation with a leading minus, e.g. "easy come" -devil
+// @ID search-lyrics
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Search query"
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of results per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
+// @Success 200 {object} searchResponse "Songs ranked by relevance with highlighted snippets"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 400 {object} errorResponse "Empty query"
+// @Failure 422 {object} errorResponse "Invalid cursor"
+// @Failure 500 {object} errorResponse "Failed to search lyrics"
+// @Router /api/search [get]
+func (h *Handler) searchLyrics(c *gin.Context) {
+	q := c.Query("q")
+	if strings.TrimSpace(q) == "" {
+		newErrorResponse(c, http.StatusBadRequest, "Query parameter q is required")
+		return
+	}
+
+	results, err := h.services.Search.SearchLyrics(c.Request.Context(), q, pageParams(c, defaultPageLimit))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to search lyrics")
+		handleError(c, err, "Failed to search lyrics")
+		return
+	}
+
+	c.JSON(http.StatusOK, searchResponse{
+		Data:     results.Items,
+		pageMeta: pageLinks(c, results),
+	})
+}
+
+type searchResponse struct {
+	Data []musiclibrary.SearchResult `json:"data"`
+	pageMeta
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary GetSimilarSongs
+// @Tags song
+// @Description Songs whose lyrics are most similar, by the cosine similarity of their TF-IDF vectors. The score raises the lyrics similarity by 25% for the same group, 15% for a shared genre and 10% for a release in the same decade. Songs without lyrics have no similar songs, nor do new songs and songs with changed lyrics until the index is refreshed in the background, within a minute
+// @ID get-similar-songs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param limit query int false "Number of songs, at most 50" default(10)
+// @Success 200 {object} similarSongsResponse "Similar songs, best first"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 404 {object} errorResponse "Song not found"
+// @Failure 422 {object} errorResponse "Invalid limit"
+// @Failure 500 {object} errorResponse "Failed to get similar songs"
+// @Router /api/song/{id}/similar [get]
+func (h *Handler) getSimilarSongs(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+	var limit int
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit == 0 {
+			handleError(c, musiclibrary.NewValidationError("limit", "must be a positive number"), "")
+			return
+		}
+	}
+
+	songs, err := h.services.Similar.GetSimilarSongs(c.Request.Context(), id, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get similar songs")
+		handleError(c, err, "Failed to get similar songs")
+		return
+	}
+
+	c.JSON(http.StatusOK, similarSongsResponse{
+		Data: songs,
+	})
+}
+
+type similarSongsResponse struct {
+	Data []musiclibrary.SimilarSong `json:"data"`
+}
@@ -11,27 +11,31 @@ import (
 
 // @Summary CreateSong
 // @Tags song
-// @Description Create a new song
+// @Description Create a new song, optionally with its release date, lyrics and link, in one transaction. When no details are given and a song info API is configured a background job fills them in
 // @ID create-song
+// @Security BearerAuth
 // @Accept  json
 // @Produce  json
 // @Param input body musiclibrary.CreateSongInput true "Song information"
 // @Success 200 {object} map[string]interface{} "Returns song ID"
-// @Failure 400 {object} errorResponse "Invalid input"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 422 {object} errorResponse "Invalid fields, release date or group"
 // @Failure 500 {object} errorResponse "Failed to create song"
 // @Router /api/song/ [post]
 func (h *Handler) createSong(c *gin.Context) {
-	var song musiclibrary.Song
-	if err := c.BindJSON(&song); err != nil {
-		logrus.WithError(err).Error("Failed to bind JSON for sign up")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var input musiclibrary.CreateSongInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for create song")
+		newBindErrorResponse(c, err)
 		return
 	}
 
-	id, err := h.services.Song.CreateSong(song)
+	id, err := h.services.Song.CreateSong(c.Request.Context(), input)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to create song")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create song"})
+		handleError(c, err, "Failed to create song")
 		return
 	}
 
@@ -50,21 +54,28 @@ func (h *Handler) createSong(c *gin.Context) {
 // @ID getAllSongs
 // @Accept  json
 // @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of songs per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
 // @Success 200 {object} getAllSongsResponse "Returns a list of all songs"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 422 {object} errorResponse "Invalid cursor"
 // @Failure 500 {object} errorResponse "Failed to get all songs"
 // @Router /api/song/ [get]
 func (h *Handler) getAllSongs(c *gin.Context) {
-	songList, err := h.services.Song.GetAllSongs()
+	songList, err := h.services.Song.GetAllSongs(c.Request.Context(), pageParams(c, defaultPageLimit))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get all songs")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get all songs"})
+		handleError(c, err, "Failed to get all songs")
 		return
 	}
 
 	logrus.Info("Retrieved all songs successfully")
 
 	c.JSON(http.StatusOK, getAllSongsResponse{
-		Data: songList,
+		Data:     songList.Items,
+		pageMeta: pageLinks(c, songList),
 	})
 }
 
@@ -72,33 +83,38 @@ func (h *Handler) getAllSongs(c *gin.Context) {
 // @Tags song
 // @Description Update an existing song
 // @ID update-song
+// @Security BearerAuth
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Song ID"
 // @Param input body musiclibrary.UpdateSongInput true "Song information"
 // @Success 200 {object} statusResponse "Returns status of the operation"
-// @Failure 400 {object} errorResponse "Invalid input or ID"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Song not found"
+// @Failure 422 {object} errorResponse "Invalid fields or group"
 // @Failure 500 {object} errorResponse "Failed to update song"
 // @Router /api/song/{id} [put]
 func (h *Handler) updateSong(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.WithError(err).Error("Invalid song ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid song ID"})
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
 		return
 	}
 
 	var input musiclibrary.UpdateSongInput
-	if err := c.BindJSON(&input); err != nil {
+	if err := c.ShouldBindJSON(&input); err != nil {
 		logrus.WithError(err).Error("Failed to bind JSON for update song")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		newBindErrorResponse(c, err)
 		return
 	}
 
-	err = h.services.Song.UpdateSong(id, input)
+	err = h.services.Song.UpdateSong(c.Request.Context(), id, input)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to update song")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update song"})
+		handleError(c, err, "Failed to update song")
 		return
 	}
 
@@ -111,15 +127,53 @@ func (h *Handler) updateSong(c *gin.Context) {
 	})
 }
 
+// @Summary GetSongById
+// @Tags song
+// @Description Get a song, include embeds its group, details and the albums it appears on
+// @ID get-song-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param include query string false "Comma-separated related resources to embed: group, details, albums"
+// @Success 200 {object} songResponse "Song with the included resources"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 404 {object} errorResponse "Song not found"
+// @Failure 422 {object} errorResponse "Unknown include"
+// @Failure 500 {object} errorResponse "Failed to get song"
+// @Router /api/song/{id} [get]
+func (h *Handler) getSongById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	song, err := h.services.Song.GetSongById(c.Request.Context(), id, includeParam(c))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get song")
+		handleError(c, err, "Failed to get song")
+		return
+	}
+
+	c.JSON(http.StatusOK, songResponse{
+		Data: song,
+	})
+}
+
 // @Summary DeleteSong
 // @Tags song
 // @Description Delete an existing song
 // @ID delete-song
+// @Security BearerAuth
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Song ID"
 // @Success 200 {object} statusResponse "Returns status of the operation"
 // @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Song not found"
 // @Failure 500 {object} errorResponse "Failed to delete song"
 // @Router /api/song/{id} [delete]
 func (h *Handler) deleteSong(c *gin.Context) {
@@ -127,14 +181,14 @@ func (h *Handler) deleteSong(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.WithError(err).Error("Invalid song ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid song ID"})
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
 		return
 	}
 
-	err = h.services.Song.DeleteSong(id)
+	err = h.services.Song.DeleteSong(c.Request.Context(), id)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to delete song")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete song"})
+		handleError(c, err, "Failed to delete song")
 		return
 	}
 
@@ -155,9 +209,20 @@ func (h *Handler) deleteSong(c *gin.Context) {
 // @Produce  json
 // @Param group query string false "Group filter"
 // @Param song query string false "Song filter"
-// @Param page query int false "Page number for pagination"
-// @Param limit query int false "Limit for pagination"
+// @Param albumtitle query string false "Album title filter"
+// @Param genre query string false "Genre name, sub-genres match too"
+// @Param tag query string false "Tag name"
+// @Param match query string false "Set to fuzzy for typo-tolerant name matching ordered by similarity score" Enums(fuzzy)
+// @Param sort query string false "Set to rating to order by average rating, best first, with unrated songs last" Enums(rating)
+// @Param favorites query bool false "Set to true for my favorites only, needs a token"
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of songs per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
 // @Success 200 {object} getAllSongsResponse
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 401 {object} errorResponse "Favorites asked for without a token"
+// @Failure 422 {object} errorResponse "Invalid cursor"
 // @Failure 500 {object} errorResponse
 // @Router /api/song/filter [get]
 func (h *Handler) getSongsWithFilter(c *gin.Context) {
@@ -167,30 +232,39 @@ func (h *Handler) getSongsWithFilter(c *gin.Context) {
 		"link":        c.Query("link"),
 		"text":        c.Query("text"),
 		"groupname":   c.Query("groupname"),
+		"albumtitle":  c.Query("albumtitle"),
+		"genre":       c.Query("genre"),
+		"tag":         c.Query("tag"),
+		"match":       c.Query("match"),
+		"sort":        c.Query("sort"),
 	}
-
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
+	if favorites, _ := strconv.ParseBool(c.Query("favorites")); favorites {
+		user, ok := currentUser(c)
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			newErrorResponse(c, http.StatusUnauthorized, "Authentication required for favorites")
+			return
+		}
+		filters["favoriteof"] = strconv.Itoa(user.Id)
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit < 1 {
-		limit = 10
-	}
-
-	songs, err := h.services.Song.GetSongsWithFilter(filters, page, limit)
+	songs, err := h.services.Song.GetSongsWithFilter(c.Request.Context(), filters, pageParams(c, defaultPageLimit))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get songs with filters")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get songs"})
+		handleError(c, err, "Failed to get songs")
 		return
 	}
 
 	c.JSON(http.StatusOK, getAllSongsResponse{
-		Data: songs,
+		Data:     songs.Items,
+		pageMeta: pageLinks(c, songs),
 	})
 }
 
 type getAllSongsResponse struct {
 	Data []musiclibrary.Song `json:"data"`
+	pageMeta
+}
+type songResponse struct {
+	Data musiclibrary.SongWithRelations `json:"data"`
 }
@@ -27,14 +27,14 @@ func (h *Handler) getSongDetailsById(c *gin.Context) {
 	songId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.WithError(err).Error("Invalid song ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid song ID"})
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
 		return
 	}
 
-	songDetails, err := h.services.SongDetails.GetSongDetailsById(songId)
+	songDetails, err := h.services.SongDetails.GetSongDetailsById(c.Request.Context(), songId)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get songDetails by ID")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get songDetails by ID"})
+		handleError(c, err, "Failed to get songDetails by ID")
 		return
 	}
 
@@ -56,13 +56,17 @@ func (h *Handler) getSongDetailsById(c *gin.Context) {
 // @Tags songDetails
 // @Description Update song details by songDetails ID
 // @ID update-songDetails
+// @Security BearerAuth
 // @Accept  json
 // @Produce  json
 // @Param id path int true "SongDetails ID"
 // @Param input body musiclibrary.UpdateSongDetailsInput true "SongDetails info"
 // @Success 200 {object} statusResponse "Status of the operation"
-// @Failure 400 {object} errorResponse "Invalid input or ID"
-// @Failure 404 {object} errorResponse "SongDetails not found"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Song not found"
+// @Failure 422 {object} errorResponse "Invalid fields or release date"
 // @Failure 500 {object} errorResponse "Failed to update song details"
 // @Router /api/songDetails/{id} [put]
 func (h *Handler) updateSongDetails(c *gin.Context) {
@@ -71,21 +75,22 @@ func (h *Handler) updateSongDetails(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.WithError(err).Error("Invalid songDetails ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid songDetails ID"})
+		newErrorResponse(c, http.StatusBadRequest, "Invalid songDetails ID")
 		return
 	}
 
 	var input musiclibrary.UpdateSongDetailsInput
-	if err := c.BindJSON(&input); err != nil {
+	if err := c.ShouldBindJSON(&input); err != nil {
 		logrus.WithError(err).Error("Failed to bind JSON for update songDetails")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		newBindErrorResponse(c, err)
 		return
 	}
 
-	err = h.services.SongDetails.UpdateSongDetails(id, input)
+	input.Author = revisionAuthor(c, input.Author)
+	err = h.services.SongDetails.UpdateSongDetails(c.Request.Context(), id, input)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to update songDetails")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update songDetails"})
+		handleError(c, err, "Failed to update songDetails")
 		return
 	}
 
@@ -107,8 +112,11 @@ func (h *Handler) updateSongDetails(c *gin.Context) {
 // @Param id path int true "SongDetails ID"
 // @Param page query int false "Page number for pagination" default(1)
 // @Param limit query int false "Limit of verses per page" default(10)
+// @Param lang query string false "Return the translation in this BCP-47 language instead of the original"
+// @Param parallel query string false "Return original and translated verses side by side for this BCP-47 language"
 // @Success 200 {object} songTextResponse "Song text with pagination"
-// @Failure 400 {object} errorResponse "Invalid songDetails ID or pagination parameters"
+// @Failure 400 {object} errorResponse "Invalid songDetails ID, language or pagination parameters"
+// @Failure 404 {object} errorResponse "Translation not found"
 // @Failure 500 {object} errorResponse "Failed to get songDetails"
 // @Router /api/songText/{id}/filter [get]
 func (h *Handler) getSongText(c *gin.Context) {
@@ -116,7 +124,7 @@ func (h *Handler) getSongText(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		logrus.WithError(err).Error("Invalid songDetails ID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid songDetails ID"})
+		newErrorResponse(c, http.StatusBadRequest, "Invalid songDetails ID")
 		return
 	}
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -129,11 +137,37 @@ func (h *Handler) getSongText(c *gin.Context) {
 		limit = 10
 	}
 
-	songText, err := h.services.SongDetails.GetSongText(id, page, limit)
+	if lang := c.Query("parallel"); lang != "" {
+		pairs, err := h.services.Translation.GetParallelText(c.Request.Context(), id, lang, page, limit)
+		if err != nil {
+			translationError(c, err, "Failed to get parallel text")
+			return
+		}
+		originals := make([]string, 0, len(pairs))
+		for _, pair := range pairs {
+			originals = append(originals, pair.Original)
+		}
+		c.JSON(http.StatusOK, songTextResponse{
+			Data:     originals,
+			Parallel: pairs,
+		})
+		return
+	}
+
+	var songText []string
+	if lang := c.Query("lang"); lang != "" {
+		songText, err = h.services.Translation.GetTranslatedText(c.Request.Context(), id, lang, page, limit)
+		if err != nil {
+			translationError(c, err, "Failed to get translated text")
+			return
+		}
+	} else {
+		songText, err = h.services.SongDetails.GetSongText(c.Request.Context(), id, page, limit)
+	}
 
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get songDetails by ID")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get songDetails by ID"})
+		handleError(c, err, "Failed to get songDetails by ID")
 		return
 	}
 	c.JSON(http.StatusOK, songTextResponse{
@@ -141,9 +175,47 @@ func (h *Handler) getSongText(c *gin.Context) {
 	})
 }
 
+// @Summary GetSongSections
+// @Tags songDetails
+// @Description Get song lyrics parsed into sections such as intro, verse, chorus, bridge and outro
+// @ID get-song-sections
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param type query string false "Only return sections of this type" Enums(intro, verse, pre-chorus, chorus, refrain, hook, bridge, interlude, instrumental, outro, other)
+// @Success 200 {object} songSectionsResponse "Lyrics sections with their lines"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 422 {object} errorResponse "Unknown section type"
+// @Failure 404 {object} errorResponse "Song not found"
+// @Failure 500 {object} errorResponse "Failed to get song sections"
+// @Router /api/songText/{id}/sections [get]
+func (h *Handler) getSongSections(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	sections, err := h.services.SongDetails.GetSongSections(c.Request.Context(), id, c.Query("type"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get song sections")
+		handleError(c, err, "Failed to get song sections")
+		return
+	}
+
+	c.JSON(http.StatusOK, songSectionsResponse{
+		Data: sections,
+	})
+}
+
 type songDetailsByIdResponse struct {
 	Data []musiclibrary.SongDetailsDL `json:"data"`
 }
 type songTextResponse struct {
-	Data []string `json:"data"`
+	Data     []string                 `json:"data"`
+	Parallel []musiclibrary.VersePair `json:"parallel,omitempty"`
+}
+type songSectionsResponse struct {
+	Data []musiclibrary.LyricsSection `json:"data"`
 }
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary GetAllTags
+// @Tags tag
+// @Description Get every tag in use
+// @ID get-all-tags
+// @Accept  json
+// @Produce  json
+// @Param after query string false "Cursor returned as next by the previous page"
+// @Param limit query int false "Number of tags per page, at most 100" default(10)
+// @Param total query bool false "Set to true to count the whole list"
+// @Param page query int false "Deprecated, use after. Page number for pagination" default(1)
+// @Success 200 {object} getAllTagsResponse "Returns a list of all tags"
+// @Header 200 {string} Link "Links to the first page and, unless this is the last one, the next"
+// @Failure 422 {object} errorResponse "Invalid cursor"
+// @Failure 500 {object} errorResponse "Failed to get all tags"
+// @Router /api/tag/ [get]
+func (h *Handler) getAllTags(c *gin.Context) {
+	tagList, err := h.services.Tag.GetAllTags(c.Request.Context(), pageParams(c, defaultPageLimit))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get all tags")
+		handleError(c, err, "Failed to get all tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllTagsResponse{
+		Data:     tagList.Items,
+		pageMeta: pageLinks(c, tagList),
+	})
+}
+
+// @Summary GetTags
+// @Tags tag
+// @Description Get the tags attached to a song or a group
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song or group ID"
+// @Success 200 {object} tagsResponse "Returns attached tags"
+// @Failure 400 {object} errorResponse "Invalid ID"
+// @Failure 500 {object} errorResponse "Failed to get tags"
+// @Router /api/song/{id}/tags [get]
+// @Router /api/group/{id}/tags [get]
+func (h *Handler) getTags(target musiclibrary.Taggable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			logrus.WithError(err).Errorf("Invalid %s ID", target)
+			newErrorResponse(c, http.StatusBadRequest, "Invalid "+string(target)+" ID")
+			return
+		}
+
+		tags, err := h.services.Tag.GetTags(c.Request.Context(), target, id)
+		if err != nil {
+			logrus.WithError(err).Errorf("Failed to get %s tags", target)
+			handleError(c, err, "Failed to get tags")
+			return
+		}
+
+		c.JSON(http.StatusOK, tagsResponse{
+			Data: tags,
+		})
+	}
+}
+
+// @Summary AttachTag
+// @Tags tag
+// @Description Attach a free-form tag to a song or a group, unknown tags are created
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song or group ID"
+// @Param tag path string true "Tag name"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid ID or tag"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 422 {object} errorResponse "Target does not exist"
+// @Failure 500 {object} errorResponse "Failed to attach tag"
+// @Router /api/song/{id}/tags/{tag} [put]
+// @Router /api/group/{id}/tags/{tag} [put]
+func (h *Handler) attachTag(target musiclibrary.Taggable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, tag, ok := tagParams(c, target)
+		if !ok {
+			return
+		}
+
+		if err := h.services.Tag.AttachTag(c.Request.Context(), target, id, tag); err != nil {
+			logrus.WithError(err).Errorf("Failed to attach tag to %s", target)
+			handleError(c, err, "Failed to attach tag")
+			return
+		}
+
+		c.JSON(http.StatusOK, statusResponse{
+			Status: "ok",
+		})
+	}
+}
+
+// @Summary DetachTag
+// @Tags tag
+// @Description Detach a tag from a song or a group
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song or group ID"
+// @Param tag path string true "Tag name"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid ID or tag"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Tag is not attached"
+// @Failure 500 {object} errorResponse "Failed to detach tag"
+// @Router /api/song/{id}/tags/{tag} [delete]
+// @Router /api/group/{id}/tags/{tag} [delete]
+func (h *Handler) detachTag(target musiclibrary.Taggable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, tag, ok := tagParams(c, target)
+		if !ok {
+			return
+		}
+
+		if err := h.services.Tag.DetachTag(c.Request.Context(), target, id, tag); err != nil {
+			logrus.WithError(err).Errorf("Failed to detach tag from %s", target)
+			handleError(c, err, "Failed to detach tag")
+			return
+		}
+
+		c.JSON(http.StatusOK, statusResponse{
+			Status: "ok",
+		})
+	}
+}
+
+func tagParams(c *gin.Context, target musiclibrary.Taggable) (int, string, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Errorf("Invalid %s ID", target)
+		newErrorResponse(c, http.StatusBadRequest, "Invalid "+string(target)+" ID")
+		return 0, "", false
+	}
+	tag := strings.TrimSpace(c.Param("tag"))
+	if tag == "" {
+		newErrorResponse(c, http.StatusBadRequest, "Invalid tag")
+		return 0, "", false
+	}
+	return id, tag, true
+}
+
+type getAllTagsResponse struct {
+	Data []musiclibrary.Tag `json:"data"`
+	pageMeta
+}
+type tagsResponse struct {
+	Data []musiclibrary.Tag `json:"data"`
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultQueryTimeout is used when QUERY_TIMEOUT is not configured.
+const DefaultQueryTimeout = 5 * time.Second
+
+// defaultRouteTimeouts apply unless Config overrides them. The export streams
+// for as long as the library takes and the import reads a large upload.
+var defaultRouteTimeouts = map[string]time.Duration{
+	"GET /api/export":  0,
+	"POST /api/import": 2 * time.Minute,
+}
+
+type Config struct {
+	// QueryTimeout is how long a request may take before its queries are
+	// cancelled and it is answered with 504. Zero disables the deadline.
+	QueryTimeout time.Duration
+	// RouteTimeouts overrides QueryTimeout per route, keyed by method and
+	// path as registered, e.g. "POST /api/import".
+	RouteTimeouts map[string]time.Duration
+}
+
+// ParseRouteTimeouts reads route deadlines written as
+// "POST /api/import=2m; GET /api/search=10s".
+func ParseRouteTimeouts(s string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("route timeout %q must be METHOD /path=duration", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("route timeout %q: %w", entry, err)
+		}
+		timeouts[strings.Join(strings.Fields(route), " ")] = d
+	}
+	return timeouts, nil
+}
+
+func (h *Handler) routeTimeout(method, path string) time.Duration {
+	key := method + " " + path
+	if d, ok := h.config.RouteTimeouts[key]; ok {
+		return d
+	}
+	if d, ok := defaultRouteTimeouts[key]; ok {
+		return d
+	}
+	return h.config.QueryTimeout
+}
+
+// deadline cancels the request context, and with it any running query, once
+// the route's timeout has passed. A handler failing because of that is
+// answered with 504 instead of its own error. The connection deadlines are
+// moved along so the server's read and write timeouts never cut a response
+// off before the route's own deadline.
+func (h *Handler) deadline(c *gin.Context) {
+	timeout := h.routeTimeout(c.Request.Method, c.FullPath())
+	rc := http.NewResponseController(c.Writer)
+	if timeout <= 0 {
+		rc.SetReadDeadline(time.Time{})
+		rc.SetWriteDeadline(time.Time{})
+		c.Next()
+		return
+	}
+	rc.SetReadDeadline(time.Now().Add(timeout))
+	rc.SetWriteDeadline(time.Now().Add(timeout + time.Second))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	c.Request = c.Request.WithContext(ctx)
+	c.Writer = &deadlineWriter{ResponseWriter: c.Writer, ctx: ctx, path: c.Request.URL.Path, timeout: timeout}
+	c.Next()
+}
+
+// deadlineWriter turns a 5xx written after the deadline passed into a 504.
+type deadlineWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	path     string
+	timeout  time.Duration
+	timedOut bool
+}
+
+func (w *deadlineWriter) WriteHeader(code int) {
+	if code < http.StatusInternalServerError || w.Written() || !errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.timedOut = true
+	logrus.WithField("timeout", w.timeout).Warn("Request deadline exceeded")
+	body, _ := json.Marshal(timeoutResponse{
+		errorResponse: errorResponse{
+			Type:     "about:blank",
+			Title:    http.StatusText(http.StatusGatewayTimeout),
+			Status:   http.StatusGatewayTimeout,
+			Detail:   "Request timed out",
+			Instance: w.path,
+		},
+		Code:    "deadline_exceeded",
+		Timeout: w.timeout.String(),
+	})
+	w.Header().Set("Content-Type", problemContentType)
+	w.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+	w.ResponseWriter.Write(body)
+}
+
+func (w *deadlineWriter) Write(b []byte) (int, error) {
+	if w.timedOut {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *deadlineWriter) WriteString(s string) (int, error) {
+	if w.timedOut {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// timeoutResponse is the problem sent with 504, extended with the timeout
+// that was exceeded.
+type timeoutResponse struct {
+	errorResponse
+	Code    string `json:"code"`
+	Timeout string `json:"timeout"`
+}
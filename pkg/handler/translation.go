@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary GetTranslations
+// @Tags translation
+// @Description Get every translation of a song's lyrics, the original comes first
+// @ID get-translations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Success 200 {object} translationsResponse "Returns song translations"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 500 {object} errorResponse "Failed to get translations"
+// @Router /api/songText/{id}/translations [get]
+func (h *Handler) getTranslations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	translations, err := h.services.Translation.GetTranslations(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get translations")
+		handleError(c, err, "Failed to get translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, translationsResponse{
+		Data: translations,
+	})
+}
+
+// @Summary GetTranslation
+// @Tags translation
+// @Description Get one translation of a song's lyrics
+// @ID get-translation
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param lang path string true "BCP-47 language code"
+// @Success 200 {object} translationResponse "Returns the translation"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 422 {object} errorResponse "Invalid language"
+// @Failure 404 {object} errorResponse "Translation not found"
+// @Failure 500 {object} errorResponse "Failed to get translation"
+// @Router /api/songText/{id}/translations/{lang} [get]
+func (h *Handler) getTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	translation, err := h.services.Translation.GetTranslation(c.Request.Context(), id, c.Param("lang"))
+	if err != nil {
+		translationError(c, err, "Failed to get translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, translationResponse{
+		Data: translation,
+	})
+}
+
+// @Summary CreateTranslation
+// @Tags translation
+// @Description Add lyrics in another language. Marking it as original unmarks the previous original
+// @ID create-translation
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param input body musiclibrary.Translation true "Translation"
+// @Success 200 {object} map[string]interface{} "Returns translation ID"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 409 {object} errorResponse "Translation already exists"
+// @Failure 422 {object} errorResponse "Invalid fields, language or song"
+// @Failure 500 {object} errorResponse "Failed to create translation"
+// @Router /api/songText/{id}/translations [post]
+func (h *Handler) createTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	var translation musiclibrary.Translation
+	if err := c.ShouldBindJSON(&translation); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for create translation")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	translationId, err := h.services.Translation.CreateTranslation(c.Request.Context(), id, translation)
+	if err != nil {
+		translationError(c, err, "Failed to create translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": translationId,
+	})
+}
+
+// @Summary UpdateTranslation
+// @Tags translation
+// @Description Update a translation of a song's lyrics
+// @ID update-translation
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param lang path string true "BCP-47 language code"
+// @Param input body musiclibrary.UpdateTranslationInput true "Translation"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Malformed JSON or invalid ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Translation not found"
+// @Failure 422 {object} errorResponse "Invalid fields or language"
+// @Failure 500 {object} errorResponse "Failed to update translation"
+// @Router /api/songText/{id}/translations/{lang} [put]
+func (h *Handler) updateTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	var input musiclibrary.UpdateTranslationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for update translation")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	err = h.services.Translation.UpdateTranslation(c.Request.Context(), id, c.Param("lang"), input)
+	if err != nil {
+		translationError(c, err, "Failed to update translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+// @Summary DeleteTranslation
+// @Tags translation
+// @Description Delete a translation of a song's lyrics
+// @ID delete-translation
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song ID"
+// @Param lang path string true "BCP-47 language code"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid song ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Requires the editor role"
+// @Failure 404 {object} errorResponse "Translation not found"
+// @Failure 422 {object} errorResponse "Invalid language"
+// @Failure 500 {object} errorResponse "Failed to delete translation"
+// @Router /api/songText/{id}/translations/{lang} [delete]
+func (h *Handler) deleteTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid song ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid song ID")
+		return
+	}
+
+	err = h.services.Translation.DeleteTranslation(c.Request.Context(), id, c.Param("lang"))
+	if err != nil {
+		translationError(c, err, "Failed to delete translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+func translationError(c *gin.Context, err error, message string) {
+	if errorStatus(err) == http.StatusInternalServerError {
+		logrus.WithError(err).Error(message)
+	}
+	handleError(c, err, message)
+}
+
+type translationsResponse struct {
+	Data []musiclibrary.Translation `json:"data"`
+}
+type translationResponse struct {
+	Data musiclibrary.Translation `json:"data"`
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	musiclibrary "time-tracker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary CreateUser
+// @Tags user
+// @Description Create a user, who can use the API once given a key
+// @ID create-user
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param input body musiclibrary.User true "User name and role"
+// @Success 200 {object} map[string]interface{} "Returns user ID"
+// @Failure 400 {object} errorResponse "Malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Not an admin"
+// @Failure 409 {object} errorResponse "User already exists"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to create user"
+// @Router /api/users/ [post]
+func (h *Handler) createUser(c *gin.Context) {
+	var user musiclibrary.User
+	if err := c.ShouldBindJSON(&user); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for create user")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	id, err := h.services.User.CreateUser(c.Request.Context(), user)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create user")
+		handleError(c, err, "Failed to create user")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": id,
+		"role":    user.Role,
+	}).Info("User created successfully")
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary GetUsers
+// @Tags user
+// @Description List users with their roles
+// @ID get-users
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} usersResponse "Users"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Not an admin"
+// @Failure 500 {object} errorResponse "Failed to get users"
+// @Router /api/users/ [get]
+func (h *Handler) getUsers(c *gin.Context) {
+	users, err := h.services.User.GetUsers(c.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get users")
+		handleError(c, err, "Failed to get users")
+		return
+	}
+
+	c.JSON(http.StatusOK, usersResponse{
+		Data: users,
+	})
+}
+
+// @Summary CreateAPIKey
+// @Tags user
+// @Description Create an API key for a user. The key is only shown in this response, keep it safe
+// @ID create-api-key
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param input body musiclibrary.APIKeyInput true "Name of the key"
+// @Success 200 {object} musiclibrary.NewAPIKey "The new key"
+// @Failure 400 {object} errorResponse "Invalid user ID or malformed JSON"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Not an admin"
+// @Failure 404 {object} errorResponse "User not found"
+// @Failure 422 {object} errorResponse "Invalid fields"
+// @Failure 500 {object} errorResponse "Failed to create API key"
+// @Router /api/users/{id}/keys [post]
+func (h *Handler) createAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid user ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var input musiclibrary.APIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logrus.WithError(err).Error("Failed to bind JSON for create API key")
+		newBindErrorResponse(c, err)
+		return
+	}
+
+	key, err := h.services.User.CreateAPIKey(c.Request.Context(), id, input)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create API key")
+		handleError(c, err, "Failed to create API key")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": id,
+		"key_id":  key.Id,
+	}).Info("API key created successfully")
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, key)
+}
+
+// @Summary GetAPIKeys
+// @Tags user
+// @Description List the API keys of a user, without the keys themselves
+// @ID get-api-keys
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {object} apiKeysResponse "API keys"
+// @Failure 400 {object} errorResponse "Invalid user ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Not an admin"
+// @Failure 404 {object} errorResponse "User not found"
+// @Failure 500 {object} errorResponse "Failed to get API keys"
+// @Router /api/users/{id}/keys [get]
+func (h *Handler) getAPIKeys(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid user ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	keys, err := h.services.User.GetAPIKeys(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get API keys")
+		handleError(c, err, "Failed to get API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKeysResponse{
+		Data: keys,
+	})
+}
+
+// @Summary DeleteAPIKey
+// @Tags user
+// @Description Revoke an API key. Access tokens already issued for it stay valid until they expire
+// @ID delete-api-key
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param keyId path int true "API key ID"
+// @Success 200 {object} statusResponse "Returns status of the operation"
+// @Failure 400 {object} errorResponse "Invalid user or key ID"
+// @Failure 401 {object} errorResponse "Missing or invalid token"
+// @Failure 403 {object} errorResponse "Not an admin"
+// @Failure 404 {object} errorResponse "API key not found"
+// @Failure 500 {object} errorResponse "Failed to delete API key"
+// @Router /api/users/{id}/keys/{keyId} [delete]
+func (h *Handler) deleteAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid user ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	keyId, err := strconv.Atoi(c.Param("keyId"))
+	if err != nil {
+		logrus.WithError(err).Error("Invalid API key ID")
+		newErrorResponse(c, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	if err := h.services.User.DeleteAPIKey(c.Request.Context(), id, keyId); err != nil {
+		logrus.WithError(err).Error("Failed to delete API key")
+		handleError(c, err, "Failed to delete API key")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": id,
+		"key_id":  keyId,
+	}).Info("API key deleted successfully")
+
+	c.JSON(http.StatusOK, statusResponse{
+		Status: "ok",
+	})
+}
+
+type usersResponse struct {
+	Data []musiclibrary.User `json:"data"`
+}
+type apiKeysResponse struct {
+	Data []musiclibrary.APIKey `json:"data"`
+}
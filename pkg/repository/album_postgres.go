@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const albumColumns = `id, title, COALESCE(TO_CHAR(releaseDate, 'YYYY-MM-DD'), '') AS releasedate, label, coverLink AS coverlink`
+
+type AlbumPostgres struct {
+	db *sqlx.DB
+}
+
+func NewAlbumPostgres(db *sqlx.DB) *AlbumPostgres {
+	return &AlbumPostgres{db: db}
+}
+
+func (r *AlbumPostgres) CreateAlbum(ctx context.Context, album musiclibrary.Album) (int, error) {
+	logrus.Debug("Creating album")
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (title, releaseDate, label, coverLink) VALUES ($1, NULLIF($2, '')::date, $3, $4) RETURNING id", albumsTable)
+	row := r.db.QueryRowContext(ctx, query, album.Title, album.ReleaseDate, album.Label, album.CoverLink)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to create album")
+		return 0, dbError(err, "album")
+	}
+	logrus.WithField("id", id).Info("Album created successfully")
+	return id, nil
+}
+
+func (r *AlbumPostgres) GetAllAlbums(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Album], error) {
+	logrus.Debug("Fetching all albums")
+	query := fmt.Sprintf("SELECT %s FROM %s", albumColumns, albumsTable)
+	albumList, err := selectPage(ctx, r.db, query, nil, sortById, page, pgPlaceholder, func(a musiclibrary.Album) []any {
+		return []any{a.Id}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch all albums")
+		return musiclibrary.Page[musiclibrary.Album]{}, dbError(err, "album")
+	}
+	logrus.WithField("count", len(albumList.Items)).Info("Fetched all albums successfully")
+	return albumList, nil
+}
+
+func (r *AlbumPostgres) GetAlbumById(ctx context.Context, id int) (musiclibrary.Album, error) {
+	logrus.WithField("id", id).Debug("Fetching album by ID")
+	var album musiclibrary.Album
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", albumColumns, albumsTable)
+	err := r.db.GetContext(ctx, &album, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch album by ID")
+		return album, dbError(err, "album")
+	}
+	return album, nil
+}
+
+func (r *AlbumPostgres) DeleteAlbum(ctx context.Context, id int) error {
+	logrus.WithField("id", id).Debug("Deleting album")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", albumsTable)
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete album")
+		return dbError(err, "album")
+	}
+	if err := affected(res, "album"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Album deleted successfully")
+	return nil
+}
+
+func (r *AlbumPostgres) UpdateAlbum(ctx context.Context, id int, input musiclibrary.UpdateAlbumInput) error {
+	logrus.WithField("id", id).Debug("Updating album")
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Title != nil {
+		setValues = append(setValues, fmt.Sprintf("title=$%d", argId))
+		args = append(args, *input.Title)
+		argId++
+	}
+	if input.ReleaseDate != nil {
+		setValues = append(setValues, fmt.Sprintf("releaseDate=NULLIF($%d, '')::date", argId))
+		args = append(args, *input.ReleaseDate)
+		argId++
+	}
+	if input.Label != nil {
+		setValues = append(setValues, fmt.Sprintf("label=$%d", argId))
+		args = append(args, *input.Label)
+		argId++
+	}
+	if input.CoverLink != nil {
+		setValues = append(setValues, fmt.Sprintf("coverLink=$%d", argId))
+		args = append(args, *input.CoverLink)
+		argId++
+	}
+
+	if argId > 1 {
+		setQuery := strings.Join(setValues, ", ")
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", albumsTable, setQuery, argId)
+		args = append(args, id)
+		res, err := r.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to update album")
+			return dbError(err, "album")
+		}
+		if err := affected(res, "album"); err != nil {
+			return err
+		}
+		logrus.WithField("id", id).Info("Album updated successfully")
+	}
+
+	return nil
+}
+
+func (r *AlbumPostgres) GetAlbumTracks(ctx context.Context, albumId int) ([]musiclibrary.AlbumTrack, error) {
+	logrus.WithField("albumId", albumId).Debug("Fetching album tracks")
+	var tracks []musiclibrary.AlbumTrack
+	query := fmt.Sprintf(`
+		SELECT t.albumId AS albumid, t.songId AS songid, s.songName AS songname,
+			t.discNumber AS discnumber, t.trackNumber AS tracknumber
+		FROM %s t
+		JOIN %s s ON s.id = t.songId
+		WHERE t.albumId = $1
+		ORDER BY t.discNumber, t.trackNumber`, albumTracksTable, songsTable)
+	err := r.db.SelectContext(ctx, &tracks, query, albumId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch album tracks")
+		return nil, dbError(err, "album track")
+	}
+	logrus.WithField("count", len(tracks)).Info("Fetched album tracks successfully")
+	return tracks, nil
+}
+
+// GetSongAlbums returns the albums each of the given songs appears on with a
+// single query, ordered by song and album release.
+func (r *AlbumPostgres) GetSongAlbums(ctx context.Context, songIds []int) ([]musiclibrary.SongAlbum, error) {
+	logrus.WithField("songIds", songIds).Debug("Fetching song albums")
+	var albums []musiclibrary.SongAlbum
+	query := fmt.Sprintf(`
+		SELECT a.id, a.title, COALESCE(TO_CHAR(a.releaseDate, 'YYYY-MM-DD'), '') AS releasedate,
+			a.label, a.coverLink AS coverlink, t.songId AS songid,
+			t.discNumber AS discnumber, t.trackNumber AS tracknumber
+		FROM %s t
+		JOIN %s a ON a.id = t.albumId
+		WHERE t.songId = ANY($1)
+		ORDER BY t.songId, a.releaseDate NULLS LAST, a.id`, albumTracksTable, albumsTable)
+	err := r.db.SelectContext(ctx, &albums, query, pq.Array(songIds))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch song albums")
+		return nil, dbError(err, "album")
+	}
+	return albums, nil
+}
+
+func (r *AlbumPostgres) AddTrack(ctx context.Context, albumId int, input musiclibrary.AlbumTrackInput) error {
+	logrus.WithFields(logrus.Fields{
+		"albumId": albumId,
+		"songId":  input.SongId,
+	}).Debug("Adding track to album")
+	discNumber := input.DiscNumber
+	if discNumber < 1 {
+		discNumber = 1
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (albumId, songId, discNumber, trackNumber) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (albumId, songId) DO UPDATE SET discNumber = EXCLUDED.discNumber, trackNumber = EXCLUDED.trackNumber`,
+		albumTracksTable)
+	_, err := r.db.ExecContext(ctx, query, albumId, input.SongId, discNumber, input.TrackNumber)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add track to album")
+		return dbError(err, "album track")
+	}
+	logrus.WithField("albumId", albumId).Info("Track added to album successfully")
+	return nil
+}
+
+func (r *AlbumPostgres) RemoveTrack(ctx context.Context, albumId, songId int) error {
+	logrus.WithFields(logrus.Fields{
+		"albumId": albumId,
+		"songId":  songId,
+	}).Debug("Removing track from album")
+	query := fmt.Sprintf("DELETE FROM %s WHERE albumId = $1 AND songId = $2", albumTracksTable)
+	res, err := r.db.ExecContext(ctx, query, albumId, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove track from album")
+		return dbError(err, "album track")
+	}
+	if err := affected(res, "album track"); err != nil {
+		return err
+	}
+	logrus.WithField("albumId", albumId).Info("Track removed from album successfully")
+	return nil
+}
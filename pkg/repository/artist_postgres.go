@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const artistColumns = `id, name, COALESCE(TO_CHAR(birthDate, 'YYYY-MM-DD'), '') AS birthdate`
+
+type ArtistPostgres struct {
+	db *sqlx.DB
+}
+
+func NewArtistPostgres(db *sqlx.DB) *ArtistPostgres {
+	return &ArtistPostgres{db: db}
+}
+
+func (r *ArtistPostgres) CreateArtist(ctx context.Context, artist musiclibrary.Artist) (int, error) {
+	logrus.Debug("Creating artist")
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (name, birthDate) VALUES ($1, NULLIF($2, '')::date) RETURNING id", artistsTable)
+	row := r.db.QueryRowContext(ctx, query, artist.Name, artist.BirthDate)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to create artist")
+		return 0, dbError(err, "artist")
+	}
+	logrus.WithField("id", id).Info("Artist created successfully")
+	return id, nil
+}
+
+func (r *ArtistPostgres) GetAllArtists(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Artist], error) {
+	logrus.Debug("Fetching all artists")
+	query := fmt.Sprintf("SELECT %s FROM %s", artistColumns, artistsTable)
+	artistList, err := selectPage(ctx, r.db, query, nil, sortById, page, pgPlaceholder, func(a musiclibrary.Artist) []any {
+		return []any{a.Id}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch all artists")
+		return musiclibrary.Page[musiclibrary.Artist]{}, dbError(err, "artist")
+	}
+	logrus.WithField("count", len(artistList.Items)).Info("Fetched all artists successfully")
+	return artistList, nil
+}
+
+func (r *ArtistPostgres) GetArtistById(ctx context.Context, id int) (musiclibrary.Artist, error) {
+	logrus.WithField("id", id).Debug("Fetching artist by ID")
+	var artist musiclibrary.Artist
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", artistColumns, artistsTable)
+	err := r.db.GetContext(ctx, &artist, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch artist by ID")
+		return artist, dbError(err, "artist")
+	}
+	return artist, nil
+}
+
+func (r *ArtistPostgres) DeleteArtist(ctx context.Context, id int) error {
+	logrus.WithField("id", id).Debug("Deleting artist")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", artistsTable)
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete artist")
+		return dbError(err, "artist")
+	}
+	if err := affected(res, "artist"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Artist deleted successfully")
+	return nil
+}
+
+func (r *ArtistPostgres) UpdateArtist(ctx context.Context, id int, input musiclibrary.UpdateArtistInput) error {
+	logrus.WithField("id", id).Debug("Updating artist")
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Name != nil {
+		setValues = append(setValues, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *input.Name)
+		argId++
+	}
+	if input.BirthDate != nil {
+		setValues = append(setValues, fmt.Sprintf("birthDate=NULLIF($%d, '')::date", argId))
+		args = append(args, *input.BirthDate)
+		argId++
+	}
+
+	if argId > 1 {
+		setQuery := strings.Join(setValues, ", ")
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", artistsTable, setQuery, argId)
+		args = append(args, id)
+		res, err := r.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to update artist")
+			return dbError(err, "artist")
+		}
+		if err := affected(res, "artist"); err != nil {
+			return err
+		}
+		logrus.WithField("id", id).Info("Artist updated successfully")
+	}
+
+	return nil
+}
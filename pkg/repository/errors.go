@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	musiclibrary "time-tracker"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// dbError translates what the database driver returned into a domain error:
+// a missing row becomes NotFound, constraint violations and values the
+// database cannot parse become ForeignKey, Conflict or Validation. entity
+// names the record for messages, e.g. "group". Other errors are returned
+// unchanged.
+func dbError(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+	var domainErr *musiclibrary.Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &musiclibrary.Error{Kind: musiclibrary.ErrNotFound, Message: entity + " not found", Err: err}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "23": // integrity_constraint_violation
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				return &musiclibrary.Error{Kind: musiclibrary.ErrForeignKey, Message: foreignKeyMessage(entity), Err: err}
+			case "unique_violation":
+				return &musiclibrary.Error{Kind: musiclibrary.ErrConflict, Message: entity + " already exists", Err: err}
+			}
+			return &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: "invalid " + entity, Err: err}
+		case "22": // data_exception, e.g. a malformed date
+			return &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: "invalid " + entity + ": " + pqErr.Message, Err: err}
+		}
+		return err
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintForeignKey:
+			return &musiclibrary.Error{Kind: musiclibrary.ErrForeignKey, Message: foreignKeyMessage(entity), Err: err}
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return &musiclibrary.Error{Kind: musiclibrary.ErrConflict, Message: entity + " already exists", Err: err}
+		}
+		return &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: "invalid " + entity, Err: err}
+	}
+	return err
+}
+
+func foreignKeyMessage(entity string) string {
+	return entity + " references a record that does not exist"
+}
+
+// notFound is the error for updates and deletes that matched no row. Like
+// the one dbError makes of sql.ErrNoRows it still matches sql.ErrNoRows.
+func notFound(entity string) error {
+	return &musiclibrary.Error{Kind: musiclibrary.ErrNotFound, Message: entity + " not found", Err: sql.ErrNoRows}
+}
+
+// exists returns a NotFound error unless query, selecting a row by id, finds
+// one. Updates that have nothing to set use it to still report missing rows.
+func exists(ctx context.Context, db dbtx, query string, id int, entity string) error {
+	var one int
+	return dbError(db.GetContext(ctx, &one, query, id), entity)
+}
+
+// affected returns a NotFound error when res reports that no row was
+// changed.
+func affected(res sql.Result, entity string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound(entity)
+	}
+	return nil
+}
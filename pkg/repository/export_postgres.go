@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportWriter receives the library one record at a time, groups first, then
+// songs, then song details.
+type ExportWriter interface {
+	Group(group musiclibrary.Group) error
+	Song(song musiclibrary.Song) error
+	SongDetails(details musiclibrary.SongDetails) error
+}
+
+type ExportPostgres struct {
+	db *sqlx.DB
+}
+
+func NewExportPostgres(db *sqlx.DB) *ExportPostgres {
+	return &ExportPostgres{db: db}
+}
+
+// Export streams the whole library to w row by row without loading it into
+// memory. All tables are read from one repeatable-read snapshot so songs never
+// reference groups that are missing from the export.
+func (r *ExportPostgres) Export(ctx context.Context, w ExportWriter) error {
+	logrus.Debug("Exporting library")
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("SELECT id, groupName AS groupname FROM %s ORDER BY id", groupsTable)
+	if err := exportRows(ctx, tx, query, func(rows *sqlx.Rows) error {
+		var group musiclibrary.Group
+		if err := rows.StructScan(&group); err != nil {
+			return err
+		}
+		return w.Group(group)
+	}); err != nil {
+		logrus.WithError(err).Error("Failed to export groups")
+		return err
+	}
+
+	query = fmt.Sprintf("SELECT id, songName AS songname, groupId AS groupid FROM %s ORDER BY id", songsTable)
+	if err := exportRows(ctx, tx, query, func(rows *sqlx.Rows) error {
+		var song musiclibrary.Song
+		if err := rows.StructScan(&song); err != nil {
+			return err
+		}
+		return w.Song(song)
+	}); err != nil {
+		logrus.WithError(err).Error("Failed to export songs")
+		return err
+	}
+
+	query = fmt.Sprintf(`SELECT id, songId AS songid, COALESCE(TO_CHAR(releaseDate, 'YYYY-MM-DD'), '') AS releasedate,
+		COALESCE(text, '') AS text, COALESCE(link, '') AS link FROM %s ORDER BY songId`, songDetailsTable)
+	if err := exportRows(ctx, tx, query, func(rows *sqlx.Rows) error {
+		var details musiclibrary.SongDetails
+		if err := rows.StructScan(&details); err != nil {
+			return err
+		}
+		return w.SongDetails(details)
+	}); err != nil {
+		logrus.WithError(err).Error("Failed to export song details")
+		return err
+	}
+
+	logrus.Info("Library exported successfully")
+	return nil
+}
+
+func exportRows(ctx context.Context, tx *sqlx.Tx, query string, fn func(rows *sqlx.Rows) error) error {
+	rows, err := tx.QueryxContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
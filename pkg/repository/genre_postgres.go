@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type linkTable struct {
+	table  string
+	column string
+}
+
+var genreLinks = map[musiclibrary.Taggable]linkTable{
+	musiclibrary.TaggableSong:  {table: "songGenres", column: "songId"},
+	musiclibrary.TaggableGroup: {table: "groupGenres", column: "groupId"},
+}
+
+// genreSubtree builds a subquery selecting the ids of the genre named by
+// placeholder $argId and all of its descendants.
+func genreSubtree(argId int) string {
+	return fmt.Sprintf(`WITH RECURSIVE subtree AS (
+			SELECT id FROM genres WHERE name ILIKE $%d
+			UNION
+			SELECT g.id FROM genres g JOIN subtree ON g.parentId = subtree.id
+		) SELECT id FROM subtree`, argId)
+}
+
+type GenrePostgres struct {
+	db *sqlx.DB
+}
+
+func NewGenrePostgres(db *sqlx.DB) *GenrePostgres {
+	return &GenrePostgres{db: db}
+}
+
+func (r *GenrePostgres) CreateGenre(ctx context.Context, genre musiclibrary.Genre) (int, error) {
+	logrus.Debug("Creating genre")
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (name, parentId) VALUES ($1, $2) RETURNING id", genresTable)
+	row := r.db.QueryRowContext(ctx, query, genre.Name, genre.ParentId)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to create genre")
+		return 0, dbError(err, "genre")
+	}
+	logrus.WithField("id", id).Info("Genre created successfully")
+	return id, nil
+}
+
+func (r *GenrePostgres) GetAllGenres(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Genre], error) {
+	logrus.Debug("Fetching all genres")
+	query := fmt.Sprintf("SELECT id, name, parentId AS parentid FROM %s", genresTable)
+	genreList, err := selectPage(ctx, r.db, query, nil, sortById, page, pgPlaceholder, func(g musiclibrary.Genre) []any {
+		return []any{g.Id}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch all genres")
+		return musiclibrary.Page[musiclibrary.Genre]{}, dbError(err, "genre")
+	}
+	logrus.WithField("count", len(genreList.Items)).Info("Fetched all genres successfully")
+	return genreList, nil
+}
+
+func (r *GenrePostgres) DeleteGenre(ctx context.Context, id int) error {
+	logrus.WithField("id", id).Debug("Deleting genre")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", genresTable)
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete genre")
+		return dbError(err, "genre")
+	}
+	if err := affected(res, "genre"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Genre deleted successfully")
+	return nil
+}
+
+func (r *GenrePostgres) GetGenres(ctx context.Context, target musiclibrary.Taggable, targetId int) ([]musiclibrary.Genre, error) {
+	link, ok := genreLinks[target]
+	if !ok {
+		return nil, fmt.Errorf("unknown taggable %q", target)
+	}
+	var genres []musiclibrary.Genre
+	query := fmt.Sprintf(`SELECT g.id, g.name, g.parentId AS parentid FROM %s g
+		JOIN %s l ON l.genreId = g.id WHERE l.%s = $1 ORDER BY g.name`, genresTable, link.table, link.column)
+	err := r.db.SelectContext(ctx, &genres, query, targetId)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to fetch %s genres", target)
+		return nil, dbError(err, "genre")
+	}
+	return genres, nil
+}
+
+func (r *GenrePostgres) AttachGenre(ctx context.Context, target musiclibrary.Taggable, targetId, genreId int) error {
+	link, ok := genreLinks[target]
+	if !ok {
+		return fmt.Errorf("unknown taggable %q", target)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s, genreId) VALUES ($1, $2) ON CONFLICT DO NOTHING", link.table, link.column)
+	_, err := r.db.ExecContext(ctx, query, targetId, genreId)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to attach genre to %s", target)
+		return dbError(err, "genre")
+	}
+	logrus.WithFields(logrus.Fields{
+		string(target): targetId,
+		"genreId":      genreId,
+	}).Info("Genre attached successfully")
+	return nil
+}
+
+func (r *GenrePostgres) DetachGenre(ctx context.Context, target musiclibrary.Taggable, targetId, genreId int) error {
+	link, ok := genreLinks[target]
+	if !ok {
+		return fmt.Errorf("unknown taggable %q", target)
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND genreId = $2", link.table, link.column)
+	res, err := r.db.ExecContext(ctx, query, targetId, genreId)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to detach genre from %s", target)
+		return dbError(err, "genre")
+	}
+	if err := affected(res, "genre"); err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		string(target): targetId,
+		"genreId":      genreId,
+	}).Info("Genre detached successfully")
+	return nil
+}
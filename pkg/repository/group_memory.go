@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	musiclibrary "time-tracker"
+)
+
+type GroupMemory struct {
+	store *memoryStore
+}
+
+func (r *GroupMemory) CreateGroup(ctx context.Context, group musiclibrary.Group) (int, error) {
+	var id int
+	err := r.store.write(ctx, func() error {
+		r.store.lastGroupId++
+		id = r.store.lastGroupId
+		r.store.groups[id] = musiclibrary.Group{Id: id, GroupName: group.GroupName}
+		return nil
+	})
+	return id, err
+}
+
+func (r *GroupMemory) GetAllGroups(ctx context.Context) ([]musiclibrary.Group, error) {
+	var groups []musiclibrary.Group
+	err := r.store.read(ctx, func() error {
+		groups = r.store.sortedGroups()
+		return nil
+	})
+	return groups, err
+}
+
+func (r *GroupMemory) GetGroupById(ctx context.Context, id int) (musiclibrary.Group, error) {
+	var group musiclibrary.Group
+	err := r.store.read(ctx, func() error {
+		var ok bool
+		if group, ok = r.store.groups[id]; !ok {
+			return notFound("group")
+		}
+		return nil
+	})
+	return group, err
+}
+
+func (r *GroupMemory) DeleteGroup(ctx context.Context, id int) error {
+	return r.store.write(ctx, func() error {
+		if _, ok := r.store.groups[id]; !ok {
+			return notFound("group")
+		}
+		delete(r.store.groups, id)
+		for songId, song := range r.store.songs {
+			if song.GroupId == id {
+				r.store.deleteSong(songId)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GroupMemory) UpdateGroup(ctx context.Context, id int, input musiclibrary.UpdateGroupInput) error {
+	return r.store.write(ctx, func() error {
+		group, ok := r.store.groups[id]
+		if !ok {
+			return notFound("group")
+		}
+		if input.GroupName == nil {
+			return nil
+		}
+		group.GroupName = *input.GroupName
+		r.store.groups[id] = group
+		return nil
+	})
+}
+
+func (r *GroupMemory) GetGroupsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Group], error) {
+	var result musiclibrary.Page[musiclibrary.Group]
+	err := r.store.read(ctx, func() error {
+		var groups []musiclibrary.Group
+		fuzzy := filters["match"] == matchFuzzy
+		name := filters["groupname"]
+		for _, group := range r.store.sortedGroups() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// Genres and tags are not kept in memory, so no group has any.
+			if filters["genre"] != "" || filters["tag"] != "" {
+				continue
+			}
+			if name != "" {
+				if !fuzzy {
+					if !contains(group.GroupName, name) {
+						continue
+					}
+				} else {
+					ok, score := trigramMatch(group.GroupName, name)
+					if !ok {
+						continue
+					}
+					group.Score = &score
+				}
+			}
+			groups = append(groups, group)
+		}
+		if fuzzy && name != "" {
+			sort.SliceStable(groups, func(i, j int) bool {
+				return *groups[i].Score > *groups[j].Score
+			})
+		}
+		var err error
+		result, err = pageSlice(groups, scoredKeys(fuzzy && name != ""), page, groupKey)
+		return err
+	})
+	return result, err
+}
+
+func (s *memoryStore) sortedGroups() []musiclibrary.Group {
+	groups := make([]musiclibrary.Group, 0, len(s.groups))
+	for _, group := range s.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Id < groups[j].Id
+	})
+	return groups
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	musiclibrary "time-tracker"
@@ -10,51 +11,65 @@ import (
 )
 
 type GroupPostgres struct {
-	db *sqlx.DB
+	db dbtx
 }
 
 func NewGroupPostgres(db *sqlx.DB) *GroupPostgres {
 	return &GroupPostgres{db: db}
 }
-func (r *GroupPostgres) CreateGroup(group musiclibrary.Group) (int, error) {
+func (r *GroupPostgres) CreateGroup(ctx context.Context, group musiclibrary.Group) (int, error) {
 	logrus.Debug("Creating group")
 	var id int
 	query := fmt.Sprintf("INSERT INTO %s (groupName) VALUES ($1) RETURNING id", groupsTable)
-	row := r.db.QueryRow(query, group.GroupName)
+	row := r.db.QueryRowContext(ctx, query, group.GroupName)
 	if err := row.Scan(&id); err != nil {
 		logrus.WithError(err).Error("Failed to create group")
-		return 0, err
+		return 0, dbError(err, "group")
 	}
 	logrus.WithField("id", id).Info("Group created successfully")
 	return id, nil
 }
 
-func (r *GroupPostgres) GetAllGroups() ([]musiclibrary.Group, error) {
+func (r *GroupPostgres) GetAllGroups(ctx context.Context) ([]musiclibrary.Group, error) {
 	logrus.Debug("Fetching all groups")
 	var groupList []musiclibrary.Group
 	query := fmt.Sprintf("SELECT * FROM %s", groupsTable)
-	err := r.db.Select(&groupList, query)
+	err := r.db.SelectContext(ctx, &groupList, query)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to fetch all groups")
-		return nil, err
+		return nil, dbError(err, "group")
 	}
 	logrus.WithField("count", len(groupList)).Info("Fetched all groups successfully")
 	return groupList, err
 }
 
-func (r *GroupPostgres) DeleteGroup(id int) error {
+func (r *GroupPostgres) GetGroupById(ctx context.Context, id int) (musiclibrary.Group, error) {
+	var group musiclibrary.Group
+	query := fmt.Sprintf("SELECT id, groupName AS groupname FROM %s WHERE id = $1", groupsTable)
+	err := r.db.GetContext(ctx, &group, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch group")
+		return group, dbError(err, "group")
+	}
+	return group, nil
+}
+
+func (r *GroupPostgres) DeleteGroup(ctx context.Context, id int) error {
 	logrus.WithField("id", id).Debug("Deleting group")
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", groupsTable)
-	_, err := r.db.Exec(query, id)
+	res, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to delete group")
+		return dbError(err, "group")
+	}
+	if err := affected(res, "group"); err != nil {
 		return err
 	}
 	logrus.WithField("id", id).Info("Group deleted successfully")
 	return nil
 }
 
-func (r *GroupPostgres) UpdateGroup(id int, input musiclibrary.UpdateGroupInput) error {
+func (r *GroupPostgres) UpdateGroup(ctx context.Context, id int, input musiclibrary.UpdateGroupInput) error {
 	logrus.WithField("id", id).Debug("Updating group")
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
@@ -70,42 +85,64 @@ func (r *GroupPostgres) UpdateGroup(id int, input musiclibrary.UpdateGroupInput)
 		setQuery := strings.Join(setValues, ", ")
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", groupsTable, setQuery, argId)
 		args = append(args, id)
-		_, err := r.db.Exec(query, args...)
+		res, err := r.db.ExecContext(ctx, query, args...)
 		if err != nil {
 			logrus.WithError(err).Error("Failed to update group")
+			return dbError(err, "group")
+		}
+		if err := affected(res, "group"); err != nil {
 			return err
 		}
 		logrus.WithField("id", id).Info("Group updated successfully")
+		return nil
 	}
 
-	return nil
+	return exists(ctx, r.db, fmt.Sprintf("SELECT 1 FROM %s WHERE id = $1", groupsTable), id, "group")
 }
 
-func (r *GroupPostgres) GetGroupsWithFilter(filters map[string]string, page, limit int) ([]musiclibrary.Group, error) {
-	var groups []musiclibrary.Group
+func (r *GroupPostgres) GetGroupsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Group], error) {
 	var conditions []string
 	var args []interface{}
 	argId := 1
 
-	query := `SELECT * FROM groupss WHERE 1=1`
+	score := ""
+	fuzzy := filters["match"] == matchFuzzy
 
 	if group, ok := filters["groupname"]; ok && group != "" {
-		conditions = append(conditions, fmt.Sprintf("groupname ILIKE $%d", argId))
-		args = append(args, "%"+group+"%")
+		if fuzzy {
+			var condition string
+			condition, score = fuzzyMatch("groupname", argId)
+			conditions = append(conditions, condition)
+			args = append(args, group)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("groupname ILIKE $%d", argId))
+			args = append(args, "%"+group+"%")
+		}
 		argId++
 	}
 
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
+	if genre, ok := filters["genre"]; ok && genre != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"id IN (SELECT groupId FROM groupGenres WHERE genreId IN (%s))", genreSubtree(argId)))
+		args = append(args, genre)
+		argId++
 	}
 
-	query += fmt.Sprintf(" ORDER BY id LIMIT $%d OFFSET $%d", argId, argId+1)
-	args = append(args, limit, (page-1)*limit)
+	if tag, ok := filters["tag"]; ok && tag != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"id IN (SELECT gt.groupId FROM groupTags gt JOIN tags t ON t.id = gt.tagId WHERE t.name = LOWER($%d))", argId))
+		args = append(args, tag)
+		argId++
+	}
 
-	err := r.db.Select(&groups, query, args...)
-	if err != nil {
-		return nil, err
+	query := `SELECT * FROM groupss WHERE 1=1`
+	if score != "" {
+		query = fmt.Sprintf(`SELECT *, %s AS score FROM groupss WHERE 1=1`, score)
+	}
+
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
 	}
 
-	return groups, nil
+	return selectPage(ctx, r.db, query, args, scoredKeys(score != ""), page, pgPlaceholder, groupKey)
 }
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type GroupSQLite struct {
+	db dbtx
+}
+
+func NewGroupSQLite(db *sqlx.DB) *GroupSQLite {
+	return &GroupSQLite{db: db}
+}
+
+func (r *GroupSQLite) CreateGroup(ctx context.Context, group musiclibrary.Group) (int, error) {
+	logrus.Debug("Creating group")
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (groupname) VALUES (?) RETURNING id", groupsTable)
+	if err := r.db.GetContext(ctx, &id, query, group.GroupName); err != nil {
+		logrus.WithError(err).Error("Failed to create group")
+		return 0, dbError(err, "group")
+	}
+	logrus.WithField("id", id).Info("Group created successfully")
+	return id, nil
+}
+
+func (r *GroupSQLite) GetAllGroups(ctx context.Context) ([]musiclibrary.Group, error) {
+	logrus.Debug("Fetching all groups")
+	var groupList []musiclibrary.Group
+	query := fmt.Sprintf("SELECT id, groupname FROM %s ORDER BY id", groupsTable)
+	if err := r.db.SelectContext(ctx, &groupList, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch all groups")
+		return nil, dbError(err, "group")
+	}
+	logrus.WithField("count", len(groupList)).Info("Fetched all groups successfully")
+	return groupList, nil
+}
+
+func (r *GroupSQLite) GetGroupById(ctx context.Context, id int) (musiclibrary.Group, error) {
+	var group musiclibrary.Group
+	query := fmt.Sprintf("SELECT id, groupname FROM %s WHERE id = ?", groupsTable)
+	if err := r.db.GetContext(ctx, &group, query, id); err != nil {
+		logrus.WithError(err).Error("Failed to fetch group")
+		return group, dbError(err, "group")
+	}
+	return group, nil
+}
+
+func (r *GroupSQLite) DeleteGroup(ctx context.Context, id int) error {
+	logrus.WithField("id", id).Debug("Deleting group")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", groupsTable)
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete group")
+		return dbError(err, "group")
+	}
+	if err := affected(res, "group"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Group deleted successfully")
+	return nil
+}
+
+func (r *GroupSQLite) UpdateGroup(ctx context.Context, id int, input musiclibrary.UpdateGroupInput) error {
+	logrus.WithField("id", id).Debug("Updating group")
+	if input.GroupName == nil {
+		return exists(ctx, r.db, fmt.Sprintf("SELECT 1 FROM %s WHERE id = ?", groupsTable), id, "group")
+	}
+	query := fmt.Sprintf("UPDATE %s SET groupname = ? WHERE id = ?", groupsTable)
+	res, err := r.db.ExecContext(ctx, query, *input.GroupName, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update group")
+		return dbError(err, "group")
+	}
+	if err := affected(res, "group"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Group updated successfully")
+	return nil
+}
+
+func (r *GroupSQLite) GetGroupsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Group], error) {
+	var conditions []string
+	var args []interface{}
+	argId := 1
+
+	score := ""
+	fuzzy := filters["match"] == matchFuzzy
+
+	if group, ok := filters["groupname"]; ok && group != "" {
+		if fuzzy {
+			var condition string
+			condition, score = sqliteFuzzyMatch("groupname", argId)
+			conditions = append(conditions, condition)
+			args = append(args, group)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("groupname LIKE ?%d", argId))
+			args = append(args, "%"+group+"%")
+		}
+		argId++
+	}
+
+	// Genres and tags are not stored in SQLite, so no group has any.
+	if filters["genre"] != "" || filters["tag"] != "" {
+		conditions = append(conditions, "0")
+	}
+
+	query := `SELECT id, groupname FROM groupss WHERE 1=1`
+	if score != "" {
+		query = fmt.Sprintf(`SELECT id, groupname, %s AS score FROM groupss WHERE 1=1`, score)
+	}
+
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	return selectPage(ctx, r.db, query, args, scoredKeys(score != ""), page, sqlitePlaceholder, groupKey)
+}
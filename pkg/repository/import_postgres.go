@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportError   = "error"
+)
+
+// ArchiveRestorer writes the records of an archive into the library, in the
+// order Export writes them. Groups and songs are matched by name, ignoring
+// case, and created when missing.
+type ArchiveRestorer interface {
+	// Group returns the id of the group named name and whether it was created.
+	Group(name string) (int, bool, error)
+	// Song returns the id of the song named name in the group and whether it
+	// was created.
+	Song(groupId int, name string) (int, bool, error)
+	// SongDetails sets every detail of a song to those given, clearing the
+	// empty ones, and reports whether any of them changed.
+	SongDetails(songId int, details musiclibrary.SongDetails) (bool, error)
+}
+
+type ImportPostgres struct {
+	db *sqlx.DB
+}
+
+func NewImportPostgres(db *sqlx.DB) *ImportPostgres {
+	return &ImportPostgres{db: db}
+}
+
+// ImportSongs writes all rows in one transaction. Each row runs under its own
+// savepoint so a failing row is reported without aborting the others. With
+// dryRun the transaction is rolled back after the report is built, so the
+// report shows exactly what a real run would do.
+func (r *ImportPostgres) ImportSongs(ctx context.Context, rows []musiclibrary.ImportRow, dryRun bool) ([]musiclibrary.ImportResult, error) {
+	logrus.WithFields(logrus.Fields{
+		"rows":   len(rows),
+		"dryRun": dryRun,
+	}).Debug("Importing songs")
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]musiclibrary.ImportResult, 0, len(rows))
+	for _, row := range rows {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT import_row"); err != nil {
+			return nil, err
+		}
+		result, err := importRow(ctx, tx, row)
+		if err != nil {
+			logrus.WithError(err).WithField("line", row.Line).Warn("Failed to import row")
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_row"); err != nil {
+				return nil, err
+			}
+			result = musiclibrary.ImportResult{Status: ImportError, Reason: err.Error()}
+		} else if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT import_row"); err != nil {
+			return nil, err
+		}
+		result.Line, result.Group, result.Song = row.Line, row.Group, row.Song
+		results = append(results, result)
+	}
+
+	if dryRun {
+		logrus.Info("Dry run import finished, rolling back")
+		return results, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	logrus.WithField("rows", len(results)).Info("Songs imported successfully")
+	return results, nil
+}
+
+// RestoreArchive runs fn with a restorer bound to a single transaction. It is
+// committed when fn returns nil, unless dryRun is set, and rolled back
+// otherwise, so an archive is restored either completely or not at all.
+func (r *ImportPostgres) RestoreArchive(ctx context.Context, dryRun bool, fn func(w ArchiveRestorer) error) error {
+	logrus.WithField("dryRun", dryRun).Debug("Restoring archive")
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&archiveRestorerPostgres{ctx: ctx, tx: tx, created: map[int]bool{}}); err != nil {
+		logrus.WithError(err).Warn("Failed to restore archive")
+		return err
+	}
+	if dryRun {
+		logrus.Info("Dry run restore finished, rolling back")
+		return nil
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	logrus.Info("Archive restored successfully")
+	return nil
+}
+
+type archiveRestorerPostgres struct {
+	ctx     context.Context
+	tx      *sqlx.Tx
+	created map[int]bool // songs created by this restore
+}
+
+func (r *archiveRestorerPostgres) Group(name string) (int, bool, error) {
+	return findOrCreateGroup(r.ctx, r.tx, name)
+}
+
+func (r *archiveRestorerPostgres) Song(groupId int, name string) (int, bool, error) {
+	var songId int
+	query := fmt.Sprintf("SELECT id FROM %s WHERE groupId = $1 AND LOWER(songName) = LOWER($2) ORDER BY id LIMIT 1", songsTable)
+	err := r.tx.GetContext(r.ctx, &songId, query, groupId, name)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return songId, false, err
+	}
+	query = fmt.Sprintf("INSERT INTO %s (songName, groupId) VALUES ($1, $2) RETURNING id", songsTable)
+	if err := r.tx.GetContext(r.ctx, &songId, query, name, groupId); err != nil {
+		return 0, false, err
+	}
+	query = fmt.Sprintf("INSERT INTO %s (songId, releaseDate, text, link) VALUES ($1, NULL, '', '')", songDetailsTable)
+	if _, err := r.tx.ExecContext(r.ctx, query, songId); err != nil {
+		return 0, false, err
+	}
+	r.created[songId] = true
+	return songId, true, nil
+}
+
+// SongDetails writes the details of a song created by this restore as they
+// are. Those of an existing song are changed like a rollback, as a new
+// revision, and only when they differ.
+func (r *archiveRestorerPostgres) SongDetails(songId int, details musiclibrary.SongDetails) (bool, error) {
+	if r.created[songId] {
+		query := fmt.Sprintf("UPDATE %s SET releaseDate = NULLIF($2, '')::date, text = $3, link = $4 WHERE songId = $1", songDetailsTable)
+		_, err := r.tx.ExecContext(r.ctx, query, songId, details.ReleaseDate, details.Text, details.Link)
+		return true, err
+	}
+
+	var current musiclibrary.SongDetails
+	query := fmt.Sprintf(`SELECT COALESCE(TO_CHAR(releaseDate, 'YYYY-MM-DD'), '') AS releasedate,
+		COALESCE(text, '') AS text, COALESCE(link, '') AS link FROM %s WHERE songId = $1`, songDetailsTable)
+	err := r.tx.GetContext(r.ctx, &current, query, songId)
+	if errors.Is(err, sql.ErrNoRows) {
+		// A song without details gets them, as every other song has.
+		query = fmt.Sprintf("INSERT INTO %s (songId) VALUES ($1)", songDetailsTable)
+		if _, err := r.tx.ExecContext(r.ctx, query, songId); err != nil {
+			return false, err
+		}
+	} else if err != nil {
+		return false, err
+	} else if current.ReleaseDate == details.ReleaseDate && current.Text == details.Text && current.Link == details.Link {
+		return false, nil
+	}
+	input := musiclibrary.UpdateSongDetailsInput{
+		ReleaseDate: details.ReleaseDate,
+		Text:        details.Text,
+		Link:        details.Link,
+		Author:      "import",
+		Comment:     "Archive restore",
+	}
+	return true, restoreSongDetails(r.ctx, r.tx, songId, input)
+}
+
+// findOrCreateGroup returns the id of the group named name, ignoring case,
+// and whether it had to be created.
+func findOrCreateGroup(ctx context.Context, tx *sqlx.Tx, name string) (int, bool, error) {
+	var groupId int
+	query := fmt.Sprintf("SELECT id FROM %s WHERE LOWER(groupName) = LOWER($1) ORDER BY id LIMIT 1", groupsTable)
+	err := tx.GetContext(ctx, &groupId, query, name)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return groupId, false, err
+	}
+	query = fmt.Sprintf("INSERT INTO %s (groupName) VALUES ($1) RETURNING id", groupsTable)
+	if err := tx.GetContext(ctx, &groupId, query, name); err != nil {
+		return 0, false, err
+	}
+	return groupId, true, nil
+}
+
+func importRow(ctx context.Context, tx *sqlx.Tx, row musiclibrary.ImportRow) (musiclibrary.ImportResult, error) {
+	groupId, _, err := findOrCreateGroup(ctx, tx, row.Group)
+	if err != nil {
+		return musiclibrary.ImportResult{}, err
+	}
+
+	var songId int
+	query := fmt.Sprintf("SELECT id FROM %s WHERE groupId = $1 AND LOWER(songName) = LOWER($2) ORDER BY id LIMIT 1", songsTable)
+	err = tx.GetContext(ctx, &songId, query, groupId, row.Song)
+	if errors.Is(err, sql.ErrNoRows) {
+		query = fmt.Sprintf("INSERT INTO %s (songName, groupId) VALUES ($1, $2) RETURNING id", songsTable)
+		if err := tx.GetContext(ctx, &songId, query, row.Song, groupId); err != nil {
+			return musiclibrary.ImportResult{}, err
+		}
+		query = fmt.Sprintf(`INSERT INTO %s (songId, releaseDate, text, link)
+			VALUES ($1, NULLIF($2, '')::date, COALESCE(NULLIF($3, ''), 'N/A'), COALESCE(NULLIF($4, ''), 'N/A'))`, songDetailsTable)
+		if _, err := tx.ExecContext(ctx, query, songId, row.ReleaseDate, row.Text, row.Link); err != nil {
+			return musiclibrary.ImportResult{}, err
+		}
+		return musiclibrary.ImportResult{Status: ImportCreated, SongId: songId}, nil
+	}
+	if err != nil {
+		return musiclibrary.ImportResult{}, err
+	}
+
+	// The song exists already, only fields that are given and differ are updated.
+	var current struct {
+		ReleaseDate string `db:"releasedate"`
+		Text        string `db:"text"`
+		Link        string `db:"link"`
+	}
+	query = fmt.Sprintf(`SELECT COALESCE(TO_CHAR(releaseDate, 'YYYY-MM-DD'), '') AS releasedate,
+		COALESCE(text, '') AS text, COALESCE(link, '') AS link FROM %s WHERE songId = $1`, songDetailsTable)
+	if err := tx.GetContext(ctx, &current, query, songId); err != nil {
+		return musiclibrary.ImportResult{}, err
+	}
+
+	input := musiclibrary.UpdateSongDetailsInput{Author: "import", Comment: "Bulk import"}
+	if row.ReleaseDate != current.ReleaseDate {
+		input.ReleaseDate = row.ReleaseDate
+	}
+	if row.Text != current.Text {
+		input.Text = row.Text
+	}
+	if row.Link != current.Link {
+		input.Link = row.Link
+	}
+	updated, err := updateSongDetails(ctx, tx, songId, input)
+	if err != nil {
+		return musiclibrary.ImportResult{}, err
+	}
+	if !updated {
+		return musiclibrary.ImportResult{Status: ImportSkipped, Reason: "already exists with the same details", SongId: songId}, nil
+	}
+	return musiclibrary.ImportResult{Status: ImportUpdated, SongId: songId}, nil
+}
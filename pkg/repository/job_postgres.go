@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobDead    = "dead"
+)
+
+// ErrJobLost is returned when recording the outcome of a job whose lease ran
+// out and which another worker has claimed since.
+var ErrJobLost = errors.New("job lease lost, another worker claimed it")
+
+const jobColumns = `id, type, payload, status, attempts, maxAttempts AS maxattempts, claims, lastError AS lasterror,
+	TO_CHAR(runAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS runat,
+	TO_CHAR(createdAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS createdat,
+	TO_CHAR(updatedAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS updatedat`
+
+type JobPostgres struct {
+	db *sqlx.DB
+}
+
+func NewJobPostgres(db *sqlx.DB) *JobPostgres {
+	return &JobPostgres{db: db}
+}
+
+func (r *JobPostgres) EnqueueJob(ctx context.Context, jobType string, payload []byte, maxAttempts int) (int, error) {
+	logrus.WithField("type", jobType).Debug("Enqueueing job")
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (type, payload, maxAttempts) VALUES ($1, $2, $3) RETURNING id", jobsTable)
+	row := r.db.QueryRowContext(ctx, query, jobType, string(payload), maxAttempts)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to enqueue job")
+		return 0, err
+	}
+	logrus.WithField("id", id).Info("Job enqueued successfully")
+	return id, nil
+}
+
+// ClaimJob takes the next due job and leases it to the caller. SKIP LOCKED
+// lets workers claim concurrently without waiting on each other, and a
+// running job whose lease ran out (its worker died) is handed out again.
+// It returns sql.ErrNoRows when there is nothing to do.
+func (r *JobPostgres) ClaimJob(ctx context.Context, lease time.Duration) (musiclibrary.Job, error) {
+	var job musiclibrary.Job
+	query := fmt.Sprintf(`UPDATE %[1]s SET status = '%[2]s', attempts = attempts + 1, claims = claims + 1,
+			lockedUntil = now() + $1 * INTERVAL '1 millisecond', updatedAt = now()
+		WHERE id = (
+			SELECT id FROM %[1]s
+			WHERE (status = '%[3]s' AND runAt <= now()) OR (status = '%[2]s' AND lockedUntil < now())
+			ORDER BY runAt, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %[4]s`, jobsTable, JobRunning, JobQueued, jobColumns)
+	err := r.db.GetContext(ctx, &job, query, lease.Milliseconds())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logrus.WithError(err).Error("Failed to claim job")
+	}
+	return job, err
+}
+
+// CompleteJob, RescheduleJob and DeadLetterJob record the outcome of a run,
+// claim is the Claims of the job as ClaimJob returned it. Should the lease
+// have run out and the job been claimed again meanwhile, they leave it alone
+// and return ErrJobLost, the run that holds it now records its own outcome.
+func (r *JobPostgres) CompleteJob(ctx context.Context, id, claim int) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = '%s', lockedUntil = NULL, lastError = '', updatedAt = now()
+		WHERE id = $1 AND status = '%s' AND claims = $2`, jobsTable, JobDone, JobRunning)
+	if err := r.finishJob(ctx, query, id, claim); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Job completed")
+	return nil
+}
+
+// RescheduleJob puts a failed job back in the queue to run again after delay.
+func (r *JobPostgres) RescheduleJob(ctx context.Context, id, claim int, jobErr string, delay time.Duration) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = '%s', lockedUntil = NULL, lastError = $3,
+			runAt = now() + $4 * INTERVAL '1 millisecond', updatedAt = now()
+		WHERE id = $1 AND status = '%s' AND claims = $2`, jobsTable, JobQueued, JobRunning)
+	if err := r.finishJob(ctx, query, id, claim, jobErr, delay.Milliseconds()); err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"id":    id,
+		"delay": delay,
+	}).Warn("Job failed, rescheduled")
+	return nil
+}
+
+// DeadLetterJob parks a job that will not be retried automatically.
+func (r *JobPostgres) DeadLetterJob(ctx context.Context, id, claim int, jobErr string) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = '%s', lockedUntil = NULL, lastError = $3, updatedAt = now()
+		WHERE id = $1 AND status = '%s' AND claims = $2`, jobsTable, JobDead, JobRunning)
+	if err := r.finishJob(ctx, query, id, claim, jobErr); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Error("Job moved to dead letters")
+	return nil
+}
+
+// finishJob runs query, which updates a running job by its id and claim.
+func (r *JobPostgres) finishJob(ctx context.Context, query string, args ...any) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to record job outcome")
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrJobLost
+	}
+	return nil
+}
+
+// RequeueJob runs a dead job again with a fresh set of attempts. Its claims
+// count on, so earlier runs stay fenced off. It returns sql.ErrNoRows when
+// there is no dead job with that id.
+func (r *JobPostgres) RequeueJob(ctx context.Context, id int) error {
+	logrus.WithField("id", id).Debug("Requeueing job")
+	query := fmt.Sprintf(`UPDATE %s SET status = '%s', attempts = 0, runAt = now(), updatedAt = now()
+		WHERE id = $1 AND status = '%s' RETURNING id`, jobsTable, JobQueued, JobDead)
+	var requeued int
+	if err := r.db.GetContext(ctx, &requeued, query, id); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logrus.WithError(err).Error("Failed to requeue job")
+		}
+		return err
+	}
+	logrus.WithField("id", id).Info("Job requeued successfully")
+	return nil
+}
+
+func (r *JobPostgres) GetJobs(ctx context.Context, status string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Job], error) {
+	logrus.WithField("status", status).Debug("Fetching jobs")
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE $1 = '' OR status = $1`, jobColumns, jobsTable)
+	jobs, err := selectPage(ctx, r.db, query, []any{status}, sortByIdDesc, page, pgPlaceholder, func(job musiclibrary.Job) []any {
+		return []any{job.Id}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch jobs")
+		return musiclibrary.Page[musiclibrary.Job]{}, dbError(err, "job")
+	}
+	logrus.WithField("count", len(jobs.Items)).Info("Fetched jobs successfully")
+	return jobs, nil
+}
+
+func (r *JobPostgres) GetJobById(ctx context.Context, id int) (musiclibrary.Job, error) {
+	var job musiclibrary.Job
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", jobColumns, jobsTable)
+	err := r.db.GetContext(ctx, &job, query, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logrus.WithError(err).Error("Failed to fetch job")
+	}
+	return job, dbError(err, "job")
+}
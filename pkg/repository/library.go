@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	musiclibrary "time-tracker"
+)
+
+// sortRating is the value of the "sort" filter of songs that orders them by
+// average rating, best first, ahead of any fuzzy match score.
+const sortRating = "rating"
+
+// favoritesOf returns the user whose favorites the "favoriteof" filter of
+// songs restricts them to, 0 when it is not set.
+func favoritesOf(filters map[string]string) (int, error) {
+	value := filters["favoriteof"]
+	if value == "" {
+		return 0, nil
+	}
+	userId, err := strconv.Atoi(value)
+	if err != nil || userId < 1 {
+		return 0, musiclibrary.NewValidationError("favoriteof", "must be a user ID")
+	}
+	return userId, nil
+}
+
+// insertItem returns the item ids of a playlist with id inserted at
+// position, counting from 1, or appended when position is 0.
+func insertItem(ids []int, id, position int) ([]int, error) {
+	if position == 0 {
+		position = len(ids) + 1
+	}
+	if position < 1 || position > len(ids)+1 {
+		return nil, musiclibrary.NewValidationError("position", fmt.Sprintf("must be between 1 and %d", len(ids)+1))
+	}
+	order := make([]int, 0, len(ids)+1)
+	order = append(order, ids[:position-1]...)
+	order = append(order, id)
+	return append(order, ids[position-1:]...), nil
+}
+
+// reorderItems checks that wanted lists each of the item ids of a playlist
+// exactly once.
+func reorderItems(ids, wanted []int) error {
+	err := musiclibrary.NewValidationError("itemIds", "must list every item of the playlist once")
+	if len(wanted) != len(ids) {
+		return err
+	}
+	pending := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		pending[id] = true
+	}
+	for _, id := range wanted {
+		if !pending[id] {
+			return err
+		}
+		delete(pending, id)
+	}
+	return nil
+}
+
+// withoutItem returns the item ids of a playlist without id.
+func withoutItem(ids []int, id int) []int {
+	order := make([]int, 0, len(ids))
+	for _, itemId := range ids {
+		if itemId != id {
+			order = append(order, itemId)
+		}
+	}
+	return order
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	musiclibrary "time-tracker"
+)
+
+type memoryUserSong struct {
+	userId, songId int
+}
+
+type LibraryMemory struct {
+	store *memoryStore
+}
+
+// AddFavorite stars a song. Starring it again changes nothing.
+func (r *LibraryMemory) AddFavorite(ctx context.Context, userId, songId int) error {
+	return r.store.write(ctx, func() error {
+		if err := r.store.checkUserSong(userId, songId, "favorite"); err != nil {
+			return err
+		}
+		key := memoryUserSong{userId, songId}
+		if _, ok := r.store.favorites[key]; !ok {
+			r.store.favorites[key] = memoryNow()
+		}
+		return nil
+	})
+}
+
+func (r *LibraryMemory) RemoveFavorite(ctx context.Context, userId, songId int) error {
+	return r.store.write(ctx, func() error {
+		key := memoryUserSong{userId, songId}
+		if _, ok := r.store.favorites[key]; !ok {
+			return notFound("favorite")
+		}
+		delete(r.store.favorites, key)
+		return nil
+	})
+}
+
+func (r *LibraryMemory) GetFavorites(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Favorite], error) {
+	var result musiclibrary.Page[musiclibrary.Favorite]
+	err := r.store.read(ctx, func() error {
+		var favorites []musiclibrary.Favorite
+		for key, createdAt := range r.store.favorites {
+			if key.userId == userId {
+				favorites = append(favorites, musiclibrary.Favorite{
+					SongId:    key.songId,
+					SongName:  r.store.songs[key.songId].SongName,
+					CreatedAt: createdAt,
+				})
+			}
+		}
+		sort.Slice(favorites, func(i, j int) bool {
+			return favorites[i].SongId < favorites[j].SongId
+		})
+		var err error
+		result, err = pageSlice(favorites, sortBySongId, page, func(f musiclibrary.Favorite) []any {
+			return []any{f.SongId}
+		})
+		return err
+	})
+	return result, err
+}
+
+// RateSong rates a song or changes the rating given before.
+func (r *LibraryMemory) RateSong(ctx context.Context, userId, songId, rating int) error {
+	return r.store.write(ctx, func() error {
+		if rating < 1 || rating > 5 {
+			return musiclibrary.NewValidationError("rating", "must be between 1 and 5")
+		}
+		if err := r.store.checkUserSong(userId, songId, "rating"); err != nil {
+			return err
+		}
+		r.store.ratings[memoryUserSong{userId, songId}] = musiclibrary.Rating{SongId: songId, Rating: rating, UpdatedAt: memoryNow()}
+		return nil
+	})
+}
+
+func (r *LibraryMemory) RemoveRating(ctx context.Context, userId, songId int) error {
+	return r.store.write(ctx, func() error {
+		key := memoryUserSong{userId, songId}
+		if _, ok := r.store.ratings[key]; !ok {
+			return notFound("rating")
+		}
+		delete(r.store.ratings, key)
+		return nil
+	})
+}
+
+func (r *LibraryMemory) GetRatings(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Rating], error) {
+	var result musiclibrary.Page[musiclibrary.Rating]
+	err := r.store.read(ctx, func() error {
+		var ratings []musiclibrary.Rating
+		for key, rating := range r.store.ratings {
+			if key.userId == userId {
+				rating.SongName = r.store.songs[key.songId].SongName
+				ratings = append(ratings, rating)
+			}
+		}
+		sort.Slice(ratings, func(i, j int) bool {
+			return ratings[i].SongId < ratings[j].SongId
+		})
+		var err error
+		result, err = pageSlice(ratings, sortBySongId, page, func(r musiclibrary.Rating) []any {
+			return []any{r.SongId}
+		})
+		return err
+	})
+	return result, err
+}
+
+// averageRating is the mean rating of a song, 0 when nobody rated it. The
+// caller holds the lock.
+func (s *memoryStore) averageRating(songId int) float64 {
+	var sum, count int
+	for key, rating := range s.ratings {
+		if key.songId == songId {
+			sum += rating.Rating
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(sum) / float64(count)
+}
+
+// checkUserSong is the foreign key check of rows referring to a user and a
+// song. The caller holds the lock.
+func (s *memoryStore) checkUserSong(userId, songId int, entity string) error {
+	_, userOk := s.users[userId]
+	_, songOk := s.songs[songId]
+	if !userOk || !songOk {
+		return musiclibrary.NewForeignKeyError(foreignKeyMessage(entity))
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type LibraryPostgres struct {
+	db *sqlx.DB
+}
+
+func NewLibraryPostgres(db *sqlx.DB) *LibraryPostgres {
+	return &LibraryPostgres{db: db}
+}
+
+// AddFavorite stars a song. Starring it again changes nothing.
+func (r *LibraryPostgres) AddFavorite(ctx context.Context, userId, songId int) error {
+	logrus.WithFields(logrus.Fields{"userId": userId, "songId": songId}).Debug("Adding favorite")
+	query := fmt.Sprintf("INSERT INTO %s (userId, songId) VALUES ($1, $2) ON CONFLICT DO NOTHING", favoritesTable)
+	if _, err := r.db.ExecContext(ctx, query, userId, songId); err != nil {
+		logrus.WithError(err).Error("Failed to add favorite")
+		return dbError(err, "favorite")
+	}
+	return nil
+}
+
+func (r *LibraryPostgres) RemoveFavorite(ctx context.Context, userId, songId int) error {
+	logrus.WithFields(logrus.Fields{"userId": userId, "songId": songId}).Debug("Removing favorite")
+	query := fmt.Sprintf("DELETE FROM %s WHERE userId = $1 AND songId = $2", favoritesTable)
+	res, err := r.db.ExecContext(ctx, query, userId, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove favorite")
+		return dbError(err, "favorite")
+	}
+	return affected(res, "favorite")
+}
+
+func (r *LibraryPostgres) GetFavorites(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Favorite], error) {
+	logrus.WithField("userId", userId).Debug("Fetching favorites")
+	query := fmt.Sprintf(`
+		SELECT f.songId AS songid, s.songName AS songname,
+			TO_CHAR(f.createdAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS createdat
+		FROM %s f
+		JOIN %s s ON s.id = f.songId
+		WHERE f.userId = $1`, favoritesTable, songsTable)
+	favorites, err := selectPage(ctx, r.db, query, []any{userId}, sortBySongId, page, pgPlaceholder, func(f musiclibrary.Favorite) []any {
+		return []any{f.SongId}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch favorites")
+		return musiclibrary.Page[musiclibrary.Favorite]{}, dbError(err, "favorite")
+	}
+	return favorites, nil
+}
+
+// RateSong rates a song or changes the rating given before.
+func (r *LibraryPostgres) RateSong(ctx context.Context, userId, songId, rating int) error {
+	logrus.WithFields(logrus.Fields{"userId": userId, "songId": songId}).Debug("Rating song")
+	query := fmt.Sprintf(`
+		INSERT INTO %s (userId, songId, rating) VALUES ($1, $2, $3)
+		ON CONFLICT (userId, songId) DO UPDATE SET rating = EXCLUDED.rating, updatedAt = now()`, ratingsTable)
+	if _, err := r.db.ExecContext(ctx, query, userId, songId, rating); err != nil {
+		logrus.WithError(err).Error("Failed to rate song")
+		return dbError(err, "rating")
+	}
+	return nil
+}
+
+func (r *LibraryPostgres) RemoveRating(ctx context.Context, userId, songId int) error {
+	logrus.WithFields(logrus.Fields{"userId": userId, "songId": songId}).Debug("Removing rating")
+	query := fmt.Sprintf("DELETE FROM %s WHERE userId = $1 AND songId = $2", ratingsTable)
+	res, err := r.db.ExecContext(ctx, query, userId, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove rating")
+		return dbError(err, "rating")
+	}
+	return affected(res, "rating")
+}
+
+func (r *LibraryPostgres) GetRatings(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Rating], error) {
+	logrus.WithField("userId", userId).Debug("Fetching ratings")
+	query := fmt.Sprintf(`
+		SELECT r.songId AS songid, s.songName AS songname, r.rating,
+			TO_CHAR(r.updatedAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS updatedat
+		FROM %s r
+		JOIN %s s ON s.id = r.songId
+		WHERE r.userId = $1`, ratingsTable, songsTable)
+	ratings, err := selectPage(ctx, r.db, query, []any{userId}, sortBySongId, page, pgPlaceholder, func(r musiclibrary.Rating) []any {
+		return []any{r.SongId}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch ratings")
+		return musiclibrary.Page[musiclibrary.Rating]{}, dbError(err, "rating")
+	}
+	return ratings, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type LibrarySQLite struct {
+	db *sqlx.DB
+}
+
+func NewLibrarySQLite(db *sqlx.DB) *LibrarySQLite {
+	return &LibrarySQLite{db: db}
+}
+
+// AddFavorite stars a song. Starring it again changes nothing.
+func (r *LibrarySQLite) AddFavorite(ctx context.Context, userId, songId int) error {
+	logrus.WithFields(logrus.Fields{"userId": userId, "songId": songId}).Debug("Adding favorite")
+	query := fmt.Sprintf("INSERT INTO %s (userid, songid) VALUES (?, ?) ON CONFLICT DO NOTHING", favoritesTable)
+	if _, err := r.db.ExecContext(ctx, query, userId, songId); err != nil {
+		logrus.WithError(err).Error("Failed to add favorite")
+		return dbError(err, "favorite")
+	}
+	return nil
+}
+
+func (r *LibrarySQLite) RemoveFavorite(ctx context.Context, userId, songId int) error {
+	logrus.WithFields(logrus.Fields{"userId": userId, "songId": songId}).Debug("Removing favorite")
+	query := fmt.Sprintf("DELETE FROM %s WHERE userid = ? AND songid = ?", favoritesTable)
+	res, err := r.db.ExecContext(ctx, query, userId, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove favorite")
+		return dbError(err, "favorite")
+	}
+	return affected(res, "favorite")
+}
+
+func (r *LibrarySQLite) GetFavorites(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Favorite], error) {
+	logrus.WithField("userId", userId).Debug("Fetching favorites")
+	query := fmt.Sprintf(`
+		SELECT f.songid, s.songname, f.createdat
+		FROM %s f
+		JOIN %s s ON s.id = f.songid
+		WHERE f.userid = ?1`, favoritesTable, songsTable)
+	favorites, err := selectPage(ctx, r.db, query, []any{userId}, sortBySongId, page, sqlitePlaceholder, func(f musiclibrary.Favorite) []any {
+		return []any{f.SongId}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch favorites")
+		return musiclibrary.Page[musiclibrary.Favorite]{}, dbError(err, "favorite")
+	}
+	return favorites, nil
+}
+
+// RateSong rates a song or changes the rating given before.
+func (r *LibrarySQLite) RateSong(ctx context.Context, userId, songId, rating int) error {
+	logrus.WithFields(logrus.Fields{"userId": userId, "songId": songId}).Debug("Rating song")
+	query := fmt.Sprintf(`
+		INSERT INTO %s (userid, songid, rating) VALUES (?, ?, ?)
+		ON CONFLICT (userid, songid) DO UPDATE SET rating = excluded.rating,
+			updatedat = strftime('%%Y-%%m-%%dT%%H:%%M:%%S', 'now')`, ratingsTable)
+	if _, err := r.db.ExecContext(ctx, query, userId, songId, rating); err != nil {
+		logrus.WithError(err).Error("Failed to rate song")
+		return dbError(err, "rating")
+	}
+	return nil
+}
+
+func (r *LibrarySQLite) RemoveRating(ctx context.Context, userId, songId int) error {
+	logrus.WithFields(logrus.Fields{"userId": userId, "songId": songId}).Debug("Removing rating")
+	query := fmt.Sprintf("DELETE FROM %s WHERE userid = ? AND songid = ?", ratingsTable)
+	res, err := r.db.ExecContext(ctx, query, userId, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove rating")
+		return dbError(err, "rating")
+	}
+	return affected(res, "rating")
+}
+
+func (r *LibrarySQLite) GetRatings(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Rating], error) {
+	logrus.WithField("userId", userId).Debug("Fetching ratings")
+	query := fmt.Sprintf(`
+		SELECT r.songid, s.songname, r.rating, r.updatedat
+		FROM %s r
+		JOIN %s s ON s.id = r.songid
+		WHERE r.userid = ?1`, ratingsTable, songsTable)
+	ratings, err := selectPage(ctx, r.db, query, []any{userId}, sortBySongId, page, sqlitePlaceholder, func(r musiclibrary.Rating) []any {
+		return []any{r.SongId}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch ratings")
+		return musiclibrary.Page[musiclibrary.Rating]{}, dbError(err, "rating")
+	}
+	return ratings, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const membershipSelect = `
+	SELECT m.id, m.groupId AS groupid, g.groupName AS groupname,
+		m.artistId AS artistid, a.name AS artistname, m.role,
+		COALESCE(TO_CHAR(m.startDate, 'YYYY-MM-DD'), '') AS startdate,
+		COALESCE(TO_CHAR(m.endDate, 'YYYY-MM-DD'), '') AS enddate
+	FROM groupMembers m
+	JOIN groupss g ON g.id = m.groupId
+	JOIN artists a ON a.id = m.artistId`
+
+type MembershipPostgres struct {
+	db *sqlx.DB
+}
+
+func NewMembershipPostgres(db *sqlx.DB) *MembershipPostgres {
+	return &MembershipPostgres{db: db}
+}
+
+func (r *MembershipPostgres) AddMember(ctx context.Context, groupId int, input musiclibrary.MembershipInput) (int, error) {
+	logrus.WithFields(logrus.Fields{
+		"groupId":  groupId,
+		"artistId": input.ArtistId,
+	}).Debug("Adding group member")
+	var id int
+	query := fmt.Sprintf(`INSERT INTO %s (groupId, artistId, role, startDate, endDate)
+		VALUES ($1, $2, $3, NULLIF($4, '')::date, NULLIF($5, '')::date) RETURNING id`, groupMembersTable)
+	row := r.db.QueryRowContext(ctx, query, groupId, input.ArtistId, input.Role, input.StartDate, input.EndDate)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to add group member")
+		return 0, dbError(err, "membership")
+	}
+	logrus.WithField("id", id).Info("Group member added successfully")
+	return id, nil
+}
+
+func (r *MembershipPostgres) RemoveMember(ctx context.Context, groupId, membershipId int) error {
+	logrus.WithField("id", membershipId).Debug("Removing group member")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND groupId = $2", groupMembersTable)
+	res, err := r.db.ExecContext(ctx, query, membershipId, groupId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove group member")
+		return dbError(err, "membership")
+	}
+	if err := affected(res, "membership"); err != nil {
+		return err
+	}
+	logrus.WithField("id", membershipId).Info("Group member removed successfully")
+	return nil
+}
+
+// GetGroupMembers returns the memberships of a group. When from and to are set
+// only the memberships overlapping that date range are returned, open ends
+// count as "since forever" and "until now".
+func (r *MembershipPostgres) GetGroupMembers(ctx context.Context, groupId int, from, to string) ([]musiclibrary.Membership, error) {
+	logrus.WithField("groupId", groupId).Debug("Fetching group members")
+	var members []musiclibrary.Membership
+	query := membershipSelect + ` WHERE m.groupId = $1`
+	args := []interface{}{groupId}
+	if from != "" && to != "" {
+		query += ` AND (m.startDate IS NULL OR m.startDate <= $3::date)
+			AND (m.endDate IS NULL OR m.endDate >= $2::date)`
+		args = append(args, from, to)
+	}
+	query += ` ORDER BY m.startDate NULLS FIRST, m.id`
+	err := r.db.SelectContext(ctx, &members, query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch group members")
+		return nil, dbError(err, "membership")
+	}
+	logrus.WithField("count", len(members)).Info("Fetched group members successfully")
+	return members, nil
+}
+
+func (r *MembershipPostgres) GetArtistGroups(ctx context.Context, artistId int) ([]musiclibrary.Membership, error) {
+	logrus.WithField("artistId", artistId).Debug("Fetching artist groups")
+	var memberships []musiclibrary.Membership
+	query := membershipSelect + ` WHERE m.artistId = $1 ORDER BY m.startDate NULLS FIRST, m.id`
+	err := r.db.SelectContext(ctx, &memberships, query, artistId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch artist groups")
+		return nil, dbError(err, "membership")
+	}
+	logrus.WithField("count", len(memberships)).Info("Fetched artist groups successfully")
+	return memberships, nil
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	musiclibrary "time-tracker"
+)
+
+// memoryStore keeps groups, songs and their details in maps. It follows the
+// Postgres schema: ids are sequences, details rows default to 'N/A' and
+// deleting a group or song cascades to everything that belongs to it.
+type memoryStore struct {
+	mu sync.RWMutex
+	// txMu serialises units of work, see UnitOfWorkMemory.
+	txMu sync.Mutex
+
+	groups    map[int]musiclibrary.Group
+	songs     map[int]musiclibrary.Song
+	details   map[int]musiclibrary.SongDetails // by song id
+	revisions map[int][]musiclibrary.Revision  // by song id
+	lrc       map[int]string                   // by song id
+	vectors   map[int]map[string]int           // term counts by song id
+
+	// Users and their keys are left alone by units of work.
+	users   map[int]musiclibrary.User
+	apiKeys map[int]memoryAPIKey
+
+	// Favorites, ratings, playlist items and plays go with their songs,
+	// playlists with their users.
+	favorites     map[memoryUserSong]string // when starred
+	ratings       map[memoryUserSong]musiclibrary.Rating
+	playlists     map[int]musiclibrary.Playlist
+	playlistItems map[int][]memoryPlaylistItem // by playlist id, in order
+	plays         map[int]musiclibrary.Play
+
+	lastGroupId, lastSongId, lastDetailsId, lastRevisionId int
+	lastUserId, lastAPIKeyId                               int
+	lastPlaylistId, lastPlaylistItemId, lastPlayId         int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		groups:    map[int]musiclibrary.Group{},
+		songs:     map[int]musiclibrary.Song{},
+		details:   map[int]musiclibrary.SongDetails{},
+		revisions: map[int][]musiclibrary.Revision{},
+		lrc:       map[int]string{},
+		vectors:   map[int]map[string]int{},
+		users:     map[int]musiclibrary.User{},
+		apiKeys:   map[int]memoryAPIKey{},
+
+		favorites:     map[memoryUserSong]string{},
+		ratings:       map[memoryUserSong]musiclibrary.Rating{},
+		playlists:     map[int]musiclibrary.Playlist{},
+		playlistItems: map[int][]memoryPlaylistItem{},
+		plays:         map[int]musiclibrary.Play{},
+	}
+}
+
+// NewMemoryRepository returns repositories that keep everything in memory.
+// Groups, songs, song details, their revisions, users, their favorites,
+// ratings, playlists, plays and the term counts of lyrics are fully
+// supported, the remaining repositories return ErrNotSupported.
+func NewMemoryRepository() *Repository {
+	store := newMemoryStore()
+	return &Repository{
+		Group:         &GroupMemory{store: store},
+		Authorisation: &SongMemory{store: store},
+		SongDetails:   &SongDetailsMemory{store: store},
+		Album:         unsupported{},
+		Artist:        unsupported{},
+		Membership:    unsupported{},
+		Genre:         unsupported{},
+		Tag:           unsupported{},
+		Search:        unsupported{},
+		Translation:   unsupported{},
+		Revision:      &RevisionMemory{store: store},
+		Import:        unsupported{},
+		Export:        unsupported{},
+		Job:           unsupported{},
+		User:          &UserMemory{store: store},
+		Library:       &LibraryMemory{store: store},
+		Playlist:      &PlaylistMemory{store: store},
+		Play:          &PlayMemory{store: store},
+		Similarity:    &SimilarityMemory{store: store},
+		UnitOfWork:    &UnitOfWorkMemory{store: store},
+	}
+}
+
+func (s *memoryStore) read(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn()
+}
+
+func (s *memoryStore) write(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn()
+}
+
+// deleteSong removes a song and everything that cascades from it. The caller
+// holds the write lock.
+func (s *memoryStore) deleteSong(id int) {
+	delete(s.songs, id)
+	delete(s.details, id)
+	delete(s.revisions, id)
+	delete(s.lrc, id)
+	delete(s.vectors, id)
+	for key := range s.favorites {
+		if key.songId == id {
+			delete(s.favorites, key)
+		}
+	}
+	for key := range s.ratings {
+		if key.songId == id {
+			delete(s.ratings, key)
+		}
+	}
+	for playlistId, items := range s.playlistItems {
+		kept := items[:0:0]
+		for _, item := range items {
+			if item.songId != id {
+				kept = append(kept, item)
+			}
+		}
+		s.playlistItems[playlistId] = kept
+	}
+	for playId, play := range s.plays {
+		if play.SongId == id {
+			delete(s.plays, playId)
+		}
+	}
+}
+
+// saveRevision snapshots the current details of a song as its next
+// revision. The caller holds the write lock.
+func (s *memoryStore) saveRevision(songId int, author, comment string) {
+	d := s.details[songId]
+	s.lastRevisionId++
+	s.revisions[songId] = append(s.revisions[songId], musiclibrary.Revision{
+		Id:          s.lastRevisionId,
+		SongId:      songId,
+		Revision:    len(s.revisions[songId]) + 1,
+		ReleaseDate: d.ReleaseDate,
+		Text:        d.Text,
+		Link:        d.Link,
+		Author:      author,
+		Comment:     comment,
+		CreatedAt:   memoryNow(),
+	})
+}
+
+// memoryNow formats the current time like the timestamps read from
+// Postgres.
+func memoryNow() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05")
+}
+
+func (s *memoryStore) snapshot() *memoryStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c := newMemoryStore()
+	for k, v := range s.groups {
+		c.groups[k] = v
+	}
+	for k, v := range s.songs {
+		c.songs[k] = v
+	}
+	for k, v := range s.details {
+		c.details[k] = v
+	}
+	for k, v := range s.revisions {
+		c.revisions[k] = append([]musiclibrary.Revision(nil), v...)
+	}
+	for k, v := range s.lrc {
+		c.lrc[k] = v
+	}
+	for k, v := range s.vectors {
+		c.vectors[k] = v
+	}
+	for k, v := range s.favorites {
+		c.favorites[k] = v
+	}
+	for k, v := range s.ratings {
+		c.ratings[k] = v
+	}
+	for k, v := range s.playlistItems {
+		c.playlistItems[k] = append([]memoryPlaylistItem(nil), v...)
+	}
+	for k, v := range s.plays {
+		c.plays[k] = v
+	}
+	c.lastGroupId, c.lastSongId, c.lastDetailsId, c.lastRevisionId = s.lastGroupId, s.lastSongId, s.lastDetailsId, s.lastRevisionId
+	return c
+}
+
+func (s *memoryStore) restore(c *memoryStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups, s.songs, s.details, s.revisions, s.lrc, s.vectors = c.groups, c.songs, c.details, c.revisions, c.lrc, c.vectors
+	s.favorites, s.ratings, s.plays = c.favorites, c.ratings, c.plays
+	// Playlists created meanwhile keep their items.
+	for k, v := range c.playlistItems {
+		if _, ok := s.playlists[k]; ok {
+			s.playlistItems[k] = v
+		}
+	}
+	// Sequences are not rolled back in Postgres either.
+}
+
+// paginate applies LIMIT and OFFSET the way Postgres does.
+func paginate[T any](items []T, page, limit int) ([]T, error) {
+	offset := (page - 1) * limit
+	if offset < 0 {
+		return nil, &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: "OFFSET must not be negative"}
+	}
+	if limit < 0 {
+		return nil, &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: "LIMIT must not be negative"}
+	}
+	if offset >= len(items) {
+		return nil, nil
+	}
+	return items[offset:min(offset+limit, len(items))], nil
+}
+
+// likeToken is a parsed element of a LIKE pattern: any run of characters
+// (%), a single character (_) or a literal rune.
+type likeToken struct {
+	kind byte // '%', '_' or 0 for a literal
+	r    rune
+}
+
+// ilike matches value against a Postgres ILIKE pattern, % matching any run of
+// characters and _ a single one, with backslash as the escape character.
+// It backtracks only to the last %, which takes O(len(value) * len(pattern))
+// time at worst, however many % the pattern has.
+func ilike(value, pattern string) bool {
+	v := []rune(strings.ToLower(value))
+	p := parseLike([]rune(strings.ToLower(pattern)))
+
+	i, j := 0, 0
+	star, starI := -1, 0
+	for i < len(v) {
+		switch {
+		case j < len(p) && p[j].kind == '%':
+			star, starI = j, i
+			j++
+		case j < len(p) && (p[j].kind == '_' || p[j].r == v[i]):
+			i++
+			j++
+		case star >= 0:
+			// Let the last % take one more character and try again.
+			starI++
+			i, j = starI, star+1
+		default:
+			return false
+		}
+	}
+	for j < len(p) && p[j].kind == '%' {
+		j++
+	}
+	return j == len(p)
+}
+
+func parseLike(pattern []rune) []likeToken {
+	tokens := make([]likeToken, 0, len(pattern))
+	for j := 0; j < len(pattern); j++ {
+		switch r := pattern[j]; r {
+		case '%', '_':
+			tokens = append(tokens, likeToken{kind: byte(r)})
+		case '\\':
+			if j+1 < len(pattern) {
+				j++
+			}
+			tokens = append(tokens, likeToken{r: pattern[j]})
+		default:
+			tokens = append(tokens, likeToken{r: r})
+		}
+	}
+	return tokens
+}
+
+// contains is the in-memory counterpart of column ILIKE '%' || term || '%'.
+func contains(value, term string) bool {
+	return ilike(value, "%"+term+"%")
+}
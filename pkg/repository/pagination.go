@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+)
+
+// sortKey is a column a list is ordered by. The last key of every order is
+// unique, so each row has its own position a cursor can point at.
+type sortKey struct {
+	column string
+	desc   bool
+}
+
+var (
+	sortById     = []sortKey{{column: "id"}}
+	sortByIdDesc = []sortKey{{column: "id", desc: true}}
+	sortByName   = []sortKey{{column: "name"}, {column: "id"}}
+	sortByScore  = []sortKey{{column: "score", desc: true}, {column: "id"}}
+	sortByRank   = []sortKey{{column: "rank", desc: true}, {column: "songid"}}
+	sortBySongId = []sortKey{{column: "songid"}}
+	sortByRating = []sortKey{{column: "rating", desc: true}, {column: "id"}}
+)
+
+var errInvalidCursor = musiclibrary.NewValidationError("after", "is not a cursor returned by this list")
+
+// checkPage rejects limits and offsets SQLite would read as no limit and no
+// offset rather than refuse as Postgres does.
+func checkPage(page musiclibrary.PageRequest) error {
+	if page.Limit < 1 {
+		return musiclibrary.NewValidationError("limit", "must be at least 1")
+	}
+	if page.Offset < 0 {
+		return musiclibrary.NewValidationError("page", "must be at least 1")
+	}
+	return nil
+}
+
+// encodeCursor makes an opaque cursor of the sort key values of a row.
+func encodeCursor(values []any) string {
+	b, err := json.Marshal(values)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor returns the sort key values a cursor points at, nil for the
+// empty cursor of the first page. Numbers come back as float64, which
+// compares equal to the integer and real columns they were read from.
+func decodeCursor(cursor string, keys []sortKey) ([]any, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var values []any
+	if err := json.Unmarshal(b, &values); err != nil || len(values) != len(keys) {
+		return nil, errInvalidCursor
+	}
+	for _, v := range values {
+		switch v.(type) {
+		case float64, string:
+		default:
+			return nil, errInvalidCursor
+		}
+	}
+	return values, nil
+}
+
+func pgPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func sqlitePlaceholder(n int) string {
+	return fmt.Sprintf("?%d", n)
+}
+
+// keysetQuery wraps query, a SELECT without ORDER BY or LIMIT whose columns
+// include the sort keys, so that it returns the rows after the cursor of
+// page and one more, which tells whether there is a next page. placeholder
+// formats the n-th bind parameter of the database.
+func keysetQuery(query string, args []any, keys []sortKey, page musiclibrary.PageRequest, placeholder func(int) string) (string, []any, error) {
+	if err := checkPage(page); err != nil {
+		return "", nil, err
+	}
+	after, err := decodeCursor(page.After, keys)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append([]any(nil), args...)
+
+	where := ""
+	if after != nil {
+		params := make([]string, len(keys))
+		for i := range keys {
+			args = append(args, after[i])
+			params[i] = placeholder(len(args))
+		}
+		// (a, b) after (x, y) is a > x OR (a = x AND b > y), with < for
+		// descending keys.
+		alternatives := make([]string, len(keys))
+		for i, key := range keys {
+			var parts []string
+			for j := 0; j < i; j++ {
+				parts = append(parts, fmt.Sprintf("%s = %s", keys[j].column, params[j]))
+			}
+			op := ">"
+			if key.desc {
+				op = "<"
+			}
+			parts = append(parts, fmt.Sprintf("%s %s %s", key.column, op, params[i]))
+			alternatives[i] = "(" + strings.Join(parts, " AND ") + ")"
+		}
+		where = " WHERE " + strings.Join(alternatives, " OR ")
+	}
+
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		order[i] = key.column
+		if key.desc {
+			order[i] += " DESC"
+		}
+	}
+	args = append(args, page.Limit+1, page.Offset)
+	return fmt.Sprintf("SELECT * FROM (%s) AS page%s ORDER BY %s LIMIT %s OFFSET %s",
+		query, where, strings.Join(order, ", "), placeholder(len(args)-1), placeholder(len(args))), args, nil
+}
+
+// selectPage runs query through keysetQuery and, when asked for, counts
+// every row it matches. key returns the sort key values of a row.
+func selectPage[T any](ctx context.Context, db dbtx, query string, args []any, keys []sortKey,
+	page musiclibrary.PageRequest, placeholder func(int) string, key func(T) []any) (musiclibrary.Page[T], error) {
+	pageQuery, pageArgs, err := keysetQuery(query, args, keys, page, placeholder)
+	if err != nil {
+		return musiclibrary.Page[T]{}, err
+	}
+	var rows []T
+	if err := db.SelectContext(ctx, &rows, pageQuery, pageArgs...); err != nil {
+		return musiclibrary.Page[T]{}, err
+	}
+	result := pageOf(rows, page, key)
+	if page.WithTotal {
+		var total int
+		if err := db.GetContext(ctx, &total, fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS matches", query), args...); err != nil {
+			return musiclibrary.Page[T]{}, err
+		}
+		result.Total = &total
+	}
+	return result, nil
+}
+
+// pageOf drops the extra row fetched to look ahead and points the cursor of
+// the next page at the last row of this one.
+func pageOf[T any](rows []T, page musiclibrary.PageRequest, key func(T) []any) musiclibrary.Page[T] {
+	result := musiclibrary.Page[T]{Items: rows}
+	if len(rows) > page.Limit {
+		result.Items = rows[:page.Limit]
+		result.Next = encodeCursor(key(result.Items[page.Limit-1]))
+	}
+	if result.Items == nil {
+		result.Items = []T{}
+	}
+	return result
+}
+
+// pageSlice is keysetQuery and selectPage for rows already sorted by keys in
+// memory.
+func pageSlice[T any](rows []T, keys []sortKey, page musiclibrary.PageRequest, key func(T) []any) (musiclibrary.Page[T], error) {
+	if err := checkPage(page); err != nil {
+		return musiclibrary.Page[T]{}, err
+	}
+	after, err := decodeCursor(page.After, keys)
+	if err != nil {
+		return musiclibrary.Page[T]{}, err
+	}
+	total := len(rows)
+	if after != nil {
+		start := len(rows)
+		for i, row := range rows {
+			if compareKeys(key(row), after, keys) > 0 {
+				start = i
+				break
+			}
+		}
+		rows = rows[start:]
+	}
+	rows = rows[min(page.Offset, len(rows)):]
+	rows = rows[:min(page.Limit+1, len(rows))]
+
+	result := pageOf(rows, page, key)
+	if page.WithTotal {
+		result.Total = &total
+	}
+	return result, nil
+}
+
+// compareKeys orders two rows by their sort key values the way keys sort
+// them: negative when a comes first, positive when b does.
+func compareKeys(a, b []any, keys []sortKey) int {
+	for i, key := range keys {
+		c := compareValues(a[i], b[i])
+		if key.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareValues(a, b any) int {
+	if s, ok := a.(string); ok {
+		t, _ := b.(string)
+		return strings.Compare(s, t)
+	}
+	x, y := toFloat(a), toFloat(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v any) float64 {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// scoredKeys orders fuzzy matches best first and everything else by id.
+func scoredKeys(scored bool) []sortKey {
+	if scored {
+		return sortByScore
+	}
+	return sortById
+}
+
+// songKeys is scoredKeys for songs, which the "sort" filter can order by
+// rating instead.
+func songKeys(filters map[string]string, scored bool) []sortKey {
+	if filters["sort"] == sortRating {
+		return sortByRating
+	}
+	return scoredKeys(scored)
+}
+
+func groupKey(group musiclibrary.Group) []any {
+	if group.Score != nil {
+		return []any{*group.Score, group.Id}
+	}
+	return []any{group.Id}
+}
+
+func songKey(song musiclibrary.Song) []any {
+	if song.Rating != nil {
+		return []any{*song.Rating, song.Id}
+	}
+	if song.Score != nil {
+		return []any{*song.Score, song.Id}
+	}
+	return []any{song.Id}
+}
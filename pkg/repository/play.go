@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"fmt"
+	musiclibrary "time-tracker"
+)
+
+const (
+	PlayRecorded  = "recorded"
+	PlayDuplicate = "duplicate"
+	PlayError     = "error"
+)
+
+// errNoSuchSong is the reason given for plays of songs that do not exist.
+const errNoSuchSong = "song does not exist"
+
+// statsWhere returns the condition picking the scrobbles of query out of
+// plays p, with its arguments numbered from 1 by placeholder.
+func statsWhere(query musiclibrary.StatsQuery, placeholder func(int) string) (string, []any) {
+	where := fmt.Sprintf("p.scrobble AND p.playedat >= %s AND p.playedat < %s", placeholder(1), placeholder(2))
+	args := []any{query.From, query.To}
+	if query.UserId != 0 {
+		where += fmt.Sprintf(" AND p.userid = %s", placeholder(3))
+		args = append(args, query.UserId)
+	}
+	return where, args
+}
+
+// uniqueSongIds returns the song ids of plays, each once.
+func uniqueSongIds(plays []musiclibrary.Play) []int {
+	seen := make(map[int]bool, len(plays))
+	ids := make([]int, 0, len(plays))
+	for _, play := range plays {
+		if !seen[play.SongId] {
+			seen[play.SongId] = true
+			ids = append(ids, play.SongId)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	musiclibrary "time-tracker"
+)
+
+type PlayMemory struct {
+	store *memoryStore
+}
+
+func (r *PlayMemory) RecordPlays(ctx context.Context, plays []musiclibrary.Play) ([]musiclibrary.PlayResult, error) {
+	results := make([]musiclibrary.PlayResult, 0, len(plays))
+	err := r.store.write(ctx, func() error {
+		if len(plays) > 0 {
+			if _, ok := r.store.users[plays[0].UserId]; !ok {
+				return musiclibrary.NewForeignKeyError(foreignKeyMessage("play"))
+			}
+		}
+		for i, play := range plays {
+			result := musiclibrary.PlayResult{Index: i, SongId: play.SongId}
+			switch {
+			case !r.songExists(play.SongId):
+				result.Status, result.Reason = PlayError, errNoSuchSong
+			case r.recorded(play):
+				result.Status = PlayDuplicate
+			default:
+				r.store.lastPlayId++
+				play.Id = r.store.lastPlayId
+				r.store.plays[play.Id] = play
+				result.Status, result.PlayId, result.Scrobble = PlayRecorded, play.Id, play.Scrobble
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *PlayMemory) GetLastPlayed(ctx context.Context, songIds []int) (map[int]string, error) {
+	lastPlayed := map[int]string{}
+	err := r.store.read(ctx, func() error {
+		wanted := make(map[int]bool, len(songIds))
+		for _, id := range songIds {
+			wanted[id] = true
+		}
+		for _, play := range r.store.plays {
+			if play.Scrobble && wanted[play.SongId] && play.PlayedAt > lastPlayed[play.SongId] {
+				lastPlayed[play.SongId] = play.PlayedAt
+			}
+		}
+		return nil
+	})
+	return lastPlayed, err
+}
+
+func (r *PlayMemory) GetTopSongs(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.SongStat, error) {
+	stats := []musiclibrary.SongStat{}
+	err := r.store.read(ctx, func() error {
+		for songId, plays := range r.count(query, func(play musiclibrary.Play) int { return play.SongId }) {
+			song := r.store.songs[songId]
+			stats = append(stats, musiclibrary.SongStat{
+				SongId:    songId,
+				SongName:  song.SongName,
+				GroupId:   song.GroupId,
+				GroupName: r.store.groups[song.GroupId].GroupName,
+				Plays:     plays,
+			})
+		}
+		sort.Slice(stats, func(i, j int) bool {
+			if stats[i].Plays != stats[j].Plays {
+				return stats[i].Plays > stats[j].Plays
+			}
+			return stats[i].SongId < stats[j].SongId
+		})
+		stats = stats[:min(len(stats), query.Limit)]
+		return nil
+	})
+	return stats, err
+}
+
+func (r *PlayMemory) GetTopGroups(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.GroupStat, error) {
+	stats := []musiclibrary.GroupStat{}
+	err := r.store.read(ctx, func() error {
+		byGroup := r.count(query, func(play musiclibrary.Play) int { return r.store.songs[play.SongId].GroupId })
+		for groupId, plays := range byGroup {
+			stats = append(stats, musiclibrary.GroupStat{
+				GroupId:   groupId,
+				GroupName: r.store.groups[groupId].GroupName,
+				Plays:     plays,
+			})
+		}
+		sort.Slice(stats, func(i, j int) bool {
+			if stats[i].Plays != stats[j].Plays {
+				return stats[i].Plays > stats[j].Plays
+			}
+			return stats[i].GroupId < stats[j].GroupId
+		})
+		stats = stats[:min(len(stats), query.Limit)]
+		return nil
+	})
+	return stats, err
+}
+
+func (r *PlayMemory) GetPlaysPerDay(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.DayStat, error) {
+	stats := []musiclibrary.DayStat{}
+	err := r.store.read(ctx, func() error {
+		perDay := map[string]int{}
+		for _, play := range r.scrobbles(query) {
+			perDay[play.PlayedAt[:len("2006-01-02")]]++
+		}
+		for day, plays := range perDay {
+			stats = append(stats, musiclibrary.DayStat{Day: day, Plays: plays})
+		}
+		sort.Slice(stats, func(i, j int) bool {
+			return stats[i].Day < stats[j].Day
+		})
+		return nil
+	})
+	return stats, err
+}
+
+// scrobbles returns the plays statsWhere picks. The store must be locked.
+func (r *PlayMemory) scrobbles(query musiclibrary.StatsQuery) []musiclibrary.Play {
+	var plays []musiclibrary.Play
+	for _, play := range r.store.plays {
+		if play.Scrobble && play.PlayedAt >= query.From && play.PlayedAt < query.To &&
+			(query.UserId == 0 || play.UserId == query.UserId) {
+			plays = append(plays, play)
+		}
+	}
+	return plays
+}
+
+// count counts the scrobbles of query by the key of each. The store must be
+// locked.
+func (r *PlayMemory) count(query musiclibrary.StatsQuery, key func(play musiclibrary.Play) int) map[int]int {
+	counts := map[int]int{}
+	for _, play := range r.scrobbles(query) {
+		counts[key(play)]++
+	}
+	return counts
+}
+
+func (r *PlayMemory) songExists(id int) bool {
+	_, ok := r.store.songs[id]
+	return ok
+}
+
+// recorded tells whether the user recorded a play of the song at the same
+// time before, which the SQL backends refuse by a unique constraint.
+func (r *PlayMemory) recorded(play musiclibrary.Play) bool {
+	for _, p := range r.store.plays {
+		if p.UserId == play.UserId && p.SongId == play.SongId && p.PlayedAt == play.PlayedAt {
+			return true
+		}
+	}
+	return false
+}
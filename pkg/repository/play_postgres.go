@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type PlayPostgres struct {
+	db *sqlx.DB
+}
+
+func NewPlayPostgres(db *sqlx.DB) *PlayPostgres {
+	return &PlayPostgres{db: db}
+}
+
+func (r *PlayPostgres) RecordPlays(ctx context.Context, plays []musiclibrary.Play) ([]musiclibrary.PlayResult, error) {
+	logrus.WithField("plays", len(plays)).Debug("Recording plays")
+	var results []musiclibrary.PlayResult
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		// FOR KEY SHARE keeps the songs from being deleted until the plays
+		// are recorded.
+		var existing []int
+		query := fmt.Sprintf("SELECT id FROM %s WHERE id = ANY($1) FOR KEY SHARE", songsTable)
+		if err := tx.SelectContext(ctx, &existing, query, pq.Array(uniqueSongIds(plays))); err != nil {
+			return err
+		}
+		songs := make(map[int]bool, len(existing))
+		for _, id := range existing {
+			songs[id] = true
+		}
+
+		query = fmt.Sprintf(`
+			INSERT INTO %s (userId, songId, playedAt, duration, songDuration, client, scrobble)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (userId, songId, playedAt) DO NOTHING
+			RETURNING id`, playsTable)
+		results = make([]musiclibrary.PlayResult, 0, len(plays))
+		for i, play := range plays {
+			result := musiclibrary.PlayResult{Index: i, SongId: play.SongId}
+			if !songs[play.SongId] {
+				result.Status, result.Reason = PlayError, errNoSuchSong
+				results = append(results, result)
+				continue
+			}
+			err := tx.GetContext(ctx, &result.PlayId, query, play.UserId, play.SongId, play.PlayedAt,
+				play.Duration, play.SongDuration, play.Client, play.Scrobble)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				result.Status = PlayDuplicate
+			case err != nil:
+				return err
+			default:
+				result.Status, result.Scrobble = PlayRecorded, play.Scrobble
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to record plays")
+		return nil, dbError(err, "play")
+	}
+	return results, nil
+}
+
+func (r *PlayPostgres) GetLastPlayed(ctx context.Context, songIds []int) (map[int]string, error) {
+	var rows []struct {
+		SongId   int    `db:"songid"`
+		PlayedAt string `db:"playedat"`
+	}
+	query := fmt.Sprintf(`
+		SELECT songId AS songid, TO_CHAR(MAX(playedAt), 'YYYY-MM-DD"T"HH24:MI:SS') AS playedat
+		FROM %s
+		WHERE scrobble AND songId = ANY($1)
+		GROUP BY songId`, playsTable)
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(songIds)); err != nil {
+		logrus.WithError(err).Error("Failed to fetch last played times")
+		return nil, dbError(err, "play")
+	}
+	lastPlayed := make(map[int]string, len(rows))
+	for _, row := range rows {
+		lastPlayed[row.SongId] = row.PlayedAt
+	}
+	return lastPlayed, nil
+}
+
+func (r *PlayPostgres) GetTopSongs(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.SongStat, error) {
+	stats := []musiclibrary.SongStat{}
+	where, args := statsWhere(query, pgPlaceholder)
+	sqlQuery := fmt.Sprintf(`
+		SELECT s.id AS songid, s.songName AS songname, g.id AS groupid, g.groupName AS groupname, COUNT(*) AS plays
+		FROM %s p
+		JOIN %s s ON s.id = p.songId
+		JOIN %s g ON g.id = s.groupId
+		WHERE %s
+		GROUP BY s.id, g.id
+		ORDER BY plays DESC, s.id
+		LIMIT %d`, playsTable, songsTable, groupsTable, where, query.Limit)
+	if err := r.db.SelectContext(ctx, &stats, sqlQuery, args...); err != nil {
+		logrus.WithError(err).Error("Failed to fetch top songs")
+		return nil, dbError(err, "play")
+	}
+	return stats, nil
+}
+
+func (r *PlayPostgres) GetTopGroups(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.GroupStat, error) {
+	stats := []musiclibrary.GroupStat{}
+	where, args := statsWhere(query, pgPlaceholder)
+	sqlQuery := fmt.Sprintf(`
+		SELECT g.id AS groupid, g.groupName AS groupname, COUNT(*) AS plays
+		FROM %s p
+		JOIN %s s ON s.id = p.songId
+		JOIN %s g ON g.id = s.groupId
+		WHERE %s
+		GROUP BY g.id
+		ORDER BY plays DESC, g.id
+		LIMIT %d`, playsTable, songsTable, groupsTable, where, query.Limit)
+	if err := r.db.SelectContext(ctx, &stats, sqlQuery, args...); err != nil {
+		logrus.WithError(err).Error("Failed to fetch top groups")
+		return nil, dbError(err, "play")
+	}
+	return stats, nil
+}
+
+func (r *PlayPostgres) GetPlaysPerDay(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.DayStat, error) {
+	stats := []musiclibrary.DayStat{}
+	where, args := statsWhere(query, pgPlaceholder)
+	sqlQuery := fmt.Sprintf(`
+		SELECT TO_CHAR(p.playedAt, 'YYYY-MM-DD') AS day, COUNT(*) AS plays
+		FROM %s p
+		WHERE %s
+		GROUP BY day
+		ORDER BY day`, playsTable, where)
+	if err := r.db.SelectContext(ctx, &stats, sqlQuery, args...); err != nil {
+		logrus.WithError(err).Error("Failed to fetch plays per day")
+		return nil, dbError(err, "play")
+	}
+	return stats, nil
+}
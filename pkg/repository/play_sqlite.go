@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type PlaySQLite struct {
+	db *sqlx.DB
+}
+
+func NewPlaySQLite(db *sqlx.DB) *PlaySQLite {
+	return &PlaySQLite{db: db}
+}
+
+func (r *PlaySQLite) RecordPlays(ctx context.Context, plays []musiclibrary.Play) ([]musiclibrary.PlayResult, error) {
+	logrus.WithField("plays", len(plays)).Debug("Recording plays")
+	var results []musiclibrary.PlayResult
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		// Transactions take the write lock up front, see NewSQLiteDB, so the
+		// songs stay until the plays are recorded.
+		ids, err := json.Marshal(uniqueSongIds(plays))
+		if err != nil {
+			return err
+		}
+		var existing []int
+		query := fmt.Sprintf("SELECT id FROM %s WHERE id IN (SELECT value FROM json_each(?))", songsTable)
+		if err := tx.SelectContext(ctx, &existing, query, string(ids)); err != nil {
+			return err
+		}
+		songs := make(map[int]bool, len(existing))
+		for _, id := range existing {
+			songs[id] = true
+		}
+
+		query = fmt.Sprintf(`
+			INSERT INTO %s (userid, songid, playedat, duration, songduration, client, scrobble)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (userid, songid, playedat) DO NOTHING
+			RETURNING id`, playsTable)
+		results = make([]musiclibrary.PlayResult, 0, len(plays))
+		for i, play := range plays {
+			result := musiclibrary.PlayResult{Index: i, SongId: play.SongId}
+			if !songs[play.SongId] {
+				result.Status, result.Reason = PlayError, errNoSuchSong
+				results = append(results, result)
+				continue
+			}
+			err := tx.GetContext(ctx, &result.PlayId, query, play.UserId, play.SongId, play.PlayedAt,
+				play.Duration, play.SongDuration, play.Client, play.Scrobble)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				result.Status = PlayDuplicate
+			case err != nil:
+				return err
+			default:
+				result.Status, result.Scrobble = PlayRecorded, play.Scrobble
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to record plays")
+		return nil, dbError(err, "play")
+	}
+	return results, nil
+}
+
+func (r *PlaySQLite) GetLastPlayed(ctx context.Context, songIds []int) (map[int]string, error) {
+	ids, err := json.Marshal(songIds)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		SongId   int    `db:"songid"`
+		PlayedAt string `db:"playedat"`
+	}
+	query := fmt.Sprintf(`
+		SELECT songid, MAX(playedat) AS playedat
+		FROM %s
+		WHERE scrobble AND songid IN (SELECT value FROM json_each(?))
+		GROUP BY songid`, playsTable)
+	if err := r.db.SelectContext(ctx, &rows, query, string(ids)); err != nil {
+		logrus.WithError(err).Error("Failed to fetch last played times")
+		return nil, dbError(err, "play")
+	}
+	lastPlayed := make(map[int]string, len(rows))
+	for _, row := range rows {
+		lastPlayed[row.SongId] = row.PlayedAt
+	}
+	return lastPlayed, nil
+}
+
+func (r *PlaySQLite) GetTopSongs(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.SongStat, error) {
+	stats := []musiclibrary.SongStat{}
+	where, args := statsWhere(query, sqlitePlaceholder)
+	sqlQuery := fmt.Sprintf(`
+		SELECT s.id AS songid, s.songname, g.id AS groupid, g.groupname, COUNT(*) AS plays
+		FROM %s p
+		JOIN %s s ON s.id = p.songid
+		JOIN %s g ON g.id = s.groupid
+		WHERE %s
+		GROUP BY s.id, g.id
+		ORDER BY plays DESC, s.id
+		LIMIT %d`, playsTable, songsTable, groupsTable, where, query.Limit)
+	if err := r.db.SelectContext(ctx, &stats, sqlQuery, args...); err != nil {
+		logrus.WithError(err).Error("Failed to fetch top songs")
+		return nil, dbError(err, "play")
+	}
+	return stats, nil
+}
+
+func (r *PlaySQLite) GetTopGroups(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.GroupStat, error) {
+	stats := []musiclibrary.GroupStat{}
+	where, args := statsWhere(query, sqlitePlaceholder)
+	sqlQuery := fmt.Sprintf(`
+		SELECT g.id AS groupid, g.groupname, COUNT(*) AS plays
+		FROM %s p
+		JOIN %s s ON s.id = p.songid
+		JOIN %s g ON g.id = s.groupid
+		WHERE %s
+		GROUP BY g.id
+		ORDER BY plays DESC, g.id
+		LIMIT %d`, playsTable, songsTable, groupsTable, where, query.Limit)
+	if err := r.db.SelectContext(ctx, &stats, sqlQuery, args...); err != nil {
+		logrus.WithError(err).Error("Failed to fetch top groups")
+		return nil, dbError(err, "play")
+	}
+	return stats, nil
+}
+
+func (r *PlaySQLite) GetPlaysPerDay(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.DayStat, error) {
+	stats := []musiclibrary.DayStat{}
+	where, args := statsWhere(query, sqlitePlaceholder)
+	sqlQuery := fmt.Sprintf(`
+		SELECT substr(p.playedat, 1, 10) AS day, COUNT(*) AS plays
+		FROM %s p
+		WHERE %s
+		GROUP BY day
+		ORDER BY day`, playsTable, where)
+	if err := r.db.SelectContext(ctx, &stats, sqlQuery, args...); err != nil {
+		logrus.WithError(err).Error("Failed to fetch plays per day")
+		return nil, dbError(err, "play")
+	}
+	return stats, nil
+}
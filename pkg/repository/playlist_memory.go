@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	musiclibrary "time-tracker"
+)
+
+type memoryPlaylistItem struct {
+	id, songId int
+}
+
+type PlaylistMemory struct {
+	store *memoryStore
+}
+
+func (r *PlaylistMemory) CreatePlaylist(ctx context.Context, playlist musiclibrary.Playlist) (int, error) {
+	var id int
+	err := r.store.write(ctx, func() error {
+		if _, ok := r.store.users[playlist.UserId]; !ok {
+			return musiclibrary.NewForeignKeyError(foreignKeyMessage("playlist"))
+		}
+		r.store.lastPlaylistId++
+		id = r.store.lastPlaylistId
+		playlist.Id, playlist.CreatedAt = id, memoryNow()
+		r.store.playlists[id] = playlist
+		return nil
+	})
+	return id, err
+}
+
+func (r *PlaylistMemory) GetPlaylists(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Playlist], error) {
+	var result musiclibrary.Page[musiclibrary.Playlist]
+	err := r.store.read(ctx, func() error {
+		var playlists []musiclibrary.Playlist
+		for _, playlist := range r.store.playlists {
+			if playlist.UserId == userId {
+				playlists = append(playlists, playlist)
+			}
+		}
+		sort.Slice(playlists, func(i, j int) bool {
+			return playlists[i].Id < playlists[j].Id
+		})
+		var err error
+		result, err = pageSlice(playlists, sortById, page, func(p musiclibrary.Playlist) []any {
+			return []any{p.Id}
+		})
+		return err
+	})
+	return result, err
+}
+
+func (r *PlaylistMemory) GetPlaylistById(ctx context.Context, id int) (musiclibrary.Playlist, error) {
+	var playlist musiclibrary.Playlist
+	err := r.store.read(ctx, func() error {
+		var ok bool
+		if playlist, ok = r.store.playlists[id]; !ok {
+			return notFound("playlist")
+		}
+		return nil
+	})
+	return playlist, err
+}
+
+func (r *PlaylistMemory) UpdatePlaylist(ctx context.Context, id int, input musiclibrary.UpdatePlaylistInput) error {
+	return r.store.write(ctx, func() error {
+		if input.Name == nil && input.Description == nil {
+			return nil
+		}
+		playlist, ok := r.store.playlists[id]
+		if !ok {
+			return notFound("playlist")
+		}
+		if input.Name != nil {
+			playlist.Name = *input.Name
+		}
+		if input.Description != nil {
+			playlist.Description = *input.Description
+		}
+		r.store.playlists[id] = playlist
+		return nil
+	})
+}
+
+func (r *PlaylistMemory) DeletePlaylist(ctx context.Context, id int) error {
+	return r.store.write(ctx, func() error {
+		if _, ok := r.store.playlists[id]; !ok {
+			return notFound("playlist")
+		}
+		delete(r.store.playlists, id)
+		delete(r.store.playlistItems, id)
+		return nil
+	})
+}
+
+func (r *PlaylistMemory) GetPlaylistItems(ctx context.Context, playlistId int) ([]musiclibrary.PlaylistItem, error) {
+	items := []musiclibrary.PlaylistItem{}
+	err := r.store.read(ctx, func() error {
+		for i, item := range r.store.playlistItems[playlistId] {
+			items = append(items, musiclibrary.PlaylistItem{
+				Id:       item.id,
+				Position: i + 1,
+				SongId:   item.songId,
+				SongName: r.store.songs[item.songId].SongName,
+			})
+		}
+		return nil
+	})
+	return items, err
+}
+
+func (r *PlaylistMemory) InsertPlaylistItem(ctx context.Context, playlistId, songId, position int) (int, error) {
+	var id int
+	err := r.changeItems(ctx, playlistId, func(ids []int) ([]int, error) {
+		if _, ok := r.store.songs[songId]; !ok {
+			return nil, musiclibrary.NewForeignKeyError(foreignKeyMessage("playlist item"))
+		}
+		order, err := insertItem(ids, r.store.lastPlaylistItemId+1, position)
+		if err != nil {
+			return nil, err
+		}
+		r.store.lastPlaylistItemId++
+		id = r.store.lastPlaylistItemId
+		r.store.playlistItems[playlistId] = append(r.store.playlistItems[playlistId], memoryPlaylistItem{id: id, songId: songId})
+		return order, nil
+	})
+	return id, err
+}
+
+func (r *PlaylistMemory) RemovePlaylistItem(ctx context.Context, playlistId, itemId int) error {
+	return r.changeItems(ctx, playlistId, func(ids []int) ([]int, error) {
+		order := withoutItem(ids, itemId)
+		if len(order) == len(ids) {
+			return nil, notFound("playlist item")
+		}
+		return order, nil
+	})
+}
+
+func (r *PlaylistMemory) ReorderPlaylist(ctx context.Context, playlistId int, itemIds []int) error {
+	return r.changeItems(ctx, playlistId, func(ids []int) ([]int, error) {
+		return itemIds, reorderItems(ids, itemIds)
+	})
+}
+
+// changeItems hands the ids of the items of a playlist in order to fn and
+// keeps the items fn returns in that order.
+func (r *PlaylistMemory) changeItems(ctx context.Context, playlistId int, fn func(ids []int) ([]int, error)) error {
+	return r.store.write(ctx, func() error {
+		if _, ok := r.store.playlists[playlistId]; !ok {
+			return notFound("playlist")
+		}
+		ids := make([]int, 0, len(r.store.playlistItems[playlistId]))
+		for _, item := range r.store.playlistItems[playlistId] {
+			ids = append(ids, item.id)
+		}
+		order, err := fn(ids)
+		if err != nil {
+			return err
+		}
+		songs := make(map[int]int, len(r.store.playlistItems[playlistId]))
+		for _, item := range r.store.playlistItems[playlistId] {
+			songs[item.id] = item.songId
+		}
+		items := make([]memoryPlaylistItem, 0, len(order))
+		for _, id := range order {
+			items = append(items, memoryPlaylistItem{id: id, songId: songs[id]})
+		}
+		r.store.playlistItems[playlistId] = items
+		return nil
+	})
+}
+
+func (r *PlaylistMemory) GetPlaylistTracks(ctx context.Context, playlistId int) ([]musiclibrary.PlaylistTrack, error) {
+	tracks := []musiclibrary.PlaylistTrack{}
+	err := r.store.read(ctx, func() error {
+		for _, item := range r.store.playlistItems[playlistId] {
+			tracks = append(tracks, r.track(item.songId))
+		}
+		return nil
+	})
+	return tracks, err
+}
+
+func (r *PlaylistMemory) GetTrackCatalog(ctx context.Context) ([]musiclibrary.PlaylistTrack, error) {
+	tracks := []musiclibrary.PlaylistTrack{}
+	err := r.store.read(ctx, func() error {
+		for id := range r.store.songs {
+			tracks = append(tracks, r.track(id))
+		}
+		sort.Slice(tracks, func(i, j int) bool {
+			return tracks[i].SongId < tracks[j].SongId
+		})
+		return nil
+	})
+	return tracks, err
+}
+
+func (r *PlaylistMemory) ImportPlaylist(ctx context.Context, playlist musiclibrary.Playlist, songIds []int) (int, error) {
+	var id int
+	err := r.store.write(ctx, func() error {
+		if _, ok := r.store.users[playlist.UserId]; !ok {
+			return musiclibrary.NewForeignKeyError(foreignKeyMessage("playlist"))
+		}
+		items := make([]memoryPlaylistItem, 0, len(songIds))
+		for i, songId := range songIds {
+			if _, ok := r.store.songs[songId]; !ok {
+				return musiclibrary.NewForeignKeyError(foreignKeyMessage("playlist item"))
+			}
+			items = append(items, memoryPlaylistItem{id: r.store.lastPlaylistItemId + i + 1, songId: songId})
+		}
+		r.store.lastPlaylistItemId += len(items)
+		r.store.lastPlaylistId++
+		id = r.store.lastPlaylistId
+		playlist.Id, playlist.CreatedAt = id, memoryNow()
+		r.store.playlists[id] = playlist
+		r.store.playlistItems[id] = items
+		return nil
+	})
+	return id, err
+}
+
+// track describes a song the way the SQL backends do, without the N/A of
+// songs without a link. The store must be locked.
+func (r *PlaylistMemory) track(songId int) musiclibrary.PlaylistTrack {
+	song := r.store.songs[songId]
+	location := r.store.details[songId].Link
+	if location == "N/A" {
+		location = ""
+	}
+	return musiclibrary.PlaylistTrack{
+		SongId:   songId,
+		Artist:   r.store.groups[song.GroupId].GroupName,
+		Title:    song.SongName,
+		Location: location,
+	}
+}
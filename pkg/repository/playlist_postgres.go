@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const playlistColumns = `id, userId AS userid, name, description,
+	TO_CHAR(createdAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS createdat`
+
+type PlaylistPostgres struct {
+	db *sqlx.DB
+}
+
+func NewPlaylistPostgres(db *sqlx.DB) *PlaylistPostgres {
+	return &PlaylistPostgres{db: db}
+}
+
+func (r *PlaylistPostgres) CreatePlaylist(ctx context.Context, playlist musiclibrary.Playlist) (int, error) {
+	logrus.WithField("userId", playlist.UserId).Debug("Creating playlist")
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (userId, name, description) VALUES ($1, $2, $3) RETURNING id", playlistsTable)
+	row := r.db.QueryRowContext(ctx, query, playlist.UserId, playlist.Name, playlist.Description)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to create playlist")
+		return 0, dbError(err, "playlist")
+	}
+	logrus.WithField("id", id).Info("Playlist created successfully")
+	return id, nil
+}
+
+func (r *PlaylistPostgres) GetPlaylists(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Playlist], error) {
+	logrus.WithField("userId", userId).Debug("Fetching playlists")
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE userId = $1", playlistColumns, playlistsTable)
+	playlists, err := selectPage(ctx, r.db, query, []any{userId}, sortById, page, pgPlaceholder, func(p musiclibrary.Playlist) []any {
+		return []any{p.Id}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch playlists")
+		return musiclibrary.Page[musiclibrary.Playlist]{}, dbError(err, "playlist")
+	}
+	return playlists, nil
+}
+
+func (r *PlaylistPostgres) GetPlaylistById(ctx context.Context, id int) (musiclibrary.Playlist, error) {
+	var playlist musiclibrary.Playlist
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", playlistColumns, playlistsTable)
+	err := r.db.GetContext(ctx, &playlist, query, id)
+	return playlist, dbError(err, "playlist")
+}
+
+func (r *PlaylistPostgres) UpdatePlaylist(ctx context.Context, id int, input musiclibrary.UpdatePlaylistInput) error {
+	logrus.WithField("id", id).Debug("Updating playlist")
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Name != nil {
+		setValues = append(setValues, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *input.Name)
+		argId++
+	}
+	if input.Description != nil {
+		setValues = append(setValues, fmt.Sprintf("description=$%d", argId))
+		args = append(args, *input.Description)
+		argId++
+	}
+
+	if argId > 1 {
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", playlistsTable, strings.Join(setValues, ", "), argId)
+		args = append(args, id)
+		res, err := r.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to update playlist")
+			return dbError(err, "playlist")
+		}
+		if err := affected(res, "playlist"); err != nil {
+			return err
+		}
+		logrus.WithField("id", id).Info("Playlist updated successfully")
+	}
+	return nil
+}
+
+func (r *PlaylistPostgres) DeletePlaylist(ctx context.Context, id int) error {
+	logrus.WithField("id", id).Debug("Deleting playlist")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", playlistsTable)
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete playlist")
+		return dbError(err, "playlist")
+	}
+	if err := affected(res, "playlist"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Playlist deleted successfully")
+	return nil
+}
+
+func (r *PlaylistPostgres) GetPlaylistItems(ctx context.Context, playlistId int) ([]musiclibrary.PlaylistItem, error) {
+	items := []musiclibrary.PlaylistItem{}
+	query := fmt.Sprintf(`
+		SELECT i.id, ROW_NUMBER() OVER (ORDER BY i.position, i.id) AS position,
+			i.songId AS songid, s.songName AS songname
+		FROM %s i
+		JOIN %s s ON s.id = i.songId
+		WHERE i.playlistId = $1
+		ORDER BY position`, playlistItemsTable, songsTable)
+	if err := r.db.SelectContext(ctx, &items, query, playlistId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch playlist items")
+		return nil, dbError(err, "playlist item")
+	}
+	return items, nil
+}
+
+func (r *PlaylistPostgres) InsertPlaylistItem(ctx context.Context, playlistId, songId, position int) (int, error) {
+	logrus.WithFields(logrus.Fields{"playlistId": playlistId, "songId": songId}).Debug("Adding playlist item")
+	var id int
+	err := r.changeItems(ctx, playlistId, func(tx *sqlx.Tx, ids []int) ([]int, error) {
+		query := fmt.Sprintf("INSERT INTO %s (playlistId, songId, position) VALUES ($1, $2, 0) RETURNING id", playlistItemsTable)
+		if err := tx.GetContext(ctx, &id, query, playlistId, songId); err != nil {
+			return nil, err
+		}
+		return insertItem(ids, id, position)
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add playlist item")
+		return 0, dbError(err, "playlist item")
+	}
+	logrus.WithField("id", id).Info("Playlist item added successfully")
+	return id, nil
+}
+
+func (r *PlaylistPostgres) RemovePlaylistItem(ctx context.Context, playlistId, itemId int) error {
+	logrus.WithFields(logrus.Fields{"playlistId": playlistId, "itemId": itemId}).Debug("Removing playlist item")
+	err := r.changeItems(ctx, playlistId, func(tx *sqlx.Tx, ids []int) ([]int, error) {
+		query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND playlistId = $2", playlistItemsTable)
+		res, err := tx.ExecContext(ctx, query, itemId, playlistId)
+		if err != nil {
+			return nil, err
+		}
+		if err := affected(res, "playlist item"); err != nil {
+			return nil, err
+		}
+		return withoutItem(ids, itemId), nil
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove playlist item")
+		return dbError(err, "playlist item")
+	}
+	return nil
+}
+
+func (r *PlaylistPostgres) ReorderPlaylist(ctx context.Context, playlistId int, itemIds []int) error {
+	logrus.WithField("playlistId", playlistId).Debug("Reordering playlist")
+	err := r.changeItems(ctx, playlistId, func(tx *sqlx.Tx, ids []int) ([]int, error) {
+		return itemIds, reorderItems(ids, itemIds)
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reorder playlist")
+		return dbError(err, "playlist item")
+	}
+	logrus.WithField("playlistId", playlistId).Info("Playlist reordered successfully")
+	return nil
+}
+
+// changeItems locks a playlist, hands the ids of its items in order to fn
+// and numbers the items in the order fn returns.
+func (r *PlaylistPostgres) changeItems(ctx context.Context, playlistId int, fn func(tx *sqlx.Tx, ids []int) ([]int, error)) error {
+	return inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		var locked int
+		query := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 FOR UPDATE", playlistsTable)
+		if err := tx.GetContext(ctx, &locked, query, playlistId); err != nil {
+			return dbError(err, "playlist")
+		}
+		var ids []int
+		query = fmt.Sprintf("SELECT id FROM %s WHERE playlistId = $1 ORDER BY position, id", playlistItemsTable)
+		if err := tx.SelectContext(ctx, &ids, query, playlistId); err != nil {
+			return err
+		}
+		order, err := fn(tx, ids)
+		if err != nil {
+			return err
+		}
+		query = fmt.Sprintf(`
+			UPDATE %s AS i SET position = o.position
+			FROM unnest($1::int[]) WITH ORDINALITY AS o(id, position)
+			WHERE i.id = o.id AND i.position <> o.position`, playlistItemsTable)
+		_, err = tx.ExecContext(ctx, query, pq.Array(order))
+		return err
+	})
+}
+
+// trackColumns picks the columns of a PlaylistTrack from songs s, groups g
+// and song details d. The N/A of songs without a link is left out.
+const trackColumns = `s.id AS songid, g.groupName AS artist, s.songName AS title,
+	COALESCE(NULLIF(d.link, 'N/A'), '') AS location`
+
+func (r *PlaylistPostgres) GetPlaylistTracks(ctx context.Context, playlistId int) ([]musiclibrary.PlaylistTrack, error) {
+	tracks := []musiclibrary.PlaylistTrack{}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s s ON s.id = i.songId
+		JOIN %s g ON g.id = s.groupId
+		LEFT JOIN %s d ON d.songId = s.id
+		WHERE i.playlistId = $1
+		ORDER BY i.position, i.id`, trackColumns, playlistItemsTable, songsTable, groupsTable, songDetailsTable)
+	if err := r.db.SelectContext(ctx, &tracks, query, playlistId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch playlist tracks")
+		return nil, dbError(err, "playlist item")
+	}
+	return tracks, nil
+}
+
+func (r *PlaylistPostgres) GetTrackCatalog(ctx context.Context) ([]musiclibrary.PlaylistTrack, error) {
+	tracks := []musiclibrary.PlaylistTrack{}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s s
+		JOIN %s g ON g.id = s.groupId
+		LEFT JOIN %s d ON d.songId = s.id
+		ORDER BY s.id`, trackColumns, songsTable, groupsTable, songDetailsTable)
+	if err := r.db.SelectContext(ctx, &tracks, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch track catalog")
+		return nil, dbError(err, "song")
+	}
+	return tracks, nil
+}
+
+func (r *PlaylistPostgres) ImportPlaylist(ctx context.Context, playlist musiclibrary.Playlist, songIds []int) (int, error) {
+	logrus.WithFields(logrus.Fields{"userId": playlist.UserId, "songs": len(songIds)}).Debug("Importing playlist")
+	var id int
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		query := fmt.Sprintf("INSERT INTO %s (userId, name, description) VALUES ($1, $2, $3) RETURNING id", playlistsTable)
+		if err := tx.GetContext(ctx, &id, query, playlist.UserId, playlist.Name, playlist.Description); err != nil {
+			return err
+		}
+		query = fmt.Sprintf(`
+			INSERT INTO %s (playlistId, songId, position)
+			SELECT $1, o.songId, o.position
+			FROM unnest($2::int[]) WITH ORDINALITY AS o(songId, position)`, playlistItemsTable)
+		_, err := tx.ExecContext(ctx, query, id, pq.Array(songIds))
+		return err
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to import playlist")
+		return 0, dbError(err, "playlist")
+	}
+	logrus.WithField("id", id).Info("Playlist imported successfully")
+	return id, nil
+}
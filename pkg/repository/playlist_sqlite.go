@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const playlistColumnsSQLite = `id, userid, name, description, createdat`
+
+type PlaylistSQLite struct {
+	db *sqlx.DB
+}
+
+func NewPlaylistSQLite(db *sqlx.DB) *PlaylistSQLite {
+	return &PlaylistSQLite{db: db}
+}
+
+func (r *PlaylistSQLite) CreatePlaylist(ctx context.Context, playlist musiclibrary.Playlist) (int, error) {
+	logrus.WithField("userId", playlist.UserId).Debug("Creating playlist")
+	query := fmt.Sprintf("INSERT INTO %s (userid, name, description) VALUES (?, ?, ?)", playlistsTable)
+	res, err := r.db.ExecContext(ctx, query, playlist.UserId, playlist.Name, playlist.Description)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create playlist")
+		return 0, dbError(err, "playlist")
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	logrus.WithField("id", id).Info("Playlist created successfully")
+	return int(id), nil
+}
+
+func (r *PlaylistSQLite) GetPlaylists(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Playlist], error) {
+	logrus.WithField("userId", userId).Debug("Fetching playlists")
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE userid = ?1", playlistColumnsSQLite, playlistsTable)
+	playlists, err := selectPage(ctx, r.db, query, []any{userId}, sortById, page, sqlitePlaceholder, func(p musiclibrary.Playlist) []any {
+		return []any{p.Id}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch playlists")
+		return musiclibrary.Page[musiclibrary.Playlist]{}, dbError(err, "playlist")
+	}
+	return playlists, nil
+}
+
+func (r *PlaylistSQLite) GetPlaylistById(ctx context.Context, id int) (musiclibrary.Playlist, error) {
+	var playlist musiclibrary.Playlist
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", playlistColumnsSQLite, playlistsTable)
+	err := r.db.GetContext(ctx, &playlist, query, id)
+	return playlist, dbError(err, "playlist")
+}
+
+func (r *PlaylistSQLite) UpdatePlaylist(ctx context.Context, id int, input musiclibrary.UpdatePlaylistInput) error {
+	logrus.WithField("id", id).Debug("Updating playlist")
+	var setValues []string
+	var args []interface{}
+
+	if input.Name != nil {
+		setValues = append(setValues, "name = ?")
+		args = append(args, *input.Name)
+	}
+	if input.Description != nil {
+		setValues = append(setValues, "description = ?")
+		args = append(args, *input.Description)
+	}
+	if len(setValues) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = ?", playlistsTable, strings.Join(setValues, ", "))
+	res, err := r.db.ExecContext(ctx, query, append(args, id)...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update playlist")
+		return dbError(err, "playlist")
+	}
+	if err := affected(res, "playlist"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Playlist updated successfully")
+	return nil
+}
+
+func (r *PlaylistSQLite) DeletePlaylist(ctx context.Context, id int) error {
+	logrus.WithField("id", id).Debug("Deleting playlist")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", playlistsTable)
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete playlist")
+		return dbError(err, "playlist")
+	}
+	if err := affected(res, "playlist"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Playlist deleted successfully")
+	return nil
+}
+
+func (r *PlaylistSQLite) GetPlaylistItems(ctx context.Context, playlistId int) ([]musiclibrary.PlaylistItem, error) {
+	items := []musiclibrary.PlaylistItem{}
+	query := fmt.Sprintf(`
+		SELECT i.id, ROW_NUMBER() OVER (ORDER BY i.position, i.id) AS position, i.songid, s.songname
+		FROM %s i
+		JOIN %s s ON s.id = i.songid
+		WHERE i.playlistid = ?
+		ORDER BY position`, playlistItemsTable, songsTable)
+	if err := r.db.SelectContext(ctx, &items, query, playlistId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch playlist items")
+		return nil, dbError(err, "playlist item")
+	}
+	return items, nil
+}
+
+func (r *PlaylistSQLite) InsertPlaylistItem(ctx context.Context, playlistId, songId, position int) (int, error) {
+	logrus.WithFields(logrus.Fields{"playlistId": playlistId, "songId": songId}).Debug("Adding playlist item")
+	var id int
+	err := r.changeItems(ctx, playlistId, func(tx *sqlx.Tx, ids []int) ([]int, error) {
+		query := fmt.Sprintf("INSERT INTO %s (playlistid, songid, position) VALUES (?, ?, 0) RETURNING id", playlistItemsTable)
+		if err := tx.GetContext(ctx, &id, query, playlistId, songId); err != nil {
+			return nil, err
+		}
+		return insertItem(ids, id, position)
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add playlist item")
+		return 0, dbError(err, "playlist item")
+	}
+	logrus.WithField("id", id).Info("Playlist item added successfully")
+	return id, nil
+}
+
+func (r *PlaylistSQLite) RemovePlaylistItem(ctx context.Context, playlistId, itemId int) error {
+	logrus.WithFields(logrus.Fields{"playlistId": playlistId, "itemId": itemId}).Debug("Removing playlist item")
+	err := r.changeItems(ctx, playlistId, func(tx *sqlx.Tx, ids []int) ([]int, error) {
+		query := fmt.Sprintf("DELETE FROM %s WHERE id = ? AND playlistid = ?", playlistItemsTable)
+		res, err := tx.ExecContext(ctx, query, itemId, playlistId)
+		if err != nil {
+			return nil, err
+		}
+		if err := affected(res, "playlist item"); err != nil {
+			return nil, err
+		}
+		return withoutItem(ids, itemId), nil
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove playlist item")
+		return dbError(err, "playlist item")
+	}
+	return nil
+}
+
+func (r *PlaylistSQLite) ReorderPlaylist(ctx context.Context, playlistId int, itemIds []int) error {
+	logrus.WithField("playlistId", playlistId).Debug("Reordering playlist")
+	err := r.changeItems(ctx, playlistId, func(tx *sqlx.Tx, ids []int) ([]int, error) {
+		return itemIds, reorderItems(ids, itemIds)
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reorder playlist")
+		return dbError(err, "playlist item")
+	}
+	logrus.WithField("playlistId", playlistId).Info("Playlist reordered successfully")
+	return nil
+}
+
+// changeItems hands the ids of the items of a playlist in order to fn and
+// numbers the items in the order fn returns. Transactions take the write
+// lock up front, see NewSQLiteDB, so nothing changes the items in between.
+func (r *PlaylistSQLite) changeItems(ctx context.Context, playlistId int, fn func(tx *sqlx.Tx, ids []int) ([]int, error)) error {
+	return inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		var exists int
+		query := fmt.Sprintf("SELECT id FROM %s WHERE id = ?", playlistsTable)
+		if err := tx.GetContext(ctx, &exists, query, playlistId); err != nil {
+			return dbError(err, "playlist")
+		}
+		var ids []int
+		query = fmt.Sprintf("SELECT id FROM %s WHERE playlistid = ? ORDER BY position, id", playlistItemsTable)
+		if err := tx.SelectContext(ctx, &ids, query, playlistId); err != nil {
+			return err
+		}
+		order, err := fn(tx, ids)
+		if err != nil {
+			return err
+		}
+		positions, err := json.Marshal(order)
+		if err != nil {
+			return err
+		}
+		query = fmt.Sprintf(`
+			UPDATE %[1]s SET position = (SELECT o.key + 1 FROM json_each(?1) o WHERE o.value = %[1]s.id)
+			WHERE playlistid = ?2`, playlistItemsTable)
+		_, err = tx.ExecContext(ctx, query, string(positions), playlistId)
+		return err
+	})
+}
+
+// trackColumnsSQLite picks the columns of a PlaylistTrack from songs s,
+// groups g and song details d. The N/A of songs without a link is left out.
+const trackColumnsSQLite = `s.id AS songid, g.groupname AS artist, s.songname AS title,
+	COALESCE(NULLIF(d.link, 'N/A'), '') AS location`
+
+func (r *PlaylistSQLite) GetPlaylistTracks(ctx context.Context, playlistId int) ([]musiclibrary.PlaylistTrack, error) {
+	tracks := []musiclibrary.PlaylistTrack{}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s s ON s.id = i.songid
+		JOIN %s g ON g.id = s.groupid
+		LEFT JOIN %s d ON d.songid = s.id
+		WHERE i.playlistid = ?
+		ORDER BY i.position, i.id`, trackColumnsSQLite, playlistItemsTable, songsTable, groupsTable, songDetailsTable)
+	if err := r.db.SelectContext(ctx, &tracks, query, playlistId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch playlist tracks")
+		return nil, dbError(err, "playlist item")
+	}
+	return tracks, nil
+}
+
+func (r *PlaylistSQLite) GetTrackCatalog(ctx context.Context) ([]musiclibrary.PlaylistTrack, error) {
+	tracks := []musiclibrary.PlaylistTrack{}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s s
+		JOIN %s g ON g.id = s.groupid
+		LEFT JOIN %s d ON d.songid = s.id
+		ORDER BY s.id`, trackColumnsSQLite, songsTable, groupsTable, songDetailsTable)
+	if err := r.db.SelectContext(ctx, &tracks, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch track catalog")
+		return nil, dbError(err, "song")
+	}
+	return tracks, nil
+}
+
+func (r *PlaylistSQLite) ImportPlaylist(ctx context.Context, playlist musiclibrary.Playlist, songIds []int) (int, error) {
+	logrus.WithFields(logrus.Fields{"userId": playlist.UserId, "songs": len(songIds)}).Debug("Importing playlist")
+	if songIds == nil {
+		// json_each would read null as one item.
+		songIds = []int{}
+	}
+	songs, err := json.Marshal(songIds)
+	if err != nil {
+		return 0, err
+	}
+	var id int
+	err = inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		query := fmt.Sprintf("INSERT INTO %s (userid, name, description) VALUES (?, ?, ?) RETURNING id", playlistsTable)
+		if err := tx.GetContext(ctx, &id, query, playlist.UserId, playlist.Name, playlist.Description); err != nil {
+			return err
+		}
+		query = fmt.Sprintf(`
+			INSERT INTO %s (playlistid, songid, position)
+			SELECT ?1, o.value, o.key + 1 FROM json_each(?2) o`, playlistItemsTable)
+		_, err := tx.ExecContext(ctx, query, id, string(songs))
+		return err
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to import playlist")
+		return 0, dbError(err, "playlist")
+	}
+	logrus.WithField("id", id).Info("Playlist imported successfully")
+	return id, nil
+}
@@ -8,9 +8,27 @@ import (
 )
 
 const (
-	groupsTable      = "groupss"
-	songsTable       = "songs"
-	songDetailsTable = "songdetails"
+	groupsTable        = "groupss"
+	songsTable         = "songs"
+	songDetailsTable   = "songdetails"
+	albumsTable        = "albums"
+	albumTracksTable   = "albumtracks"
+	artistsTable       = "artists"
+	groupMembersTable  = "groupmembers"
+	genresTable        = "genres"
+	tagsTable          = "tags"
+	songLrcTable       = "songlrc"
+	translationsTable  = "songtranslations"
+	revisionsTable     = "songdetailsrevisions"
+	jobsTable          = "jobs"
+	usersTable         = "users"
+	apiKeysTable       = "apikeys"
+	favoritesTable     = "favorites"
+	ratingsTable       = "songratings"
+	playlistsTable     = "playlists"
+	playlistItemsTable = "playlistitems"
+	playsTable         = "plays"
+	songVectorsTable   = "songvectors"
 )
 
 type Config struct {
@@ -1,36 +1,242 @@
 package repository
 
 import (
+	"context"
+	"time"
 	musiclibrary "time-tracker"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type Group interface {
-	CreateGroup(group musiclibrary.Group) (int, error)
-	GetAllGroups() ([]musiclibrary.Group, error)
-	DeleteGroup(id int) error
-	UpdateGroup(id int, input musiclibrary.UpdateGroupInput) error
-	GetGroupsWithFilter(map[string]string, int, int) ([]musiclibrary.Group, error)
+	CreateGroup(ctx context.Context, group musiclibrary.Group) (int, error)
+	GetAllGroups(ctx context.Context) ([]musiclibrary.Group, error)
+	GetGroupById(ctx context.Context, id int) (musiclibrary.Group, error)
+	DeleteGroup(ctx context.Context, id int) error
+	UpdateGroup(ctx context.Context, id int, input musiclibrary.UpdateGroupInput) error
+	GetGroupsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Group], error)
 }
 
 type Authorisation interface {
-	CreateSong(song musiclibrary.Song) (int, error)
-	GetAllSongs() ([]musiclibrary.Song, error)
-	DeleteSong(id int) error
-	UpdateSong(id int, input musiclibrary.UpdateSongInput) error
-	GetSongsWithFilter(map[string]string, int, int) ([]musiclibrary.Song, error)
+	CreateSong(ctx context.Context, song musiclibrary.Song) (int, error)
+	GetAllSongs(ctx context.Context) ([]musiclibrary.Song, error)
+	GetSongById(ctx context.Context, id int) (musiclibrary.Song, error)
+	GetSongsByGroupIds(ctx context.Context, groupIds []int) ([]musiclibrary.Song, error)
+	DeleteSong(ctx context.Context, id int) error
+	UpdateSong(ctx context.Context, id int, input musiclibrary.UpdateSongInput) error
+	GetSongsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Song], error)
 }
 type SongDetails interface {
-	GetSongDetailsById(songId int) ([]musiclibrary.SongDetails, error)
-	UpdateSongDetails(id int, input musiclibrary.UpdateSongDetailsInput) error
-	GetSongText(songId int, page int, limit int) ([]string, error)
+	GetSongDetailsById(ctx context.Context, songId int) ([]musiclibrary.SongDetails, error)
+	UpdateSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error
+	// RestoreSongDetails is UpdateSongDetails for all the fields of input,
+	// empty ones clear the field.
+	RestoreSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error
+	GetSongText(ctx context.Context, songId int, page int, limit int) ([]string, error)
+	GetLyrics(ctx context.Context, songId int) (string, error)
+	GetLrc(ctx context.Context, songId int) (string, error)
+	SaveLrc(ctx context.Context, songId int, lrc string) error
+}
+
+type Album interface {
+	CreateAlbum(ctx context.Context, album musiclibrary.Album) (int, error)
+	GetAllAlbums(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Album], error)
+	GetAlbumById(ctx context.Context, id int) (musiclibrary.Album, error)
+	DeleteAlbum(ctx context.Context, id int) error
+	UpdateAlbum(ctx context.Context, id int, input musiclibrary.UpdateAlbumInput) error
+	GetAlbumTracks(ctx context.Context, albumId int) ([]musiclibrary.AlbumTrack, error)
+	GetSongAlbums(ctx context.Context, songIds []int) ([]musiclibrary.SongAlbum, error)
+	AddTrack(ctx context.Context, albumId int, input musiclibrary.AlbumTrackInput) error
+	RemoveTrack(ctx context.Context, albumId, songId int) error
+}
+
+type Artist interface {
+	CreateArtist(ctx context.Context, artist musiclibrary.Artist) (int, error)
+	GetAllArtists(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Artist], error)
+	GetArtistById(ctx context.Context, id int) (musiclibrary.Artist, error)
+	DeleteArtist(ctx context.Context, id int) error
+	UpdateArtist(ctx context.Context, id int, input musiclibrary.UpdateArtistInput) error
+}
+
+type Membership interface {
+	AddMember(ctx context.Context, groupId int, input musiclibrary.MembershipInput) (int, error)
+	RemoveMember(ctx context.Context, groupId, membershipId int) error
+	GetGroupMembers(ctx context.Context, groupId int, from, to string) ([]musiclibrary.Membership, error)
+	GetArtistGroups(ctx context.Context, artistId int) ([]musiclibrary.Membership, error)
+}
+
+type Genre interface {
+	CreateGenre(ctx context.Context, genre musiclibrary.Genre) (int, error)
+	GetAllGenres(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Genre], error)
+	DeleteGenre(ctx context.Context, id int) error
+	GetGenres(ctx context.Context, target musiclibrary.Taggable, targetId int) ([]musiclibrary.Genre, error)
+	AttachGenre(ctx context.Context, target musiclibrary.Taggable, targetId, genreId int) error
+	DetachGenre(ctx context.Context, target musiclibrary.Taggable, targetId, genreId int) error
+}
+
+type Tag interface {
+	GetAllTags(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Tag], error)
+	GetTags(ctx context.Context, target musiclibrary.Taggable, targetId int) ([]musiclibrary.Tag, error)
+	AttachTag(ctx context.Context, target musiclibrary.Taggable, targetId int, name string) error
+	DetachTag(ctx context.Context, target musiclibrary.Taggable, targetId int, name string) error
+}
+
+type Search interface {
+	SearchLyrics(ctx context.Context, q string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.SearchResult], error)
+}
+
+type Translation interface {
+	GetTranslations(ctx context.Context, songId int) ([]musiclibrary.Translation, error)
+	GetTranslation(ctx context.Context, songId int, language string) (musiclibrary.Translation, error)
+	GetOriginal(ctx context.Context, songId int) (musiclibrary.Translation, error)
+	CreateTranslation(ctx context.Context, songId int, translation musiclibrary.Translation) (int, error)
+	UpdateTranslation(ctx context.Context, songId int, language string, input musiclibrary.UpdateTranslationInput) error
+	DeleteTranslation(ctx context.Context, songId int, language string) error
+}
+
+type Revision interface {
+	GetRevisions(ctx context.Context, songId int) ([]musiclibrary.Revision, error)
+	GetRevision(ctx context.Context, songId, revision int) (musiclibrary.Revision, error)
+}
+
+type Import interface {
+	ImportSongs(ctx context.Context, rows []musiclibrary.ImportRow, dryRun bool) ([]musiclibrary.ImportResult, error)
+	RestoreArchive(ctx context.Context, dryRun bool, fn func(w ArchiveRestorer) error) error
+}
+
+type Export interface {
+	Export(ctx context.Context, w ExportWriter) error
+}
+
+type Job interface {
+	EnqueueJob(ctx context.Context, jobType string, payload []byte, maxAttempts int) (int, error)
+	ClaimJob(ctx context.Context, lease time.Duration) (musiclibrary.Job, error)
+	CompleteJob(ctx context.Context, id, claim int) error
+	RescheduleJob(ctx context.Context, id, claim int, jobErr string, delay time.Duration) error
+	DeadLetterJob(ctx context.Context, id, claim int, jobErr string) error
+	RequeueJob(ctx context.Context, id int) error
+	GetJobs(ctx context.Context, status string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Job], error)
+	GetJobById(ctx context.Context, id int) (musiclibrary.Job, error)
+}
+
+// User keeps users and their API keys. Keys are looked up by the SHA-256
+// hash of the key, the key itself is never stored.
+type User interface {
+	CreateUser(ctx context.Context, user musiclibrary.User) (int, error)
+	GetUsers(ctx context.Context) ([]musiclibrary.User, error)
+	GetUserById(ctx context.Context, id int) (musiclibrary.User, error)
+	CreateAPIKey(ctx context.Context, key musiclibrary.APIKey, hash string) (int, error)
+	GetAPIKeys(ctx context.Context, userId int) ([]musiclibrary.APIKey, error)
+	DeleteAPIKey(ctx context.Context, userId, keyId int) error
+	// GetUserByAPIKey returns the owner of the key with the given hash and
+	// records that the key was used.
+	GetUserByAPIKey(ctx context.Context, hash string) (musiclibrary.User, error)
+}
+
+// Library keeps what each user thinks of songs: the songs they starred and
+// how they rated them.
+type Library interface {
+	AddFavorite(ctx context.Context, userId, songId int) error
+	RemoveFavorite(ctx context.Context, userId, songId int) error
+	GetFavorites(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Favorite], error)
+	RateSong(ctx context.Context, userId, songId, rating int) error
+	RemoveRating(ctx context.Context, userId, songId int) error
+	GetRatings(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Rating], error)
+}
+
+// Playlist keeps playlists and their items in order. Changes to the items
+// renumber them, so positions count from 1 without gaps.
+type Playlist interface {
+	CreatePlaylist(ctx context.Context, playlist musiclibrary.Playlist) (int, error)
+	GetPlaylists(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Playlist], error)
+	GetPlaylistById(ctx context.Context, id int) (musiclibrary.Playlist, error)
+	UpdatePlaylist(ctx context.Context, id int, input musiclibrary.UpdatePlaylistInput) error
+	DeletePlaylist(ctx context.Context, id int) error
+	GetPlaylistItems(ctx context.Context, playlistId int) ([]musiclibrary.PlaylistItem, error)
+	// InsertPlaylistItem adds a song at position, or at the end when
+	// position is 0, and returns the id of the new item.
+	InsertPlaylistItem(ctx context.Context, playlistId, songId, position int) (int, error)
+	RemovePlaylistItem(ctx context.Context, playlistId, itemId int) error
+	// ReorderPlaylist puts the items in the order of itemIds, which must
+	// list each of them once.
+	ReorderPlaylist(ctx context.Context, playlistId int, itemIds []int) error
+	// GetPlaylistTracks returns the songs of a playlist in order, with their
+	// group as artist and their link, if any, as location.
+	GetPlaylistTracks(ctx context.Context, playlistId int) ([]musiclibrary.PlaylistTrack, error)
+	// GetTrackCatalog returns every song the way GetPlaylistTracks does, for
+	// matching the entries of imported playlists.
+	GetTrackCatalog(ctx context.Context) ([]musiclibrary.PlaylistTrack, error)
+	// ImportPlaylist creates a playlist holding songIds in order in one go
+	// and returns its id.
+	ImportPlaylist(ctx context.Context, playlist musiclibrary.Playlist, songIds []int) (int, error)
+}
+
+// Play keeps the listening history of each user. Statistics only count
+// scrobbles, and only those in the window of the query.
+type Play interface {
+	// RecordPlays records plays in one transaction and reports on each in
+	// order: PlayRecorded, PlayDuplicate for a play recorded before or
+	// PlayError for a play of a song that does not exist.
+	RecordPlays(ctx context.Context, plays []musiclibrary.Play) ([]musiclibrary.PlayResult, error)
+	// GetLastPlayed returns when each of songIds was last scrobbled by
+	// anyone, leaving out songs never scrobbled.
+	GetLastPlayed(ctx context.Context, songIds []int) (map[int]string, error)
+	GetTopSongs(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.SongStat, error)
+	GetTopGroups(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.GroupStat, error)
+	// GetPlaysPerDay leaves out days without scrobbles.
+	GetPlaysPerDay(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.DayStat, error)
+}
+
+// Similarity keeps the term counts of the lyrics of songs, which the
+// similar songs index is built from. Changing the text of a song deletes its
+// counts, so the song turns up in GetUnindexedSongs again.
+type Similarity interface {
+	GetSongVectors(ctx context.Context) ([]musiclibrary.SongVector, error)
+	// GetUnindexedSongs returns the lyrics of the songs without term counts.
+	GetUnindexedSongs(ctx context.Context) ([]musiclibrary.SongLyrics, error)
+	// SaveSongVector stores the term counts of a song computed from text,
+	// unless the text of the song changed meanwhile. It reports whether the
+	// counts were stored.
+	SaveSongVector(ctx context.Context, vector musiclibrary.SongVector, text string) (bool, error)
+	// GetRemovedSongs returns those of songIds whose term counts are gone,
+	// because the song was deleted or its text changed.
+	GetRemovedSongs(ctx context.Context, songIds []int) ([]int, error)
+	// GetSongFeatures returns the features of those of songIds that exist.
+	GetSongFeatures(ctx context.Context, songIds []int) ([]musiclibrary.SongFeatures, error)
+}
+
+// TxRepositories are the repositories that can take part in a unit of work.
+type TxRepositories struct {
+	Group
+	Authorisation
+	SongDetails
+}
+
+type UnitOfWork interface {
+	InTransaction(ctx context.Context, fn func(repos TxRepositories) error) error
 }
 
 type Repository struct {
 	Group
 	Authorisation
 	SongDetails
+	Album
+	Artist
+	Membership
+	Genre
+	Tag
+	Search
+	Translation
+	Revision
+	Import
+	Export
+	Job
+	User
+	Library
+	Playlist
+	Play
+	Similarity
+	UnitOfWork
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
@@ -38,5 +244,22 @@ func NewRepository(db *sqlx.DB) *Repository {
 		Group:         NewGroupPostgres(db),
 		Authorisation: NewSongPostgres(db),
 		SongDetails:   NewSongDetailsPostgres(db),
+		Album:         NewAlbumPostgres(db),
+		Artist:        NewArtistPostgres(db),
+		Membership:    NewMembershipPostgres(db),
+		Genre:         NewGenrePostgres(db),
+		Tag:           NewTagPostgres(db),
+		Search:        NewSearchPostgres(db),
+		Translation:   NewTranslationPostgres(db),
+		Revision:      NewRevisionPostgres(db),
+		Import:        NewImportPostgres(db),
+		Export:        NewExportPostgres(db),
+		Job:           NewJobPostgres(db),
+		User:          NewUserPostgres(db),
+		Library:       NewLibraryPostgres(db),
+		Playlist:      NewPlaylistPostgres(db),
+		Play:          NewPlayPostgres(db),
+		Similarity:    NewSimilarityPostgres(db),
+		UnitOfWork:    NewUnitOfWorkPostgres(db),
 	}
 }
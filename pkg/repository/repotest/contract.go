@@ -0,0 +1,556 @@
+// Package repotest checks that a storage backend behaves the way the
+// handlers and services expect, so every implementation of the group, song
+// and song details repositories can be held to the same contract.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+var errRollback = errors.New("rollback")
+
+// Run exercises the group, song, song details, revision, similarity and unit
+// of work repositories of repos and returns an error describing every check that
+// failed. It only touches groups it creates itself, named after the time it
+// was started, and deletes them again, so it can run against a live
+// database.
+func Run(ctx context.Context, repos *repository.Repository) error {
+	c := &checker{
+		ctx:    ctx,
+		repos:  repos,
+		prefix: fmt.Sprintf("contract-%d", time.Now().UnixNano()),
+	}
+	defer c.cleanup()
+	c.run()
+	return errors.Join(c.errs...)
+}
+
+type checker struct {
+	ctx    context.Context
+	repos  *repository.Repository
+	prefix string
+	groups []int
+	errs   []error
+}
+
+func (c *checker) errorf(format string, args ...any) {
+	c.errs = append(c.errs, fmt.Errorf(format, args...))
+}
+
+// must records err and reports whether the check can carry on.
+func (c *checker) must(what string, err error) bool {
+	if err != nil {
+		c.errorf("%s: %w", what, err)
+		return false
+	}
+	return true
+}
+
+func (c *checker) cleanup() {
+	for _, id := range c.groups {
+		c.repos.DeleteGroup(context.WithoutCancel(c.ctx), id)
+	}
+}
+
+func (c *checker) createGroup(name string) (int, bool) {
+	id, err := c.repos.CreateGroup(c.ctx, musiclibrary.Group{GroupName: c.prefix + " " + name})
+	if !c.must("CreateGroup", err) {
+		return 0, false
+	}
+	c.groups = append(c.groups, id)
+	return id, true
+}
+
+func (c *checker) run() {
+	alpha, ok := c.createGroup("Alpha")
+	if !ok {
+		return
+	}
+	beta, ok := c.createGroup("Beta")
+	if !ok {
+		return
+	}
+	if beta <= alpha {
+		c.errorf("CreateGroup: ids not increasing: %d then %d", alpha, beta)
+	}
+
+	c.checkGroups(alpha, beta)
+	songs, ok := c.checkSongs(alpha, beta)
+	if !ok {
+		return
+	}
+	c.checkEmptyUpdates(alpha, songs[0])
+	c.checkDetails(songs[0])
+	c.checkLrc(songs[0])
+	c.checkVectors(alpha, songs[0])
+	c.checkRestore(songs[0])
+	c.checkUnitOfWork(alpha)
+	c.checkCascade(alpha, songs)
+}
+
+func (c *checker) checkGroups(alpha, beta int) {
+	group, err := c.repos.GetGroupById(c.ctx, alpha)
+	if c.must("GetGroupById", err) && group.GroupName != c.prefix+" Alpha" {
+		c.errorf("GetGroupById: got name %q, want %q", group.GroupName, c.prefix+" Alpha")
+	}
+	if _, err := c.repos.GetGroupById(c.ctx, -1); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("GetGroupById of a missing group: got %v, want ErrNotFound", err)
+	}
+
+	all, err := c.repos.GetAllGroups(c.ctx)
+	if c.must("GetAllGroups", err) {
+		found := 0
+		for _, g := range all {
+			if g.Id == alpha || g.Id == beta {
+				found++
+			}
+		}
+		if found != 2 {
+			c.errorf("GetAllGroups: found %d of the 2 created groups", found)
+		}
+	}
+
+	if c.must("UpdateGroup with no fields", c.repos.UpdateGroup(c.ctx, beta, musiclibrary.UpdateGroupInput{})) {
+		c.expectGroupName(beta, c.prefix+" Beta")
+	}
+	name := c.prefix + " Beta Renamed"
+	if c.must("UpdateGroup", c.repos.UpdateGroup(c.ctx, beta, musiclibrary.UpdateGroupInput{GroupName: &name})) {
+		c.expectGroupName(beta, name)
+	}
+	if err := c.repos.UpdateGroup(c.ctx, -1, musiclibrary.UpdateGroupInput{GroupName: &name}); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("UpdateGroup of a missing group: got %v, want ErrNotFound", err)
+	}
+	if err := c.repos.DeleteGroup(c.ctx, -1); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("DeleteGroup of a missing group: got %v, want ErrNotFound", err)
+	}
+
+	filters := map[string]string{"groupname": c.prefix}
+	page := musiclibrary.PageRequest{Limit: 1, WithTotal: true}
+	for i, want := range [][]int{{alpha}, {beta}} {
+		got, err := c.repos.GetGroupsWithFilter(c.ctx, filters, page)
+		if !c.must("GetGroupsWithFilter", err) {
+			return
+		}
+		if !reflect.DeepEqual(groupIds(got.Items), want) {
+			c.errorf("GetGroupsWithFilter page %d: got %v, want %v", i+1, groupIds(got.Items), want)
+		}
+		if got.Total == nil || *got.Total != 2 {
+			c.errorf("GetGroupsWithFilter page %d: got total %v, want 2", i+1, got.Total)
+		}
+		if last := i == 1; last != (got.Next == "") {
+			c.errorf("GetGroupsWithFilter page %d: got next cursor %q on the last page %v", i+1, got.Next, last)
+		}
+		page.After = got.Next
+	}
+	got, err := c.repos.GetGroupsWithFilter(c.ctx, filters, musiclibrary.PageRequest{Limit: 1, Offset: 1})
+	if c.must("GetGroupsWithFilter", err) && (!reflect.DeepEqual(groupIds(got.Items), []int{beta}) || got.Total != nil) {
+		c.errorf("GetGroupsWithFilter with offset 1: got %v total %v, want %v and no total", groupIds(got.Items), got.Total, []int{beta})
+	}
+	if _, err := c.repos.GetGroupsWithFilter(c.ctx, filters, musiclibrary.PageRequest{Limit: 1, After: "not a cursor"}); !errors.Is(err, musiclibrary.ErrValidation) {
+		c.errorf("GetGroupsWithFilter with an invalid cursor: got %v, want ErrValidation", err)
+	}
+	got, err = c.repos.GetGroupsWithFilter(c.ctx, map[string]string{"groupname": c.prefix + " BETA RENAMED"}, musiclibrary.PageRequest{Limit: 10})
+	if c.must("GetGroupsWithFilter", err) && !reflect.DeepEqual(groupIds(got.Items), []int{beta}) {
+		c.errorf("GetGroupsWithFilter is not case insensitive: got %v, want %v", groupIds(got.Items), []int{beta})
+	}
+}
+
+func (c *checker) expectGroupName(id int, want string) {
+	group, err := c.repos.GetGroupById(c.ctx, id)
+	if c.must("GetGroupById", err) && group.GroupName != want {
+		c.errorf("group %d: got name %q, want %q", id, group.GroupName, want)
+	}
+}
+
+// checkSongs creates two songs in alpha and one in beta and returns their ids.
+func (c *checker) checkSongs(alpha, beta int) ([]int, bool) {
+	var ids []int
+	for _, song := range []musiclibrary.Song{
+		{SongName: "First Song", GroupId: alpha},
+		{SongName: "Second Song", GroupId: alpha},
+		{SongName: "Third Song", GroupId: beta},
+	} {
+		id, err := c.repos.CreateSong(c.ctx, song)
+		if !c.must("CreateSong", err) {
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	if _, err := c.repos.CreateSong(c.ctx, musiclibrary.Song{SongName: "Orphan", GroupId: -1}); !errors.Is(err, musiclibrary.ErrForeignKey) {
+		c.errorf("CreateSong in a missing group: got %v, want ErrForeignKey", err)
+	}
+
+	song, err := c.repos.GetSongById(c.ctx, ids[0])
+	want := musiclibrary.Song{Id: ids[0], SongName: "First Song", GroupId: alpha}
+	if c.must("GetSongById", err) && !reflect.DeepEqual(song, want) {
+		c.errorf("GetSongById: got %+v, want %+v", song, want)
+	}
+	if _, err := c.repos.GetSongById(c.ctx, -1); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("GetSongById of a missing song: got %v, want ErrNotFound", err)
+	}
+
+	moved := beta
+	if c.must("UpdateSong", c.repos.UpdateSong(c.ctx, ids[1], musiclibrary.UpdateSongInput{GroupId: &moved})) {
+		back := alpha
+		c.must("UpdateSong", c.repos.UpdateSong(c.ctx, ids[1], musiclibrary.UpdateSongInput{GroupId: &back}))
+	}
+	missing := -1
+	if err := c.repos.UpdateSong(c.ctx, ids[1], musiclibrary.UpdateSongInput{GroupId: &missing}); !errors.Is(err, musiclibrary.ErrForeignKey) {
+		c.errorf("UpdateSong into a missing group: got %v, want ErrForeignKey", err)
+	}
+	if err := c.repos.UpdateSong(c.ctx, -1, musiclibrary.UpdateSongInput{GroupId: &moved}); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("UpdateSong of a missing song: got %v, want ErrNotFound", err)
+	}
+	if err := c.repos.DeleteSong(c.ctx, -1); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("DeleteSong of a missing song: got %v, want ErrNotFound", err)
+	}
+
+	byGroup, err := c.repos.GetSongsByGroupIds(c.ctx, []int{beta, alpha, -1})
+	if c.must("GetSongsByGroupIds", err) && !reflect.DeepEqual(songIds(byGroup), ids) {
+		c.errorf("GetSongsByGroupIds: got %v, want %v", songIds(byGroup), ids)
+	}
+
+	for _, tc := range []struct {
+		filters map[string]string
+		page    musiclibrary.PageRequest
+		want    []int
+	}{
+		{map[string]string{"groupname": c.prefix + " alpha"}, musiclibrary.PageRequest{Limit: 10}, ids[:2]},
+		{map[string]string{"groupname": c.prefix, "songname": "song"}, musiclibrary.PageRequest{Limit: 2}, ids[:2]},
+		{map[string]string{"groupname": c.prefix, "songname": "song"}, musiclibrary.PageRequest{Limit: 2, Offset: 2}, ids[2:]},
+		{map[string]string{"groupname": c.prefix, "songname": "third"}, musiclibrary.PageRequest{Limit: 10}, ids[2:]},
+		{map[string]string{"groupname": c.prefix, "link": "N/A"}, musiclibrary.PageRequest{Limit: 10}, ids},
+		{map[string]string{"groupname": c.prefix, "releasedate": "1999"}, musiclibrary.PageRequest{Limit: 10}, nil},
+	} {
+		got, err := c.repos.GetSongsWithFilter(c.ctx, tc.filters, tc.page)
+		if c.must("GetSongsWithFilter", err) && !reflect.DeepEqual(songIds(got.Items), tc.want) {
+			c.errorf("GetSongsWithFilter %v limit %d offset %d: got %v, want %v",
+				tc.filters, tc.page.Limit, tc.page.Offset, songIds(got.Items), tc.want)
+		}
+	}
+
+	// The cursor points after the last song read, so a song added since
+	// shows up on the next page.
+	filters := map[string]string{"groupname": c.prefix, "songname": "song"}
+	first, err := c.repos.GetSongsWithFilter(c.ctx, filters, musiclibrary.PageRequest{Limit: 2, WithTotal: true})
+	if c.must("GetSongsWithFilter", err) {
+		if first.Total == nil || *first.Total != len(ids) {
+			c.errorf("GetSongsWithFilter: got total %v, want %d", first.Total, len(ids))
+		}
+		added, err := c.repos.CreateSong(c.ctx, musiclibrary.Song{SongName: "Fourth Song", GroupId: alpha})
+		if c.must("CreateSong", err) {
+			second, err := c.repos.GetSongsWithFilter(c.ctx, filters, musiclibrary.PageRequest{Limit: 2, After: first.Next})
+			if want := []int{ids[2], added}; c.must("GetSongsWithFilter", err) && (!reflect.DeepEqual(songIds(second.Items), want) || second.Next != "") {
+				c.errorf("GetSongsWithFilter after cursor %q: got %v next %q, want %v and no next", first.Next, songIds(second.Items), second.Next, want)
+			}
+			c.must("DeleteSong", c.repos.DeleteSong(c.ctx, added))
+		}
+	}
+	return ids, true
+}
+
+// checkEmptyUpdates expects updates that set nothing to succeed for rows
+// that exist and to report those that do not.
+func (c *checker) checkEmptyUpdates(groupId, songId int) {
+	for _, id := range []int{groupId, -1} {
+		want := id == -1
+		if err := c.repos.UpdateGroup(c.ctx, id, musiclibrary.UpdateGroupInput{}); errors.Is(err, musiclibrary.ErrNotFound) != want || (!want && err != nil) {
+			c.errorf("UpdateGroup(%d) without fields: got %v, want NotFound %v", id, err, want)
+		}
+	}
+	for _, id := range []int{songId, -1} {
+		want := id == -1
+		if err := c.repos.UpdateSong(c.ctx, id, musiclibrary.UpdateSongInput{}); errors.Is(err, musiclibrary.ErrNotFound) != want || (!want && err != nil) {
+			c.errorf("UpdateSong(%d) without fields: got %v, want NotFound %v", id, err, want)
+		}
+		if err := c.repos.UpdateSongDetails(c.ctx, id, musiclibrary.UpdateSongDetailsInput{}); errors.Is(err, musiclibrary.ErrNotFound) != want || (!want && err != nil) {
+			c.errorf("UpdateSongDetails(%d) without fields: got %v, want NotFound %v", id, err, want)
+		}
+	}
+}
+
+func (c *checker) checkDetails(songId int) {
+	details, err := c.repos.GetSongDetailsById(c.ctx, songId)
+	if c.must("GetSongDetailsById", err) {
+		if len(details) != 1 {
+			c.errorf("GetSongDetailsById: got %d rows, want 1", len(details))
+		} else if d := details[0]; d.SongId != songId || d.ReleaseDate != "" || d.Text != "N/A" || d.Link != "N/A" {
+			c.errorf("GetSongDetailsById of a new song: got %+v, want no release date and N/A text and link", d)
+		}
+	}
+
+	input := musiclibrary.UpdateSongDetailsInput{
+		ReleaseDate: "1999-12-31",
+		Text:        "one\n\ntwo\n\nthree",
+		Author:      "contract",
+		Comment:     "first edit",
+	}
+	if !c.must("UpdateSongDetails", c.repos.UpdateSongDetails(c.ctx, songId, input)) {
+		return
+	}
+	if err := c.repos.UpdateSongDetails(c.ctx, songId, musiclibrary.UpdateSongDetailsInput{ReleaseDate: "31.12.1999"}); !errors.Is(err, musiclibrary.ErrValidation) {
+		c.errorf("UpdateSongDetails with an invalid date: got %v, want ErrValidation", err)
+	}
+
+	details, err = c.repos.GetSongDetailsById(c.ctx, songId)
+	if c.must("GetSongDetailsById", err) && len(details) == 1 {
+		if d := details[0]; d.ReleaseDate != input.ReleaseDate || d.Text != input.Text || d.Link != "N/A" {
+			c.errorf("GetSongDetailsById after update: got %+v", d)
+		}
+	}
+
+	verses, err := c.repos.GetSongText(c.ctx, songId, 2, 1)
+	if c.must("GetSongText", err) && !reflect.DeepEqual(verses, []string{"two"}) {
+		c.errorf("GetSongText page 2 limit 1: got %q, want [two]", verses)
+	}
+	verses, err = c.repos.GetSongText(c.ctx, songId, 4, 1)
+	if c.must("GetSongText", err) && len(verses) != 0 {
+		c.errorf("GetSongText past the last verse: got %q, want none", verses)
+	}
+	if _, err := c.repos.GetSongText(c.ctx, -1, 1, 1); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("GetSongText of a missing song: got %v, want ErrNotFound", err)
+	}
+
+	got, err := c.repos.GetSongsWithFilter(c.ctx, map[string]string{"groupname": c.prefix, "text": "TWO", "releasedate": "1999"}, musiclibrary.PageRequest{Limit: 10})
+	if c.must("GetSongsWithFilter", err) && !reflect.DeepEqual(songIds(got.Items), []int{songId}) {
+		c.errorf("GetSongsWithFilter by text and release date: got %v, want %v", songIds(got.Items), []int{songId})
+	}
+
+	revisions, err := c.repos.GetRevisions(c.ctx, songId)
+	if c.must("GetRevisions", err) {
+		if len(revisions) != 2 {
+			c.errorf("GetRevisions after one edit: got %d revisions, want 2", len(revisions))
+		} else {
+			if r := revisions[0]; r.Revision != 1 || r.Comment != "Initial version" || r.ReleaseDate != "" {
+				c.errorf("GetRevisions: first revision is %+v, want the initial version", r)
+			}
+			if r := revisions[1]; r.Revision != 2 || r.Author != "contract" || r.Comment != "first edit" || r.Text != "" {
+				c.errorf("GetRevisions: second revision is %+v, want the edit without its text", r)
+			}
+		}
+	}
+	rev, err := c.repos.GetRevision(c.ctx, songId, 2)
+	if c.must("GetRevision", err) && rev.Text != input.Text {
+		c.errorf("GetRevision: got text %q, want %q", rev.Text, input.Text)
+	}
+	if _, err := c.repos.GetRevision(c.ctx, songId, 3); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("GetRevision of a missing revision: got %v, want ErrNotFound", err)
+	}
+}
+
+func (c *checker) checkLrc(songId int) {
+	if _, err := c.repos.GetLrc(c.ctx, songId); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("GetLrc before saving: got %v, want ErrNotFound", err)
+	}
+	for _, lrc := range []string{"[00:01.00]one", "[00:02.00]two"} {
+		if !c.must("SaveLrc", c.repos.SaveLrc(c.ctx, songId, lrc)) {
+			return
+		}
+		got, err := c.repos.GetLrc(c.ctx, songId)
+		if c.must("GetLrc", err) && got != lrc {
+			c.errorf("GetLrc: got %q, want %q", got, lrc)
+		}
+	}
+}
+
+// checkVectors expects term counts to be kept only for the text they were
+// computed from.
+func (c *checker) checkVectors(alpha, songId int) {
+	text, ok := c.unindexedText(songId)
+	if !ok {
+		c.errorf("GetUnindexedSongs: song %d without term counts is missing", songId)
+		return
+	}
+	vector := musiclibrary.SongVector{SongId: songId, Terms: map[string]int{"one": 1, "two": 1}}
+	if saved, err := c.repos.SaveSongVector(c.ctx, vector, "stale"); c.must("SaveSongVector", err) && saved {
+		c.errorf("SaveSongVector for a text the song no longer has: saved, want skipped")
+	}
+	if saved, err := c.repos.SaveSongVector(c.ctx, vector, text); !c.must("SaveSongVector", err) || !saved {
+		c.errorf("SaveSongVector for the current text: not saved")
+		return
+	}
+	if _, ok := c.unindexedText(songId); ok {
+		c.errorf("GetUnindexedSongs: song %d is listed after its term counts were saved", songId)
+	}
+	vectors, err := c.repos.GetSongVectors(c.ctx)
+	if c.must("GetSongVectors", err) {
+		found := false
+		for _, v := range vectors {
+			if v.SongId == songId {
+				found = reflect.DeepEqual(v.Terms, vector.Terms)
+			}
+		}
+		if !found {
+			c.errorf("GetSongVectors: term counts of song %d missing or changed", songId)
+		}
+	}
+	if removed, err := c.repos.GetRemovedSongs(c.ctx, []int{songId, -1}); c.must("GetRemovedSongs", err) && !reflect.DeepEqual(removed, []int{-1}) {
+		c.errorf("GetRemovedSongs: got %v, want only the missing song -1", removed)
+	}
+
+	features, err := c.repos.GetSongFeatures(c.ctx, []int{songId, -1})
+	if c.must("GetSongFeatures", err) {
+		if len(features) != 1 || features[0].SongId != songId || features[0].GroupId != alpha || features[0].ReleaseDate != "1999-12-31" {
+			c.errorf("GetSongFeatures: got %+v, want song %d of group %d released 1999-12-31", features, songId, alpha)
+		}
+	}
+
+	if !c.must("UpdateSongDetails", c.repos.UpdateSongDetails(c.ctx, songId, musiclibrary.UpdateSongDetailsInput{Link: "https://example.com"})) {
+		return
+	}
+	if _, ok := c.unindexedText(songId); ok {
+		c.errorf("GetUnindexedSongs: song %d is listed after only its link changed", songId)
+	}
+	if !c.must("UpdateSongDetails", c.repos.UpdateSongDetails(c.ctx, songId, musiclibrary.UpdateSongDetailsInput{Text: text + "\n\nfour"})) {
+		return
+	}
+	if _, ok := c.unindexedText(songId); !ok {
+		c.errorf("GetUnindexedSongs: song %d is missing after its text changed", songId)
+	}
+	if removed, err := c.repos.GetRemovedSongs(c.ctx, []int{songId}); c.must("GetRemovedSongs", err) && !reflect.DeepEqual(removed, []int{songId}) {
+		c.errorf("GetRemovedSongs: got %v, want song %d whose text changed", removed, songId)
+	}
+}
+
+// checkRestore rolls the details back to the initial version, which has no
+// release date, and then clears them altogether.
+func (c *checker) checkRestore(songId int) {
+	initial, err := c.repos.GetRevision(c.ctx, songId, 1)
+	if !c.must("GetRevision", err) {
+		return
+	}
+	before, err := c.repos.GetRevisions(c.ctx, songId)
+	if !c.must("GetRevisions", err) {
+		return
+	}
+	for _, want := range []musiclibrary.SongDetails{
+		{ReleaseDate: initial.ReleaseDate, Text: initial.Text, Link: initial.Link},
+		{},
+	} {
+		input := musiclibrary.UpdateSongDetailsInput{ReleaseDate: want.ReleaseDate, Text: want.Text, Link: want.Link, Author: "contract"}
+		if !c.must("RestoreSongDetails", c.repos.RestoreSongDetails(c.ctx, songId, input)) {
+			return
+		}
+		details, err := c.repos.GetSongDetailsById(c.ctx, songId)
+		if c.must("GetSongDetailsById", err) && len(details) == 1 {
+			if d := details[0]; d.ReleaseDate != want.ReleaseDate || d.Text != want.Text || d.Link != want.Link {
+				c.errorf("GetSongDetailsById after RestoreSongDetails(%+v): got %+v", input, d)
+			}
+		}
+	}
+	if initial.ReleaseDate != "" {
+		c.errorf("GetRevision: initial version has release date %q, want none", initial.ReleaseDate)
+	}
+	after, err := c.repos.GetRevisions(c.ctx, songId)
+	if c.must("GetRevisions", err) && len(after) != len(before)+2 {
+		c.errorf("GetRevisions after two restores: got %d revisions, want %d", len(after), len(before)+2)
+	}
+	if err := c.repos.RestoreSongDetails(c.ctx, -1, musiclibrary.UpdateSongDetailsInput{}); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("RestoreSongDetails of a missing song: got %v, want ErrNotFound", err)
+	}
+}
+
+func (c *checker) unindexedText(songId int) (string, bool) {
+	songs, err := c.repos.GetUnindexedSongs(c.ctx)
+	if !c.must("GetUnindexedSongs", err) {
+		return "", false
+	}
+	for _, song := range songs {
+		if song.SongId == songId {
+			return song.Text, true
+		}
+	}
+	return "", false
+}
+
+func (c *checker) checkUnitOfWork(alpha int) {
+	name := c.prefix + " Rolled Back"
+	err := c.repos.InTransaction(c.ctx, func(repos repository.TxRepositories) error {
+		id, err := repos.CreateGroup(c.ctx, musiclibrary.Group{GroupName: name})
+		if err != nil {
+			return err
+		}
+		c.groups = append(c.groups, id)
+		if _, err := repos.CreateSong(c.ctx, musiclibrary.Song{SongName: "Rolled Back", GroupId: alpha}); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		c.errorf("InTransaction: got %v, want the error returned by fn", err)
+	}
+	if got, err := c.repos.GetGroupsWithFilter(c.ctx, map[string]string{"groupname": name}, musiclibrary.PageRequest{Limit: 10}); c.must("GetGroupsWithFilter", err) && len(got.Items) != 0 {
+		c.errorf("InTransaction: group created in a rolled back transaction still exists")
+	}
+	if got, err := c.repos.GetSongsWithFilter(c.ctx, map[string]string{"groupname": c.prefix, "songname": "rolled back"}, musiclibrary.PageRequest{Limit: 10}); c.must("GetSongsWithFilter", err) && len(got.Items) != 0 {
+		c.errorf("InTransaction: song created in a rolled back transaction still exists")
+	}
+
+	var id int
+	err = c.repos.InTransaction(c.ctx, func(repos repository.TxRepositories) error {
+		var err error
+		id, err = repos.CreateSong(c.ctx, musiclibrary.Song{SongName: "Committed", GroupId: alpha})
+		return err
+	})
+	if c.must("InTransaction", err) {
+		if _, err := c.repos.GetSongById(c.ctx, id); err != nil {
+			c.errorf("InTransaction: song created in a committed transaction: %v", err)
+		}
+	}
+}
+
+// checkCascade deletes a song and then the group holding the others, and
+// expects everything belonging to them to go too.
+func (c *checker) checkCascade(alpha int, songs []int) {
+	if !c.must("DeleteSong", c.repos.DeleteSong(c.ctx, songs[1])) {
+		return
+	}
+	c.expectSongGone(songs[1])
+	if !c.must("DeleteGroup", c.repos.DeleteGroup(c.ctx, alpha)) {
+		return
+	}
+	if _, err := c.repos.GetGroupById(c.ctx, alpha); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("GetGroupById after DeleteGroup: got %v, want ErrNotFound", err)
+	}
+	c.expectSongGone(songs[0])
+	if _, err := c.repos.GetLrc(c.ctx, songs[0]); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("GetLrc after DeleteGroup: got %v, want ErrNotFound", err)
+	}
+	if revisions, err := c.repos.GetRevisions(c.ctx, songs[0]); c.must("GetRevisions", err) && len(revisions) != 0 {
+		c.errorf("GetRevisions after DeleteGroup: got %d revisions, want none", len(revisions))
+	}
+	if _, err := c.repos.GetSongById(c.ctx, songs[2]); err != nil {
+		c.errorf("GetSongById of a song in another group after DeleteGroup: %v", err)
+	}
+}
+
+func (c *checker) expectSongGone(id int) {
+	if _, err := c.repos.GetSongById(c.ctx, id); !errors.Is(err, musiclibrary.ErrNotFound) {
+		c.errorf("GetSongById of deleted song %d: got %v, want ErrNotFound", id, err)
+	}
+	if details, err := c.repos.GetSongDetailsById(c.ctx, id); c.must("GetSongDetailsById", err) && len(details) != 0 {
+		c.errorf("GetSongDetailsById of deleted song %d: got %d rows, want none", id, len(details))
+	}
+}
+
+func groupIds(groups []musiclibrary.Group) []int {
+	var ids []int
+	for _, g := range groups {
+		ids = append(ids, g.Id)
+	}
+	return ids
+}
+
+func songIds(songs []musiclibrary.Song) []int {
+	var ids []int
+	for _, s := range songs {
+		ids = append(ids, s.Id)
+	}
+	return ids
+}
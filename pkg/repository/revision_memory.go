@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	musiclibrary "time-tracker"
+)
+
+type RevisionMemory struct {
+	store *memoryStore
+}
+
+// GetRevisions lists the revisions of a song's details without their lyrics.
+func (r *RevisionMemory) GetRevisions(ctx context.Context, songId int) ([]musiclibrary.Revision, error) {
+	var revisions []musiclibrary.Revision
+	err := r.store.read(ctx, func() error {
+		for _, rev := range r.store.revisions[songId] {
+			rev.Text = ""
+			revisions = append(revisions, rev)
+		}
+		return nil
+	})
+	return revisions, err
+}
+
+func (r *RevisionMemory) GetRevision(ctx context.Context, songId, revision int) (musiclibrary.Revision, error) {
+	var rev musiclibrary.Revision
+	err := r.store.read(ctx, func() error {
+		for _, candidate := range r.store.revisions[songId] {
+			if candidate.Revision == revision {
+				rev = candidate
+				return nil
+			}
+		}
+		return notFound("revision")
+	})
+	return rev, err
+}
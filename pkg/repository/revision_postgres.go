@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const revisionColumns = `id, songId AS songid, revision,
+	COALESCE(TO_CHAR(releaseDate, 'YYYY-MM-DD'), '') AS releasedate,
+	COALESCE(link, '') AS link, author, comment,
+	TO_CHAR(createdAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS createdat`
+
+type RevisionPostgres struct {
+	db *sqlx.DB
+}
+
+func NewRevisionPostgres(db *sqlx.DB) *RevisionPostgres {
+	return &RevisionPostgres{db: db}
+}
+
+// GetRevisions lists the revisions of a song's details without their lyrics.
+func (r *RevisionPostgres) GetRevisions(ctx context.Context, songId int) ([]musiclibrary.Revision, error) {
+	logrus.WithField("songId", songId).Debug("Fetching song detail revisions")
+	var revisions []musiclibrary.Revision
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE songId = $1 ORDER BY revision", revisionColumns, revisionsTable)
+	err := r.db.SelectContext(ctx, &revisions, query, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch song detail revisions")
+		return nil, dbError(err, "revision")
+	}
+	logrus.WithField("count", len(revisions)).Info("Fetched song detail revisions successfully")
+	return revisions, nil
+}
+
+func (r *RevisionPostgres) GetRevision(ctx context.Context, songId, revision int) (musiclibrary.Revision, error) {
+	logrus.WithFields(logrus.Fields{
+		"songId":   songId,
+		"revision": revision,
+	}).Debug("Fetching song detail revision")
+	var rev musiclibrary.Revision
+	query := fmt.Sprintf("SELECT %s, COALESCE(text, '') AS text FROM %s WHERE songId = $1 AND revision = $2",
+		revisionColumns, revisionsTable)
+	err := r.db.GetContext(ctx, &rev, query, songId, revision)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch song detail revision")
+		return rev, dbError(err, "revision")
+	}
+	return rev, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const revisionColumnsSQLite = `id, songid, revision, COALESCE(releasedate, '') AS releasedate,
+	COALESCE(link, '') AS link, author, comment, createdat`
+
+type RevisionSQLite struct {
+	db *sqlx.DB
+}
+
+func NewRevisionSQLite(db *sqlx.DB) *RevisionSQLite {
+	return &RevisionSQLite{db: db}
+}
+
+// GetRevisions lists the revisions of a song's details without their lyrics.
+func (r *RevisionSQLite) GetRevisions(ctx context.Context, songId int) ([]musiclibrary.Revision, error) {
+	logrus.WithField("songId", songId).Debug("Fetching song detail revisions")
+	var revisions []musiclibrary.Revision
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE songid = ? ORDER BY revision", revisionColumnsSQLite, revisionsTable)
+	if err := r.db.SelectContext(ctx, &revisions, query, songId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch song detail revisions")
+		return nil, dbError(err, "revision")
+	}
+	logrus.WithField("count", len(revisions)).Info("Fetched song detail revisions successfully")
+	return revisions, nil
+}
+
+func (r *RevisionSQLite) GetRevision(ctx context.Context, songId, revision int) (musiclibrary.Revision, error) {
+	logrus.WithFields(logrus.Fields{
+		"songId":   songId,
+		"revision": revision,
+	}).Debug("Fetching song detail revision")
+	var rev musiclibrary.Revision
+	query := fmt.Sprintf("SELECT %s, COALESCE(text, '') AS text FROM %s WHERE songid = ? AND revision = ?",
+		revisionColumnsSQLite, revisionsTable)
+	if err := r.db.GetContext(ctx, &rev, query, songId, revision); err != nil {
+		logrus.WithError(err).Error("Failed to fetch song detail revision")
+		return rev, dbError(err, "revision")
+	}
+	return rev, nil
+}
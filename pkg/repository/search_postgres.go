@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// searchConfig is the text search configuration the searchVector column of
+// songDetails is generated with, queries must use the same one to hit the index.
+const searchConfig = "english"
+
+type SearchPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSearchPostgres(db *sqlx.DB) *SearchPostgres {
+	return &SearchPostgres{db: db}
+}
+
+type searchRow struct {
+	musiclibrary.SearchResult
+	Lines pq.StringArray `db:"lines"`
+}
+
+// SearchLyrics runs a web-search style query ("easy come" -devil) against the
+// lyrics and returns the songs ranked by relevance. Every result carries a
+// highlighted snippet and the individual lyric lines that match the query.
+func (r *SearchPostgres) SearchLyrics(ctx context.Context, q string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.SearchResult], error) {
+	logrus.WithField("q", q).Debug("Searching lyrics")
+	query := fmt.Sprintf(`
+		SELECT s.id AS songid, s.songName AS songname, g.id AS groupid, g.groupName AS groupname,
+			ts_rank_cd(sd.searchVector, q) AS rank,
+			ts_headline('%[1]s', sd.text, q,
+				'StartSel=<b>, StopSel=</b>, MaxFragments=3, MinWords=5, MaxWords=20, FragmentDelimiter=" ... "') AS snippet,
+			ARRAY(
+				SELECT ts_headline('%[1]s', line, q, 'StartSel=<b>, StopSel=</b>, HighlightAll=true')
+				FROM unnest(string_to_array(sd.text, E'\n')) AS line
+				WHERE to_tsvector('%[1]s', line) @@ q
+			) AS lines
+		FROM %[2]s sd
+		JOIN %[3]s s ON s.id = sd.songId
+		JOIN %[4]s g ON g.id = s.groupId,
+		websearch_to_tsquery('%[1]s', $1) q
+		WHERE sd.searchVector @@ q`, searchConfig, songDetailsTable, songsTable, groupsTable)
+
+	rows, err := selectPage(ctx, r.db, query, []any{q}, sortByRank, page, pgPlaceholder, func(row searchRow) []any {
+		return []any{row.Rank, row.SongId}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to search lyrics")
+		return musiclibrary.Page[musiclibrary.SearchResult]{}, err
+	}
+
+	results := musiclibrary.Page[musiclibrary.SearchResult]{
+		Items: make([]musiclibrary.SearchResult, 0, len(rows.Items)),
+		Next:  rows.Next,
+		Total: rows.Total,
+	}
+	for _, row := range rows.Items {
+		result := row.SearchResult
+		result.Lines = row.Lines
+		results.Items = append(results.Items, result)
+	}
+	logrus.WithField("count", len(results.Items)).Info("Searched lyrics successfully")
+	return results, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"encoding/json"
+	musiclibrary "time-tracker"
+)
+
+// songVectorRow is a SongVector as stored, with the term counts as JSON.
+type songVectorRow struct {
+	SongId int    `db:"songid"`
+	Terms  string `db:"terms"`
+}
+
+func songVectors(rows []songVectorRow) ([]musiclibrary.SongVector, error) {
+	vectors := make([]musiclibrary.SongVector, 0, len(rows))
+	for _, row := range rows {
+		vector := musiclibrary.SongVector{SongId: row.SongId}
+		if err := json.Unmarshal([]byte(row.Terms), &vector.Terms); err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+// songFeaturesRow is a SongFeatures as selected, before the genre ids are
+// scanned into Genres.
+type songFeaturesRow struct {
+	SongId      int    `db:"songid"`
+	SongName    string `db:"songname"`
+	GroupId     int    `db:"groupid"`
+	GroupName   string `db:"groupname"`
+	ReleaseDate string `db:"releasedate"`
+}
+
+func (row songFeaturesRow) features(genres []int) musiclibrary.SongFeatures {
+	return musiclibrary.SongFeatures{
+		SongId:      row.SongId,
+		SongName:    row.SongName,
+		GroupId:     row.GroupId,
+		GroupName:   row.GroupName,
+		ReleaseDate: row.ReleaseDate,
+		Genres:      genres,
+	}
+}
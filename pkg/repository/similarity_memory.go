@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"sort"
+	musiclibrary "time-tracker"
+)
+
+type SimilarityMemory struct {
+	store *memoryStore
+}
+
+func (r *SimilarityMemory) GetSongVectors(ctx context.Context) ([]musiclibrary.SongVector, error) {
+	var vectors []musiclibrary.SongVector
+	err := r.store.read(ctx, func() error {
+		vectors = make([]musiclibrary.SongVector, 0, len(r.store.vectors))
+		for songId, terms := range r.store.vectors {
+			vectors = append(vectors, musiclibrary.SongVector{SongId: songId, Terms: maps.Clone(terms)})
+		}
+		return nil
+	})
+	return vectors, err
+}
+
+func (r *SimilarityMemory) GetUnindexedSongs(ctx context.Context) ([]musiclibrary.SongLyrics, error) {
+	var songs []musiclibrary.SongLyrics
+	err := r.store.read(ctx, func() error {
+		for songId, details := range r.store.details {
+			if _, ok := r.store.vectors[songId]; !ok {
+				songs = append(songs, musiclibrary.SongLyrics{SongId: songId, Text: details.Text})
+			}
+		}
+		return nil
+	})
+	sort.Slice(songs, func(i, j int) bool { return songs[i].SongId < songs[j].SongId })
+	return songs, err
+}
+
+func (r *SimilarityMemory) SaveSongVector(ctx context.Context, vector musiclibrary.SongVector, text string) (bool, error) {
+	var saved bool
+	err := r.store.write(ctx, func() error {
+		if details, ok := r.store.details[vector.SongId]; ok && details.Text == text {
+			r.store.vectors[vector.SongId] = maps.Clone(vector.Terms)
+			saved = true
+		}
+		return nil
+	})
+	return saved, err
+}
+
+func (r *SimilarityMemory) GetRemovedSongs(ctx context.Context, songIds []int) ([]int, error) {
+	removed := []int{}
+	err := r.store.read(ctx, func() error {
+		for _, songId := range songIds {
+			if _, ok := r.store.vectors[songId]; !ok {
+				removed = append(removed, songId)
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// GetSongFeatures leaves Genres empty, the memory store keeps no genres.
+func (r *SimilarityMemory) GetSongFeatures(ctx context.Context, songIds []int) ([]musiclibrary.SongFeatures, error) {
+	var features []musiclibrary.SongFeatures
+	err := r.store.read(ctx, func() error {
+		for _, songId := range songIds {
+			song, ok := r.store.songs[songId]
+			if !ok {
+				continue
+			}
+			features = append(features, musiclibrary.SongFeatures{
+				SongId:      song.Id,
+				SongName:    song.SongName,
+				GroupId:     song.GroupId,
+				GroupName:   r.store.groups[song.GroupId].GroupName,
+				ReleaseDate: r.store.details[songId].ReleaseDate,
+			})
+		}
+		return nil
+	})
+	return features, err
+}
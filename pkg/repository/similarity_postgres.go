@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type SimilarityPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSimilarityPostgres(db *sqlx.DB) *SimilarityPostgres {
+	return &SimilarityPostgres{db: db}
+}
+
+func (r *SimilarityPostgres) GetSongVectors(ctx context.Context) ([]musiclibrary.SongVector, error) {
+	var rows []songVectorRow
+	query := fmt.Sprintf("SELECT songId AS songid, terms FROM %s", songVectorsTable)
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch song vectors")
+		return nil, dbError(err, "song vector")
+	}
+	return songVectors(rows)
+}
+
+func (r *SimilarityPostgres) GetUnindexedSongs(ctx context.Context) ([]musiclibrary.SongLyrics, error) {
+	var songs []musiclibrary.SongLyrics
+	query := fmt.Sprintf(`
+		SELECT sd.songId AS songid, COALESCE(sd.text, '') AS text
+		FROM %s sd
+		WHERE NOT EXISTS (SELECT 1 FROM %s v WHERE v.songId = sd.songId)`, songDetailsTable, songVectorsTable)
+	if err := r.db.SelectContext(ctx, &songs, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch unindexed songs")
+		return nil, dbError(err, "song details")
+	}
+	return songs, nil
+}
+
+func (r *SimilarityPostgres) SaveSongVector(ctx context.Context, vector musiclibrary.SongVector, text string) (bool, error) {
+	terms, err := json.Marshal(vector.Terms)
+	if err != nil {
+		return false, err
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (songId, terms)
+		SELECT songId, $2::text FROM %s WHERE songId = $1 AND COALESCE(text, '') = $3
+		ON CONFLICT (songId) DO UPDATE SET terms = EXCLUDED.terms`, songVectorsTable, songDetailsTable)
+	res, err := r.db.ExecContext(ctx, query, vector.SongId, string(terms), text)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to save song vector")
+		return false, dbError(err, "song vector")
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *SimilarityPostgres) GetRemovedSongs(ctx context.Context, songIds []int) ([]int, error) {
+	removed := []int{}
+	if len(songIds) == 0 {
+		return removed, nil
+	}
+	query := fmt.Sprintf(`
+		SELECT id FROM UNNEST($1::int[]) AS id
+		WHERE NOT EXISTS (SELECT 1 FROM %s v WHERE v.songId = id)`, songVectorsTable)
+	if err := r.db.SelectContext(ctx, &removed, query, pq.Array(songIds)); err != nil {
+		logrus.WithError(err).Error("Failed to fetch removed song vectors")
+		return nil, dbError(err, "song vector")
+	}
+	return removed, nil
+}
+
+// GetSongFeatures counts the genres of a song's group as genres of the song.
+func (r *SimilarityPostgres) GetSongFeatures(ctx context.Context, songIds []int) ([]musiclibrary.SongFeatures, error) {
+	var rows []struct {
+		songFeaturesRow
+		Genres pq.Int64Array `db:"genres"`
+	}
+	query := fmt.Sprintf(`
+		SELECT s.id AS songid, s.songName AS songname, s.groupId AS groupid, g.groupName AS groupname,
+			COALESCE(TO_CHAR(sd.releaseDate, 'YYYY-MM-DD'), '') AS releasedate,
+			ARRAY(
+				SELECT genreId FROM songGenres WHERE songId = s.id
+				UNION SELECT genreId FROM groupGenres WHERE groupId = s.groupId
+			) AS genres
+		FROM %s s
+		JOIN %s g ON g.id = s.groupId
+		LEFT JOIN %s sd ON sd.songId = s.id
+		WHERE s.id = ANY($1)`, songsTable, groupsTable, songDetailsTable)
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(songIds)); err != nil {
+		logrus.WithError(err).Error("Failed to fetch song features")
+		return nil, dbError(err, "song")
+	}
+	features := make([]musiclibrary.SongFeatures, 0, len(rows))
+	for _, row := range rows {
+		genres := make([]int, 0, len(row.Genres))
+		for _, id := range row.Genres {
+			genres = append(genres, int(id))
+		}
+		features = append(features, row.features(genres))
+	}
+	return features, nil
+}
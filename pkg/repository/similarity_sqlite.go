@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type SimilaritySQLite struct {
+	db *sqlx.DB
+}
+
+func NewSimilaritySQLite(db *sqlx.DB) *SimilaritySQLite {
+	return &SimilaritySQLite{db: db}
+}
+
+func (r *SimilaritySQLite) GetSongVectors(ctx context.Context) ([]musiclibrary.SongVector, error) {
+	var rows []songVectorRow
+	query := fmt.Sprintf("SELECT songid, terms FROM %s", songVectorsTable)
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch song vectors")
+		return nil, dbError(err, "song vector")
+	}
+	return songVectors(rows)
+}
+
+func (r *SimilaritySQLite) GetUnindexedSongs(ctx context.Context) ([]musiclibrary.SongLyrics, error) {
+	var songs []musiclibrary.SongLyrics
+	query := fmt.Sprintf(`
+		SELECT sd.songid, COALESCE(sd.text, '') AS text
+		FROM %s sd
+		WHERE NOT EXISTS (SELECT 1 FROM %s v WHERE v.songid = sd.songid)`, songDetailsTable, songVectorsTable)
+	if err := r.db.SelectContext(ctx, &songs, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch unindexed songs")
+		return nil, dbError(err, "song details")
+	}
+	return songs, nil
+}
+
+func (r *SimilaritySQLite) SaveSongVector(ctx context.Context, vector musiclibrary.SongVector, text string) (bool, error) {
+	terms, err := json.Marshal(vector.Terms)
+	if err != nil {
+		return false, err
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO %s (songid, terms)
+		SELECT songid, ?2 FROM %s WHERE songid = ?1 AND COALESCE(text, '') = ?3
+		ON CONFLICT (songid) DO UPDATE SET terms = excluded.terms`, songVectorsTable, songDetailsTable)
+	res, err := r.db.ExecContext(ctx, query, vector.SongId, string(terms), text)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to save song vector")
+		return false, dbError(err, "song vector")
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *SimilaritySQLite) GetRemovedSongs(ctx context.Context, songIds []int) ([]int, error) {
+	removed := []int{}
+	if len(songIds) == 0 {
+		return removed, nil
+	}
+	ids, err := json.Marshal(songIds)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT o.value FROM json_each(?) o
+		WHERE NOT EXISTS (SELECT 1 FROM %s v WHERE v.songid = o.value)`, songVectorsTable)
+	if err := r.db.SelectContext(ctx, &removed, query, string(ids)); err != nil {
+		logrus.WithError(err).Error("Failed to fetch removed song vectors")
+		return nil, dbError(err, "song vector")
+	}
+	return removed, nil
+}
+
+// GetSongFeatures leaves Genres empty, SQLite keeps no genres.
+func (r *SimilaritySQLite) GetSongFeatures(ctx context.Context, songIds []int) ([]musiclibrary.SongFeatures, error) {
+	ids, err := json.Marshal(songIds)
+	if err != nil {
+		return nil, err
+	}
+	var rows []songFeaturesRow
+	query := fmt.Sprintf(`
+		SELECT s.id AS songid, s.songname, s.groupid, g.groupname, COALESCE(sd.releasedate, '') AS releasedate
+		FROM %s s
+		JOIN %s g ON g.id = s.groupid
+		LEFT JOIN %s sd ON sd.songid = s.id
+		WHERE s.id IN (SELECT value FROM json_each(?))`, songsTable, groupsTable, songDetailsTable)
+	if err := r.db.SelectContext(ctx, &rows, query, string(ids)); err != nil {
+		logrus.WithError(err).Error("Failed to fetch song features")
+		return nil, dbError(err, "song")
+	}
+	features := make([]musiclibrary.SongFeatures, 0, len(rows))
+	for _, row := range rows {
+		features = append(features, row.features(nil))
+	}
+	return features, nil
+}
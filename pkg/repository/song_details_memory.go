@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	musiclibrary "time-tracker"
+)
+
+type SongDetailsMemory struct {
+	store *memoryStore
+}
+
+func (r *SongDetailsMemory) GetSongDetailsById(ctx context.Context, songId int) ([]musiclibrary.SongDetails, error) {
+	var details []musiclibrary.SongDetails
+	err := r.store.read(ctx, func() error {
+		if d, ok := r.store.details[songId]; ok {
+			details = append(details, d)
+		}
+		return nil
+	})
+	return details, err
+}
+
+// UpdateSongDetails applies the non-empty fields of input and records the
+// result as a new revision, the first edit also records the initial version.
+func (r *SongDetailsMemory) UpdateSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error {
+	if input.ReleaseDate == "" && input.Text == "" && input.Link == "" {
+		return r.store.read(ctx, func() error {
+			if _, ok := r.store.details[id]; !ok {
+				return notFound("song details")
+			}
+			return nil
+		})
+	}
+	if input.ReleaseDate != "" {
+		if _, err := time.Parse("2006-01-02", input.ReleaseDate); err != nil {
+			return &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: fmt.Sprintf("invalid song details: release date %q", input.ReleaseDate)}
+		}
+	}
+	return r.store.write(ctx, func() error {
+		details, ok := r.store.details[id]
+		if !ok {
+			return notFound("song details")
+		}
+		if len(r.store.revisions[id]) == 0 {
+			r.store.saveRevision(id, "", "Initial version")
+		}
+		if input.ReleaseDate != "" {
+			details.ReleaseDate = input.ReleaseDate
+		}
+		if input.Text != "" {
+			details.Text = input.Text
+			delete(r.store.vectors, id)
+		}
+		if input.Link != "" {
+			details.Link = input.Link
+		}
+		r.store.details[id] = details
+		r.store.saveRevision(id, input.Author, input.Comment)
+		return nil
+	})
+}
+
+// RestoreSongDetails sets all the details of a song to those of input,
+// clearing those that are empty, and records them as a new revision.
+func (r *SongDetailsMemory) RestoreSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error {
+	if input.ReleaseDate != "" {
+		if _, err := time.Parse("2006-01-02", input.ReleaseDate); err != nil {
+			return &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: fmt.Sprintf("invalid song details: release date %q", input.ReleaseDate)}
+		}
+	}
+	return r.store.write(ctx, func() error {
+		details, ok := r.store.details[id]
+		if !ok {
+			return notFound("song details")
+		}
+		if len(r.store.revisions[id]) == 0 {
+			r.store.saveRevision(id, "", "Initial version")
+		}
+		details.ReleaseDate, details.Text, details.Link = input.ReleaseDate, input.Text, input.Link
+		r.store.details[id] = details
+		delete(r.store.vectors, id)
+		r.store.saveRevision(id, input.Author, input.Comment)
+		return nil
+	})
+}
+
+func (r *SongDetailsMemory) GetSongText(ctx context.Context, songId int, page int, limit int) ([]string, error) {
+	text, err := r.GetLyrics(ctx, songId)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(strings.Split(text, "\n\n"), page, limit)
+}
+
+func (r *SongDetailsMemory) GetLyrics(ctx context.Context, songId int) (string, error) {
+	var text string
+	err := r.store.read(ctx, func() error {
+		details, ok := r.store.details[songId]
+		if !ok {
+			return notFound("song details")
+		}
+		text = details.Text
+		return nil
+	})
+	return text, err
+}
+
+func (r *SongDetailsMemory) GetLrc(ctx context.Context, songId int) (string, error) {
+	var lrc string
+	err := r.store.read(ctx, func() error {
+		var ok bool
+		if lrc, ok = r.store.lrc[songId]; !ok {
+			return notFound("LRC")
+		}
+		return nil
+	})
+	return lrc, err
+}
+
+func (r *SongDetailsMemory) SaveLrc(ctx context.Context, songId int, lrc string) error {
+	return r.store.write(ctx, func() error {
+		if _, ok := r.store.songs[songId]; !ok {
+			return musiclibrary.NewForeignKeyError("song %d does not exist", songId)
+		}
+		r.store.lrc[songId] = lrc
+		return nil
+	})
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	musiclibrary "time-tracker"
@@ -10,28 +11,61 @@ import (
 )
 
 type SongDetailPostgres struct {
-	db *sqlx.DB
+	db dbtx
 }
 
 func NewSongDetailsPostgres(db *sqlx.DB) *SongDetailPostgres {
 	return &SongDetailPostgres{db: db}
 }
 
-func (r *SongDetailPostgres) GetSongDetailsById(songId int) ([]musiclibrary.SongDetails, error) {
+func (r *SongDetailPostgres) GetSongDetailsById(ctx context.Context, songId int) ([]musiclibrary.SongDetails, error) {
 	logrus.WithField("songId", songId).Debug("Fetching song details by song ID")
 	var details []musiclibrary.SongDetails
-	query := fmt.Sprintf("SELECT id, songId  AS \"songid\", releaseDate AS \"releasedate\", text, link FROM %s WHERE songid = $1", songDetailsTable)
-	err := r.db.Select(&details, query, songId)
+	query := fmt.Sprintf(`SELECT id, songId AS songid, COALESCE(TO_CHAR(releaseDate, 'YYYY-MM-DD'), '') AS releasedate,
+		COALESCE(text, '') AS text, COALESCE(link, '') AS link FROM %s WHERE songid = $1`, songDetailsTable)
+	err := r.db.SelectContext(ctx, &details, query, songId)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to fetch song details by song ID")
-		return nil, err
+		return nil, dbError(err, "song details")
 	}
 	logrus.WithField("count", len(details)).Info("Fetched song details by song ID successfully")
 	return details, err
 }
 
-func (r *SongDetailPostgres) UpdateSongDetails(id int, input musiclibrary.UpdateSongDetailsInput) error {
+// RestoreSongDetails sets all the details of a song to those of input,
+// clearing those that are empty, and records them as a new revision.
+func (r *SongDetailPostgres) RestoreSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error {
+	logrus.WithField("id", id).Debug("Restoring song detail")
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		return restoreSongDetails(ctx, tx, id, input)
+	})
+	if err != nil {
+		return dbError(err, "song details")
+	}
+	logrus.WithField("id", id).Info("Song detail restored successfully")
+	return nil
+}
+
+func (r *SongDetailPostgres) UpdateSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error {
 	logrus.WithField("id", id).Debug("Updating song detail")
+	var updated bool
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		var err error
+		updated, err = updateSongDetails(ctx, tx, id, input)
+		return err
+	})
+	if err != nil {
+		return dbError(err, "song details")
+	}
+	if updated {
+		logrus.WithField("id", id).Info("Song detail updated successfully")
+	}
+	return nil
+}
+
+// updateSongDetails applies the non-empty fields of input to a song's details
+// and records the result as a new revision. It reports whether anything was set.
+func updateSongDetails(ctx context.Context, tx *sqlx.Tx, id int, input musiclibrary.UpdateSongDetailsInput) (bool, error) {
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
 	argId := 1
@@ -54,28 +88,82 @@ func (r *SongDetailPostgres) UpdateSongDetails(id int, input musiclibrary.Update
 		argId++
 	}
 
-	if argId > 1 {
-		setQuery := strings.Join(setValues, ", ")
-		query := fmt.Sprintf("UPDATE %s SET %s WHERE songid = $%d", songDetailsTable, setQuery, argId)
-		args = append(args, id)
-		_, err := r.db.Exec(query, args...)
-		if err != nil {
-			logrus.WithError(err).Error("Failed to update song detail")
+	if argId == 1 {
+		return false, exists(ctx, tx, fmt.Sprintf("SELECT 1 FROM %s WHERE songId = $1", songDetailsTable), id, "song details")
+	}
+	return true, editSongDetails(ctx, tx, id, strings.Join(setValues, ", "), args, input.Text != "", input.Author, input.Comment)
+}
+
+// restoreSongDetails sets all of a song's details to those of input, empty
+// ones included, and records the result as a new revision.
+func restoreSongDetails(ctx context.Context, tx *sqlx.Tx, id int, input musiclibrary.UpdateSongDetailsInput) error {
+	setQuery := "releasedate = NULLIF($1, '')::date, text = $2, link = $3"
+	args := []interface{}{input.ReleaseDate, input.Text, input.Link}
+	return editSongDetails(ctx, tx, id, setQuery, args, true, input.Author, input.Comment)
+}
+
+// editSongDetails applies setQuery, whose placeholders are numbered from 1,
+// to a song's details and records the result as a new revision.
+func editSongDetails(ctx context.Context, tx *sqlx.Tx, id int, setQuery string, args []interface{}, textChanged bool, author, comment string) error {
+	// The first edit of a song also records the state it started from,
+	// so every later revision can be diffed against the original.
+	var detailsId, revisions int
+	query := fmt.Sprintf("SELECT id FROM %s WHERE songId = $1 FOR UPDATE", songDetailsTable)
+	if err := tx.GetContext(ctx, &detailsId, query, id); err != nil {
+		logrus.WithError(err).Error("Failed to lock song detail")
+		return dbError(err, "song details")
+	}
+	query = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE songId = $1", revisionsTable)
+	if err := tx.GetContext(ctx, &revisions, query, id); err != nil {
+		logrus.WithError(err).Error("Failed to count song detail revisions")
+		return dbError(err, "song details")
+	}
+	if revisions == 0 {
+		if err := saveRevision(ctx, tx, id, "", "Initial version"); err != nil {
 			return err
 		}
-		logrus.WithField("id", id).Info("Song detail updated successfully")
 	}
-	return nil
+
+	query = fmt.Sprintf("UPDATE %s SET %s WHERE songid = $%d", songDetailsTable, setQuery, len(args)+1)
+	if _, err := tx.ExecContext(ctx, query, append(args, id)...); err != nil {
+		logrus.WithError(err).Error("Failed to update song detail")
+		return dbError(err, "song details")
+	}
+	// The similar songs indexer counts the terms of the new lyrics.
+	if textChanged {
+		query = fmt.Sprintf("DELETE FROM %s WHERE songId = $1", songVectorsTable)
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			logrus.WithError(err).Error("Failed to drop song vector")
+			return dbError(err, "song details")
+		}
+	}
+
+	return saveRevision(ctx, tx, id, author, comment)
 }
-func (r *SongDetailPostgres) GetSongText(songId int, page int, limit int) ([]string, error) {
+
+// saveRevision snapshots the current song details as the next revision.
+func saveRevision(ctx context.Context, tx *sqlx.Tx, songId int, author, comment string) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (songId, revision, releaseDate, text, link, author, comment)
+		SELECT sd.songId,
+			COALESCE((SELECT MAX(revision) FROM %[1]s WHERE songId = sd.songId), 0) + 1,
+			sd.releaseDate, sd.text, sd.link, $2, $3
+		FROM %[2]s sd WHERE sd.songId = $1`, revisionsTable, songDetailsTable)
+	_, err := tx.ExecContext(ctx, query, songId, author, comment)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to save song detail revision")
+	}
+	return err
+}
+func (r *SongDetailPostgres) GetSongText(ctx context.Context, songId int, page int, limit int) ([]string, error) {
 	logrus.WithField("songId", songId).Debug("Fetching song text by song ID")
 	var details musiclibrary.SongDetailsT
-	query := fmt.Sprintf("SELECT id, songId AS \"songid\", text FROM %s WHERE songid = $1", songDetailsTable)
+	query := fmt.Sprintf("SELECT id, songId AS songid, COALESCE(text, '') AS text FROM %s WHERE songid = $1", songDetailsTable)
 
-	err := r.db.Get(&details, query, songId)
+	err := r.db.GetContext(ctx, &details, query, songId)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to fetch song text by song ID")
-		return nil, err
+		return nil, dbError(err, "song details")
 	}
 
 	verses := strings.Split(details.Text, "\n\n")
@@ -101,3 +189,40 @@ func (r *SongDetailPostgres) GetSongText(songId int, page int, limit int) ([]str
 
 	return paginatedVerses, nil
 }
+
+func (r *SongDetailPostgres) GetLyrics(ctx context.Context, songId int) (string, error) {
+	logrus.WithField("songId", songId).Debug("Fetching lyrics by song ID")
+	var text string
+	query := fmt.Sprintf("SELECT COALESCE(text, '') FROM %s WHERE songid = $1", songDetailsTable)
+	err := r.db.GetContext(ctx, &text, query, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch lyrics by song ID")
+		return "", dbError(err, "song details")
+	}
+	return text, nil
+}
+
+func (r *SongDetailPostgres) GetLrc(ctx context.Context, songId int) (string, error) {
+	logrus.WithField("songId", songId).Debug("Fetching LRC by song ID")
+	var lrc string
+	query := fmt.Sprintf("SELECT lrc FROM %s WHERE songid = $1", songLrcTable)
+	err := r.db.GetContext(ctx, &lrc, query, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch LRC by song ID")
+		return "", dbError(err, "LRC")
+	}
+	return lrc, nil
+}
+
+func (r *SongDetailPostgres) SaveLrc(ctx context.Context, songId int, lrc string) error {
+	logrus.WithField("songId", songId).Debug("Saving LRC")
+	query := fmt.Sprintf(`INSERT INTO %s (songId, lrc) VALUES ($1, $2)
+		ON CONFLICT (songId) DO UPDATE SET lrc = EXCLUDED.lrc, updatedAt = now()`, songLrcTable)
+	_, err := r.db.ExecContext(ctx, query, songId, lrc)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to save LRC")
+		return dbError(err, "LRC")
+	}
+	logrus.WithField("songId", songId).Info("LRC saved successfully")
+	return nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type SongDetailsSQLite struct {
+	db dbtx
+}
+
+func NewSongDetailsSQLite(db *sqlx.DB) *SongDetailsSQLite {
+	return &SongDetailsSQLite{db: db}
+}
+
+func (r *SongDetailsSQLite) GetSongDetailsById(ctx context.Context, songId int) ([]musiclibrary.SongDetails, error) {
+	logrus.WithField("songId", songId).Debug("Fetching song details by song ID")
+	var details []musiclibrary.SongDetails
+	query := fmt.Sprintf(`SELECT id, songid, COALESCE(releasedate, '') AS releasedate,
+		COALESCE(text, '') AS text, COALESCE(link, '') AS link FROM %s WHERE songid = ?`, songDetailsTable)
+	if err := r.db.SelectContext(ctx, &details, query, songId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch song details by song ID")
+		return nil, dbError(err, "song details")
+	}
+	logrus.WithField("count", len(details)).Info("Fetched song details by song ID successfully")
+	return details, nil
+}
+
+// UpdateSongDetails applies the non-empty fields of input and records the
+// result as a new revision, the first edit also records the initial version.
+// Invalid release dates are rejected by a check constraint.
+func (r *SongDetailsSQLite) UpdateSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error {
+	logrus.WithField("id", id).Debug("Updating song detail")
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if input.ReleaseDate != "" {
+		setValues = append(setValues, "releasedate = ?")
+		args = append(args, input.ReleaseDate)
+	}
+	if input.Text != "" {
+		setValues = append(setValues, "text = ?")
+		args = append(args, input.Text)
+	}
+	if input.Link != "" {
+		setValues = append(setValues, "link = ?")
+		args = append(args, input.Link)
+	}
+	if len(setValues) == 0 {
+		return exists(ctx, r.db, fmt.Sprintf("SELECT 1 FROM %s WHERE songid = ?", songDetailsTable), id, "song details")
+	}
+
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		return editSongDetailsSQLite(ctx, tx, id, strings.Join(setValues, ", "), args, input.Text != "", input.Author, input.Comment)
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update song detail")
+		return dbError(err, "song details")
+	}
+	logrus.WithField("id", id).Info("Song detail updated successfully")
+	return nil
+}
+
+// RestoreSongDetails sets all the details of a song to those of input,
+// clearing those that are empty, and records them as a new revision.
+func (r *SongDetailsSQLite) RestoreSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error {
+	logrus.WithField("id", id).Debug("Restoring song detail")
+	setQuery := "releasedate = NULLIF(?, ''), text = ?, link = ?"
+	args := []interface{}{input.ReleaseDate, input.Text, input.Link}
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		return editSongDetailsSQLite(ctx, tx, id, setQuery, args, true, input.Author, input.Comment)
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to restore song detail")
+		return dbError(err, "song details")
+	}
+	logrus.WithField("id", id).Info("Song detail restored successfully")
+	return nil
+}
+
+// editSongDetailsSQLite applies setQuery to a song's details and records the
+// result as a new revision, the first edit also records the initial version.
+func editSongDetailsSQLite(ctx context.Context, tx *sqlx.Tx, id int, setQuery string, args []interface{}, textChanged bool, author, comment string) error {
+	// Transactions take the write lock when they begin, so nothing can
+	// change the details between the count and the update.
+	var detailsId, revisions int
+	query := fmt.Sprintf("SELECT id FROM %s WHERE songid = ?", songDetailsTable)
+	if err := tx.GetContext(ctx, &detailsId, query, id); err != nil {
+		return err
+	}
+	query = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE songid = ?", revisionsTable)
+	if err := tx.GetContext(ctx, &revisions, query, id); err != nil {
+		return err
+	}
+	if revisions == 0 {
+		if err := saveRevisionSQLite(ctx, tx, id, "", "Initial version"); err != nil {
+			return err
+		}
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET %s WHERE songid = ?", songDetailsTable, setQuery)
+	if _, err := tx.ExecContext(ctx, query, append(args, id)...); err != nil {
+		return err
+	}
+	if textChanged {
+		query = fmt.Sprintf("DELETE FROM %s WHERE songid = ?", songVectorsTable)
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+	return saveRevisionSQLite(ctx, tx, id, author, comment)
+}
+
+// saveRevisionSQLite snapshots the current song details as the next revision.
+func saveRevisionSQLite(ctx context.Context, tx *sqlx.Tx, songId int, author, comment string) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (songid, revision, releasedate, text, link, author, comment)
+		SELECT sd.songid,
+			COALESCE((SELECT MAX(revision) FROM %[1]s WHERE songid = sd.songid), 0) + 1,
+			sd.releasedate, sd.text, sd.link, ?2, ?3
+		FROM %[2]s sd WHERE sd.songid = ?1`, revisionsTable, songDetailsTable)
+	_, err := tx.ExecContext(ctx, query, songId, author, comment)
+	return err
+}
+
+func (r *SongDetailsSQLite) GetSongText(ctx context.Context, songId int, page int, limit int) ([]string, error) {
+	text, err := r.GetLyrics(ctx, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	verses := strings.Split(text, "\n\n")
+
+	start := (page - 1) * limit
+	end := start + limit
+
+	if start >= len(verses) {
+		return nil, nil
+	}
+
+	if end > len(verses) {
+		end = len(verses)
+	}
+
+	return verses[start:end], nil
+}
+
+func (r *SongDetailsSQLite) GetLyrics(ctx context.Context, songId int) (string, error) {
+	logrus.WithField("songId", songId).Debug("Fetching lyrics by song ID")
+	var text string
+	query := fmt.Sprintf("SELECT COALESCE(text, '') FROM %s WHERE songid = ?", songDetailsTable)
+	if err := r.db.GetContext(ctx, &text, query, songId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch lyrics by song ID")
+		return "", dbError(err, "song details")
+	}
+	return text, nil
+}
+
+func (r *SongDetailsSQLite) GetLrc(ctx context.Context, songId int) (string, error) {
+	logrus.WithField("songId", songId).Debug("Fetching LRC by song ID")
+	var lrc string
+	query := fmt.Sprintf("SELECT lrc FROM %s WHERE songid = ?", songLrcTable)
+	if err := r.db.GetContext(ctx, &lrc, query, songId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch LRC by song ID")
+		return "", dbError(err, "LRC")
+	}
+	return lrc, nil
+}
+
+func (r *SongDetailsSQLite) SaveLrc(ctx context.Context, songId int, lrc string) error {
+	logrus.WithField("songId", songId).Debug("Saving LRC")
+	query := fmt.Sprintf(`INSERT INTO %s (songid, lrc) VALUES (?, ?)
+		ON CONFLICT (songid) DO UPDATE SET lrc = excluded.lrc,
+		updatedat = strftime('%%Y-%%m-%%dT%%H:%%M:%%S', 'now')`, songLrcTable)
+	if _, err := r.db.ExecContext(ctx, query, songId, lrc); err != nil {
+		logrus.WithError(err).Error("Failed to save LRC")
+		return dbError(err, "LRC")
+	}
+	logrus.WithField("songId", songId).Info("LRC saved successfully")
+	return nil
+}
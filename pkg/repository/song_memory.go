@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	musiclibrary "time-tracker"
+)
+
+type SongMemory struct {
+	store *memoryStore
+}
+
+func (r *SongMemory) CreateSong(ctx context.Context, song musiclibrary.Song) (int, error) {
+	var id int
+	err := r.store.write(ctx, func() error {
+		if _, ok := r.store.groups[song.GroupId]; !ok {
+			return musiclibrary.NewForeignKeyError("group %d does not exist", song.GroupId)
+		}
+		r.store.lastSongId++
+		id = r.store.lastSongId
+		r.store.songs[id] = musiclibrary.Song{Id: id, SongName: song.SongName, GroupId: song.GroupId}
+		r.store.lastDetailsId++
+		r.store.details[id] = musiclibrary.SongDetails{Id: r.store.lastDetailsId, SongId: id, Text: "N/A", Link: "N/A"}
+		return nil
+	})
+	return id, err
+}
+
+func (r *SongMemory) GetAllSongs(ctx context.Context) ([]musiclibrary.Song, error) {
+	var songs []musiclibrary.Song
+	err := r.store.read(ctx, func() error {
+		songs = r.store.sortedSongs()
+		return nil
+	})
+	return songs, err
+}
+
+func (r *SongMemory) GetSongById(ctx context.Context, id int) (musiclibrary.Song, error) {
+	var song musiclibrary.Song
+	err := r.store.read(ctx, func() error {
+		var ok bool
+		if song, ok = r.store.songs[id]; !ok {
+			return notFound("song")
+		}
+		return nil
+	})
+	return song, err
+}
+
+func (r *SongMemory) GetSongsByGroupIds(ctx context.Context, groupIds []int) ([]musiclibrary.Song, error) {
+	var songs []musiclibrary.Song
+	err := r.store.read(ctx, func() error {
+		wanted := make(map[int]bool, len(groupIds))
+		for _, id := range groupIds {
+			wanted[id] = true
+		}
+		for _, song := range r.store.sortedSongs() {
+			if wanted[song.GroupId] {
+				songs = append(songs, song)
+			}
+		}
+		return nil
+	})
+	return songs, err
+}
+
+func (r *SongMemory) DeleteSong(ctx context.Context, id int) error {
+	return r.store.write(ctx, func() error {
+		if _, ok := r.store.songs[id]; !ok {
+			return notFound("song")
+		}
+		r.store.deleteSong(id)
+		return nil
+	})
+}
+
+func (r *SongMemory) UpdateSong(ctx context.Context, id int, input musiclibrary.UpdateSongInput) error {
+	return r.store.write(ctx, func() error {
+		if input.GroupId != nil {
+			if _, ok := r.store.groups[*input.GroupId]; !ok {
+				return musiclibrary.NewForeignKeyError("group %d does not exist", *input.GroupId)
+			}
+		}
+		song, ok := r.store.songs[id]
+		if !ok {
+			return notFound("song")
+		}
+		if input.SongName == nil && input.GroupId == nil {
+			return nil
+		}
+		if input.SongName != nil {
+			song.SongName = *input.SongName
+		}
+		if input.GroupId != nil {
+			song.GroupId = *input.GroupId
+		}
+		r.store.songs[id] = song
+		return nil
+	})
+}
+
+func (r *SongMemory) GetSongsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Song], error) {
+	var result musiclibrary.Page[musiclibrary.Song]
+	favoriteOf, err := favoritesOf(filters)
+	if err != nil {
+		return result, err
+	}
+	byRating := filters["sort"] == sortRating
+	err = r.store.read(ctx, func() error {
+		var songs []musiclibrary.Song
+		fuzzy := filters["match"] == matchFuzzy
+		for _, song := range r.store.sortedSongs() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			details := r.store.details[song.Id]
+			group := r.store.groups[song.GroupId]
+
+			// Albums, genres and tags are not kept in memory, so no song has any.
+			if filters["albumtitle"] != "" || filters["genre"] != "" || filters["tag"] != "" {
+				continue
+			}
+			if _, ok := r.store.favorites[memoryUserSong{favoriteOf, song.Id}]; favoriteOf != 0 && !ok {
+				continue
+			}
+			if !contains(details.ReleaseDate, filters["releasedate"]) ||
+				!contains(details.Link, filters["link"]) ||
+				!contains(details.Text, filters["text"]) {
+				continue
+			}
+
+			var scores []float64
+			matched := true
+			for _, f := range []struct{ value, term string }{
+				{song.SongName, filters["songname"]},
+				{group.GroupName, filters["groupname"]},
+			} {
+				if f.term == "" {
+					continue
+				}
+				if !fuzzy {
+					matched = matched && contains(f.value, f.term)
+					continue
+				}
+				ok, score := trigramMatch(f.value, f.term)
+				matched = matched && ok
+				scores = append(scores, score)
+			}
+			if !matched {
+				continue
+			}
+
+			if len(scores) > 0 {
+				// With both a song and a group name the score is the mean of the two.
+				var score float64
+				for _, s := range scores {
+					score += s
+				}
+				score /= float64(len(scores))
+				song.Score = &score
+			}
+			if byRating {
+				rating := r.store.averageRating(song.Id)
+				song.Rating = &rating
+			}
+			songs = append(songs, song)
+		}
+		scored := fuzzy && (filters["songname"] != "" || filters["groupname"] != "")
+		keys := songKeys(filters, scored)
+		sort.SliceStable(songs, func(i, j int) bool {
+			return compareKeys(songKey(songs[i]), songKey(songs[j]), keys) < 0
+		})
+		var err error
+		result, err = pageSlice(songs, keys, page, songKey)
+		return err
+	})
+	return result, err
+}
+
+func (s *memoryStore) sortedSongs() []musiclibrary.Song {
+	songs := make([]musiclibrary.Song, 0, len(s.songs))
+	for _, song := range s.songs {
+		songs = append(songs, song)
+	}
+	sort.Slice(songs, func(i, j int) bool {
+		return songs[i].Id < songs[j].Id
+	})
+	return songs
+}
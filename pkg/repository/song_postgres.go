@@ -1,63 +1,97 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	musiclibrary "time-tracker"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
 type SongPostgres struct {
-	db *sqlx.DB
+	db dbtx
 }
 
 func NewSongPostgres(db *sqlx.DB) *SongPostgres {
 	return &SongPostgres{db: db}
 }
 
-func (r *SongPostgres) CreateSong(song musiclibrary.Song) (int, error) {
+func (r *SongPostgres) CreateSong(ctx context.Context, song musiclibrary.Song) (int, error) {
 	logrus.Debug("Creating song")
 	var id int
-	query := fmt.Sprintf("INSERT INTO %s (songName, groupId) VALUES ($1, $2) RETURNING id", songsTable)
-	row := r.db.QueryRow(query, song.SongName, song.GroupId)
-	if err := row.Scan(&id); err != nil {
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		query := fmt.Sprintf("INSERT INTO %s (songName, groupId) VALUES ($1, $2) RETURNING id", songsTable)
+		if err := tx.GetContext(ctx, &id, query, song.SongName, song.GroupId); err != nil {
+			return err
+		}
+		query = fmt.Sprintf("INSERT INTO %s (songId) VALUES ($1)", songDetailsTable)
+		_, err := tx.ExecContext(ctx, query, id)
+		return err
+	})
+	if err != nil {
 		logrus.WithError(err).Error("Failed to create song")
-		return 0, err
+		return 0, dbError(err, "song")
 	}
-	query = fmt.Sprintf("INSERT INTO %s (songId) VALUES ($1)", songDetailsTable)
-	r.db.QueryRow(query, id)
 	logrus.WithField("id", id).Info("Song created successfully")
 	return id, nil
 }
 
-func (r *SongPostgres) GetAllSongs() ([]musiclibrary.Song, error) {
+func (r *SongPostgres) GetAllSongs(ctx context.Context) ([]musiclibrary.Song, error) {
 	logrus.Debug("Fetching all songs")
 	var songList []musiclibrary.Song
 	query := fmt.Sprintf("SELECT * FROM %s", songsTable)
-	err := r.db.Select(&songList, query)
+	err := r.db.SelectContext(ctx, &songList, query)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to fetch all songs")
-		return nil, err
+		return nil, dbError(err, "song")
 	}
 	logrus.WithField("count", len(songList)).Info("Fetched all songs successfully")
 	return songList, err
 }
 
-func (r *SongPostgres) DeleteSong(id int) error {
+func (r *SongPostgres) GetSongById(ctx context.Context, id int) (musiclibrary.Song, error) {
+	var song musiclibrary.Song
+	query := fmt.Sprintf("SELECT id, songName AS songname, groupId AS groupid FROM %s WHERE id = $1", songsTable)
+	err := r.db.GetContext(ctx, &song, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch song")
+		return song, dbError(err, "song")
+	}
+	return song, nil
+}
+
+// GetSongsByGroupIds returns the songs of all the given groups with a single
+// query, ordered by id.
+func (r *SongPostgres) GetSongsByGroupIds(ctx context.Context, groupIds []int) ([]musiclibrary.Song, error) {
+	logrus.WithField("groupIds", groupIds).Debug("Fetching songs by group IDs")
+	var songs []musiclibrary.Song
+	query := fmt.Sprintf("SELECT id, songName AS songname, groupId AS groupid FROM %s WHERE groupId = ANY($1) ORDER BY id", songsTable)
+	if err := r.db.SelectContext(ctx, &songs, query, pq.Array(groupIds)); err != nil {
+		logrus.WithError(err).Error("Failed to fetch songs by group IDs")
+		return nil, dbError(err, "song")
+	}
+	return songs, nil
+}
+
+func (r *SongPostgres) DeleteSong(ctx context.Context, id int) error {
 	logrus.WithField("id", id).Debug("Deleting song")
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", songsTable)
-	_, err := r.db.Exec(query, id)
+	res, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to delete song")
+		return dbError(err, "song")
+	}
+	if err := affected(res, "song"); err != nil {
 		return err
 	}
 	logrus.WithField("id", id).Info("Song deleted successfully")
 	return nil
 }
 
-func (r *SongPostgres) UpdateSong(id int, input musiclibrary.UpdateSongInput) error {
+func (r *SongPostgres) UpdateSong(ctx context.Context, id int, input musiclibrary.UpdateSongInput) error {
 	logrus.WithField("id", id).Debug("Updating song")
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
@@ -78,34 +112,39 @@ func (r *SongPostgres) UpdateSong(id int, input musiclibrary.UpdateSongInput) er
 		setQuery := strings.Join(setValues, ", ")
 		query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", songsTable, setQuery, argId)
 		args = append(args, id)
-		_, err := r.db.Exec(query, args...)
+		res, err := r.db.ExecContext(ctx, query, args...)
 		if err != nil {
 			logrus.WithError(err).Error("Failed to update song")
+			return dbError(err, "song")
+		}
+		if err := affected(res, "song"); err != nil {
 			return err
 		}
 		logrus.WithField("id", id).Info("Song updated successfully")
+		return nil
 	}
 
-	return nil
+	return exists(ctx, r.db, fmt.Sprintf("SELECT 1 FROM %s WHERE id = $1", songsTable), id, "song")
 }
 
-func (r *SongPostgres) GetSongsWithFilter(filters map[string]string, page, limit int) ([]musiclibrary.Song, error) {
-	var songs []musiclibrary.Song
+func (r *SongPostgres) GetSongsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Song], error) {
 	var conditions []string
 	var args []interface{}
 	argId := 1
 
-	query := `
-		SELECT s.* 
-		FROM songs s 
-		JOIN songDetails sd ON s.id = sd.songId 
-		JOIN groupss g ON s.groupId = g.id 
-		WHERE 1=1
-	`
+	var scores []string
+	fuzzy := filters["match"] == matchFuzzy
 
 	if song, ok := filters["songname"]; ok && song != "" {
-		conditions = append(conditions, fmt.Sprintf("s.songname ILIKE $%d", argId))
-		args = append(args, "%"+song+"%")
+		if fuzzy {
+			condition, score := fuzzyMatch("s.songname", argId)
+			conditions = append(conditions, condition)
+			scores = append(scores, score)
+			args = append(args, song)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("s.songname ILIKE $%d", argId))
+			args = append(args, "%"+song+"%")
+		}
 		argId++
 	}
 
@@ -128,22 +167,73 @@ func (r *SongPostgres) GetSongsWithFilter(filters map[string]string, page, limit
 	}
 
 	if groupName, ok := filters["groupname"]; ok && groupName != "" {
-		conditions = append(conditions, fmt.Sprintf("g.groupname ILIKE $%d", argId))
-		args = append(args, "%"+groupName+"%")
+		if fuzzy {
+			condition, score := fuzzyMatch("g.groupname", argId)
+			conditions = append(conditions, condition)
+			scores = append(scores, score)
+			args = append(args, groupName)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("g.groupname ILIKE $%d", argId))
+			args = append(args, "%"+groupName+"%")
+		}
 		argId++
 	}
 
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
+	if albumTitle, ok := filters["albumtitle"]; ok && albumTitle != "" {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM albumTracks t JOIN albums a ON a.id = t.albumId
+			WHERE t.songId = s.id AND a.title ILIKE $%d)`, argId))
+		args = append(args, "%"+albumTitle+"%")
+		argId++
+	}
+
+	if genre, ok := filters["genre"]; ok && genre != "" {
+		subtree := genreSubtree(argId)
+		conditions = append(conditions, fmt.Sprintf(`(
+			s.id IN (SELECT songId FROM songGenres WHERE genreId IN (%s))
+			OR s.groupId IN (SELECT groupId FROM groupGenres WHERE genreId IN (%s)))`, subtree, subtree))
+		args = append(args, genre)
+		argId++
 	}
 
-	query += fmt.Sprintf(" ORDER BY s.id LIMIT $%d OFFSET $%d", argId, argId+1)
-	args = append(args, limit, (page-1)*limit)
+	if tag, ok := filters["tag"]; ok && tag != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"s.id IN (SELECT st.songId FROM songTags st JOIN tags t ON t.id = st.tagId WHERE t.name = LOWER($%d))", argId))
+		args = append(args, tag)
+		argId++
+	}
 
-	err := r.db.Select(&songs, query, args...)
+	userId, err := favoritesOf(filters)
 	if err != nil {
-		return nil, err
+		return musiclibrary.Page[musiclibrary.Song]{}, err
+	}
+	if userId != 0 {
+		conditions = append(conditions, fmt.Sprintf("s.id IN (SELECT songId FROM favorites WHERE userId = $%d)", argId))
+		args = append(args, userId)
+		argId++
 	}
 
-	return songs, nil
+	columns := "s.*"
+	if len(scores) > 0 {
+		// With both a song and a group name the score is the mean of the two.
+		columns = fmt.Sprintf("s.*, (%s) / %d AS score", strings.Join(scores, " + "), len(scores))
+	}
+	if filters["sort"] == sortRating {
+		// Songs nobody rated come last with a rating of 0.
+		columns += ", COALESCE((SELECT AVG(r.rating) FROM songRatings r WHERE r.songId = s.id), 0)::float8 AS rating"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM songs s
+		JOIN songDetails sd ON s.id = sd.songId
+		JOIN groupss g ON s.groupId = g.id
+		WHERE 1=1
+	`, columns)
+
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	return selectPage(ctx, r.db, query, args, songKeys(filters, len(scores) > 0), page, pgPlaceholder, songKey)
 }
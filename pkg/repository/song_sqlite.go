@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type SongSQLite struct {
+	db dbtx
+}
+
+func NewSongSQLite(db *sqlx.DB) *SongSQLite {
+	return &SongSQLite{db: db}
+}
+
+func (r *SongSQLite) CreateSong(ctx context.Context, song musiclibrary.Song) (int, error) {
+	logrus.Debug("Creating song")
+	var id int
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		query := fmt.Sprintf("INSERT INTO %s (songname, groupid) VALUES (?, ?) RETURNING id", songsTable)
+		if err := tx.GetContext(ctx, &id, query, song.SongName, song.GroupId); err != nil {
+			return err
+		}
+		query = fmt.Sprintf("INSERT INTO %s (songid) VALUES (?)", songDetailsTable)
+		_, err := tx.ExecContext(ctx, query, id)
+		return err
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create song")
+		return 0, dbError(err, "song")
+	}
+	logrus.WithField("id", id).Info("Song created successfully")
+	return id, nil
+}
+
+func (r *SongSQLite) GetAllSongs(ctx context.Context) ([]musiclibrary.Song, error) {
+	logrus.Debug("Fetching all songs")
+	var songList []musiclibrary.Song
+	query := fmt.Sprintf("SELECT id, songname, groupid FROM %s ORDER BY id", songsTable)
+	if err := r.db.SelectContext(ctx, &songList, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch all songs")
+		return nil, dbError(err, "song")
+	}
+	logrus.WithField("count", len(songList)).Info("Fetched all songs successfully")
+	return songList, nil
+}
+
+func (r *SongSQLite) GetSongById(ctx context.Context, id int) (musiclibrary.Song, error) {
+	var song musiclibrary.Song
+	query := fmt.Sprintf("SELECT id, songname, groupid FROM %s WHERE id = ?", songsTable)
+	if err := r.db.GetContext(ctx, &song, query, id); err != nil {
+		logrus.WithError(err).Error("Failed to fetch song")
+		return song, dbError(err, "song")
+	}
+	return song, nil
+}
+
+// GetSongsByGroupIds returns the songs of all the given groups with a single
+// query, ordered by id.
+func (r *SongSQLite) GetSongsByGroupIds(ctx context.Context, groupIds []int) ([]musiclibrary.Song, error) {
+	logrus.WithField("groupIds", groupIds).Debug("Fetching songs by group IDs")
+	if len(groupIds) == 0 {
+		return nil, nil
+	}
+	var songs []musiclibrary.Song
+	query, args, err := sqlx.In(fmt.Sprintf("SELECT id, songname, groupid FROM %s WHERE groupid IN (?) ORDER BY id", songsTable), groupIds)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.SelectContext(ctx, &songs, query, args...); err != nil {
+		logrus.WithError(err).Error("Failed to fetch songs by group IDs")
+		return nil, dbError(err, "song")
+	}
+	return songs, nil
+}
+
+func (r *SongSQLite) DeleteSong(ctx context.Context, id int) error {
+	logrus.WithField("id", id).Debug("Deleting song")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", songsTable)
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete song")
+		return dbError(err, "song")
+	}
+	if err := affected(res, "song"); err != nil {
+		return err
+	}
+	logrus.WithField("id", id).Info("Song deleted successfully")
+	return nil
+}
+
+func (r *SongSQLite) UpdateSong(ctx context.Context, id int, input musiclibrary.UpdateSongInput) error {
+	logrus.WithField("id", id).Debug("Updating song")
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if input.SongName != nil {
+		setValues = append(setValues, "songname = ?")
+		args = append(args, *input.SongName)
+	}
+	if input.GroupId != nil {
+		setValues = append(setValues, "groupid = ?")
+		args = append(args, *input.GroupId)
+	}
+
+	if len(setValues) > 0 {
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE id = ?", songsTable, strings.Join(setValues, ", "))
+		args = append(args, id)
+		res, err := r.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to update song")
+			return dbError(err, "song")
+		}
+		if err := affected(res, "song"); err != nil {
+			return err
+		}
+		logrus.WithField("id", id).Info("Song updated successfully")
+		return nil
+	}
+
+	return exists(ctx, r.db, fmt.Sprintf("SELECT 1 FROM %s WHERE id = ?", songsTable), id, "song")
+}
+
+func (r *SongSQLite) GetSongsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Song], error) {
+	var conditions []string
+	var args []interface{}
+	argId := 1
+
+	var scores []string
+	fuzzy := filters["match"] == matchFuzzy
+
+	if song, ok := filters["songname"]; ok && song != "" {
+		if fuzzy {
+			condition, score := sqliteFuzzyMatch("s.songname", argId)
+			conditions = append(conditions, condition)
+			scores = append(scores, score)
+			args = append(args, song)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("s.songname LIKE ?%d", argId))
+			args = append(args, "%"+song+"%")
+		}
+		argId++
+	}
+
+	for _, f := range []struct{ filter, column string }{
+		{"releasedate", "sd.releasedate"},
+		{"link", "sd.link"},
+		{"text", "sd.text"},
+	} {
+		if value := filters[f.filter]; value != "" {
+			conditions = append(conditions, fmt.Sprintf("%s LIKE ?%d", f.column, argId))
+			args = append(args, "%"+value+"%")
+			argId++
+		}
+	}
+
+	if groupName, ok := filters["groupname"]; ok && groupName != "" {
+		if fuzzy {
+			condition, score := sqliteFuzzyMatch("g.groupname", argId)
+			conditions = append(conditions, condition)
+			scores = append(scores, score)
+			args = append(args, groupName)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("g.groupname LIKE ?%d", argId))
+			args = append(args, "%"+groupName+"%")
+		}
+		argId++
+	}
+
+	// Albums, genres and tags are not stored in SQLite, so no song has any.
+	if filters["albumtitle"] != "" || filters["genre"] != "" || filters["tag"] != "" {
+		conditions = append(conditions, "0")
+	}
+
+	userId, err := favoritesOf(filters)
+	if err != nil {
+		return musiclibrary.Page[musiclibrary.Song]{}, err
+	}
+	if userId != 0 {
+		conditions = append(conditions, fmt.Sprintf("s.id IN (SELECT songid FROM favorites WHERE userid = ?%d)", argId))
+		args = append(args, userId)
+		argId++
+	}
+
+	columns := "s.id, s.songname, s.groupid"
+	if len(scores) > 0 {
+		// With both a song and a group name the score is the mean of the two.
+		columns = fmt.Sprintf("%s, (%s) / %d.0 AS score", columns, strings.Join(scores, " + "), len(scores))
+	}
+	if filters["sort"] == sortRating {
+		// Songs nobody rated come last with a rating of 0.
+		columns += ", COALESCE((SELECT AVG(r.rating) FROM songratings r WHERE r.songid = s.id), 0.0) AS rating"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM songs s
+		JOIN songdetails sd ON s.id = sd.songid
+		JOIN groupss g ON s.groupid = g.id
+		WHERE 1=1
+	`, columns)
+
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	return selectPage(ctx, r.db, query, args, songKeys(filters, len(scores) > 0), page, sqlitePlaceholder, songKey)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+// sqliteDriver is go-sqlite3 with the functions the SQLite repositories rely
+// on: similarity and word_similarity from pg_trgm, and a LIKE that folds case
+// beyond ASCII and treats backslash as the escape character like ILIKE does.
+const sqliteDriver = "sqlite3_musiclibrary"
+
+func init() {
+	sql.Register(sqliteDriver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := conn.RegisterFunc("similarity", trigramSimilarity, true); err != nil {
+				return err
+			}
+			if err := conn.RegisterFunc("word_similarity", trigramWordSimilarity, true); err != nil {
+				return err
+			}
+			return conn.RegisterFunc("like", sqliteLike, true)
+		},
+	})
+}
+
+// sqliteLike implements "value LIKE pattern", which SQLite calls as
+// like(pattern, value). The call cannot be interrupted once it runs, so it
+// leans on ilike taking at most O(len(value) * len(pattern)) time, whatever
+// the pattern users filter by.
+func sqliteLike(pattern, value any) any {
+	if pattern == nil || value == nil {
+		return nil
+	}
+	return ilike(fmt.Sprint(value), fmt.Sprint(pattern))
+}
+
+// NewSQLiteDB opens the SQLite database at path, creating it if needed.
+// Foreign keys are switched on so deletes cascade, and transactions take the
+// write lock up front so concurrent ones wait instead of failing.
+func NewSQLiteDB(path string) (*sqlx.DB, error) {
+	logrus.WithField("path", path).Info("Opening SQLite database")
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate", path)
+	db, err := sqlx.Open(sqliteDriver, dsn)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to open SQLite database")
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		logrus.WithError(err).Error("Failed to ping SQLite database")
+		return nil, err
+	}
+	logrus.Info("SQLite database opened successfully")
+	return db, nil
+}
+
+// NewSQLiteRepository returns repositories backed by a SQLite database
+// migrated with migrations/sqlite. Groups, songs, song details, their
+// revisions, users, their favorites, ratings, playlists, plays and the
+// term counts of lyrics are supported, the remaining repositories return
+// ErrNotSupported.
+func NewSQLiteRepository(db *sqlx.DB) *Repository {
+	return &Repository{
+		Group:         NewGroupSQLite(db),
+		Authorisation: NewSongSQLite(db),
+		SongDetails:   NewSongDetailsSQLite(db),
+		Album:         unsupported{},
+		Artist:        unsupported{},
+		Membership:    unsupported{},
+		Genre:         unsupported{},
+		Tag:           unsupported{},
+		Search:        unsupported{},
+		Translation:   unsupported{},
+		Revision:      NewRevisionSQLite(db),
+		Import:        unsupported{},
+		Export:        unsupported{},
+		Job:           unsupported{},
+		User:          NewUserSQLite(db),
+		Library:       NewLibrarySQLite(db),
+		Playlist:      NewPlaylistSQLite(db),
+		Play:          NewPlaySQLite(db),
+		Similarity:    NewSimilaritySQLite(db),
+		UnitOfWork:    NewUnitOfWorkSQLite(db),
+	}
+}
+
+// sqliteFuzzyMatch is fuzzyMatch for SQLite, which has no pg_trgm operators,
+// so the thresholds are spelled out.
+func sqliteFuzzyMatch(column string, argId int) (string, string) {
+	condition := fmt.Sprintf("(similarity(%[1]s, ?%[2]d) >= %[3]g OR word_similarity(?%[2]d, %[1]s) >= %[4]g)",
+		column, argId, similarityThreshold, wordSimilarityThreshold)
+	score := fmt.Sprintf("MAX(similarity(%[1]s, ?%[2]d), word_similarity(?%[2]d, %[1]s))", column, argId)
+	return condition, score
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+var tagLinks = map[musiclibrary.Taggable]linkTable{
+	musiclibrary.TaggableSong:  {table: "songTags", column: "songId"},
+	musiclibrary.TaggableGroup: {table: "groupTags", column: "groupId"},
+}
+
+type TagPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTagPostgres(db *sqlx.DB) *TagPostgres {
+	return &TagPostgres{db: db}
+}
+
+func (r *TagPostgres) GetAllTags(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Tag], error) {
+	logrus.Debug("Fetching all tags")
+	query := fmt.Sprintf("SELECT id, name FROM %s", tagsTable)
+	tagList, err := selectPage(ctx, r.db, query, nil, sortByName, page, pgPlaceholder, func(t musiclibrary.Tag) []any {
+		return []any{t.Name, t.Id}
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch all tags")
+		return musiclibrary.Page[musiclibrary.Tag]{}, dbError(err, "tag")
+	}
+	logrus.WithField("count", len(tagList.Items)).Info("Fetched all tags successfully")
+	return tagList, nil
+}
+
+func (r *TagPostgres) GetTags(ctx context.Context, target musiclibrary.Taggable, targetId int) ([]musiclibrary.Tag, error) {
+	link, ok := tagLinks[target]
+	if !ok {
+		return nil, fmt.Errorf("unknown taggable %q", target)
+	}
+	var tags []musiclibrary.Tag
+	query := fmt.Sprintf(`SELECT t.id, t.name FROM %s t
+		JOIN %s l ON l.tagId = t.id WHERE l.%s = $1 ORDER BY t.name`, tagsTable, link.table, link.column)
+	err := r.db.SelectContext(ctx, &tags, query, targetId)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to fetch %s tags", target)
+		return nil, dbError(err, "tag")
+	}
+	return tags, nil
+}
+
+// AttachTag links a free-form tag to the target, creating the tag on first use.
+// Tag names are stored lower-cased so "Live" and "live" are the same tag.
+func (r *TagPostgres) AttachTag(ctx context.Context, target musiclibrary.Taggable, targetId int, name string) error {
+	link, ok := tagLinks[target]
+	if !ok {
+		return fmt.Errorf("unknown taggable %q", target)
+	}
+	query := fmt.Sprintf(`
+		WITH tag AS (
+			INSERT INTO %s (name) VALUES ($2)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		)
+		INSERT INTO %s (%s, tagId) SELECT $1, id FROM tag ON CONFLICT DO NOTHING`,
+		tagsTable, link.table, link.column)
+	_, err := r.db.ExecContext(ctx, query, targetId, strings.ToLower(name))
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to attach tag to %s", target)
+		return dbError(err, "tag")
+	}
+	logrus.WithFields(logrus.Fields{
+		string(target): targetId,
+		"tag":          name,
+	}).Info("Tag attached successfully")
+	return nil
+}
+
+func (r *TagPostgres) DetachTag(ctx context.Context, target musiclibrary.Taggable, targetId int, name string) error {
+	link, ok := tagLinks[target]
+	if !ok {
+		return fmt.Errorf("unknown taggable %q", target)
+	}
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1
+		AND tagId = (SELECT id FROM %s WHERE name = $2)`, link.table, link.column, tagsTable)
+	res, err := r.db.ExecContext(ctx, query, targetId, strings.ToLower(name))
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to detach tag from %s", target)
+		return dbError(err, "tag")
+	}
+	if err := affected(res, "tag"); err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		string(target): targetId,
+		"tag":          name,
+	}).Info("Tag detached successfully")
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const translationColumns = `id, songId AS songid, language, isOriginal AS isoriginal, translator, text`
+
+type TranslationPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTranslationPostgres(db *sqlx.DB) *TranslationPostgres {
+	return &TranslationPostgres{db: db}
+}
+
+func (r *TranslationPostgres) GetTranslations(ctx context.Context, songId int) ([]musiclibrary.Translation, error) {
+	logrus.WithField("songId", songId).Debug("Fetching song translations")
+	var translations []musiclibrary.Translation
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE songId = $1 ORDER BY isOriginal DESC, language", translationColumns, translationsTable)
+	err := r.db.SelectContext(ctx, &translations, query, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch song translations")
+		return nil, dbError(err, "translation")
+	}
+	logrus.WithField("count", len(translations)).Info("Fetched song translations successfully")
+	return translations, nil
+}
+
+func (r *TranslationPostgres) GetTranslation(ctx context.Context, songId int, language string) (musiclibrary.Translation, error) {
+	var translation musiclibrary.Translation
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE songId = $1 AND language = $2", translationColumns, translationsTable)
+	err := r.db.GetContext(ctx, &translation, query, songId, language)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch song translation")
+		return translation, dbError(err, "translation")
+	}
+	return translation, nil
+}
+
+func (r *TranslationPostgres) GetOriginal(ctx context.Context, songId int) (musiclibrary.Translation, error) {
+	var translation musiclibrary.Translation
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE songId = $1 AND isOriginal", translationColumns, translationsTable)
+	err := r.db.GetContext(ctx, &translation, query, songId)
+	return translation, err
+}
+
+func (r *TranslationPostgres) CreateTranslation(ctx context.Context, songId int, translation musiclibrary.Translation) (int, error) {
+	logrus.WithFields(logrus.Fields{
+		"songId":   songId,
+		"language": translation.Language,
+	}).Debug("Creating song translation")
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if translation.IsOriginal {
+		if err := clearOriginal(ctx, tx, songId); err != nil {
+			return 0, err
+		}
+	}
+
+	var id int
+	query := fmt.Sprintf(`INSERT INTO %s (songId, language, isOriginal, translator, text)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`, translationsTable)
+	row := tx.QueryRowContext(ctx, query, songId, translation.Language, translation.IsOriginal, translation.Translator, translation.Text)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to create song translation")
+		return 0, dbError(err, "translation")
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	logrus.WithField("id", id).Info("Song translation created successfully")
+	return id, nil
+}
+
+func (r *TranslationPostgres) UpdateTranslation(ctx context.Context, songId int, language string, input musiclibrary.UpdateTranslationInput) error {
+	logrus.WithFields(logrus.Fields{
+		"songId":   songId,
+		"language": language,
+	}).Debug("Updating song translation")
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.IsOriginal != nil {
+		setValues = append(setValues, fmt.Sprintf("isOriginal=$%d", argId))
+		args = append(args, *input.IsOriginal)
+		argId++
+	}
+	if input.Translator != nil {
+		setValues = append(setValues, fmt.Sprintf("translator=$%d", argId))
+		args = append(args, *input.Translator)
+		argId++
+	}
+	if input.Text != nil {
+		setValues = append(setValues, fmt.Sprintf("text=$%d", argId))
+		args = append(args, *input.Text)
+		argId++
+	}
+
+	if argId == 1 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if input.IsOriginal != nil && *input.IsOriginal {
+		if err := clearOriginal(ctx, tx, songId); err != nil {
+			return err
+		}
+	}
+
+	setQuery := strings.Join(setValues, ", ")
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE songId = $%d AND language = $%d", translationsTable, setQuery, argId, argId+1)
+	args = append(args, songId, language)
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update song translation")
+		return dbError(err, "translation")
+	}
+	if err := affected(res, "translation"); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	logrus.WithField("songId", songId).Info("Song translation updated successfully")
+	return nil
+}
+
+func (r *TranslationPostgres) DeleteTranslation(ctx context.Context, songId int, language string) error {
+	logrus.WithFields(logrus.Fields{
+		"songId":   songId,
+		"language": language,
+	}).Debug("Deleting song translation")
+	query := fmt.Sprintf("DELETE FROM %s WHERE songId = $1 AND language = $2", translationsTable)
+	res, err := r.db.ExecContext(ctx, query, songId, language)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete song translation")
+		return dbError(err, "translation")
+	}
+	if err := affected(res, "translation"); err != nil {
+		return err
+	}
+	logrus.WithField("songId", songId).Info("Song translation deleted successfully")
+	return nil
+}
+
+// clearOriginal unmarks the current original text of a song so another
+// translation can take its place without breaking the one-original index.
+func clearOriginal(ctx context.Context, tx *sqlx.Tx, songId int) error {
+	query := fmt.Sprintf("UPDATE %s SET isOriginal = FALSE WHERE songId = $1 AND isOriginal", translationsTable)
+	_, err := tx.ExecContext(ctx, query, songId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to clear original translation")
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// matchFuzzy is the value of the "match" filter that switches name filters
+// from substring matching to pg_trgm similarity.
+const matchFuzzy = "fuzzy"
+
+// fuzzyMatch returns a condition matching column against placeholder $argId by
+// trigram similarity and an expression scoring the match between 0 and 1. Word
+// similarity is taken into account so that "metalica" still finds a longer
+// name such as "Metallica Tribute Band".
+func fuzzyMatch(column string, argId int) (string, string) {
+	condition := fmt.Sprintf("(%[1]s %% $%[2]d OR $%[2]d <%% %[1]s)", column, argId)
+	score := fmt.Sprintf("GREATEST(similarity(%[1]s, $%[2]d), word_similarity($%[2]d, %[1]s))", column, argId)
+	return condition, score
+}
+
+// The functions below mirror pg_trgm for backends without the extension.
+const (
+	similarityThreshold     = 0.3
+	wordSimilarityThreshold = 0.6
+)
+
+// trigramMatch is the Go counterpart of the condition and score built by
+// fuzzyMatch for matching value against the search term q.
+func trigramMatch(value, q string) (bool, float64) {
+	sim := trigramSimilarity(value, q)
+	wordSim := trigramWordSimilarity(q, value)
+	return sim >= similarityThreshold || wordSim >= wordSimilarityThreshold, max(sim, wordSim)
+}
+
+// trigramSeq returns the trigrams of the words in order, each word padded
+// with two spaces in front and one behind as pg_trgm does.
+func trigramSeq(s string) []string {
+	var seq []string
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			seq = append(seq, string(padded[i:i+3]))
+		}
+	}
+	return seq
+}
+
+func trigramSet(seq []string) map[string]bool {
+	set := make(map[string]bool, len(seq))
+	for _, t := range seq {
+		set[t] = true
+	}
+	return set
+}
+
+// trigramSimilarity is pg_trgm's similarity(a, b).
+func trigramSimilarity(a, b string) float64 {
+	setA, setB := trigramSet(trigramSeq(a)), trigramSet(trigramSeq(b))
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range setA {
+		if setB[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(setA)+len(setB)-shared)
+}
+
+// trigramWordSimilarity is pg_trgm's word_similarity(a, b): the best
+// similarity between the trigrams of a and any continuous extent of the
+// ordered trigrams of b.
+func trigramWordSimilarity(a, b string) float64 {
+	query := trigramSet(trigramSeq(a))
+	seq := trigramSeq(b)
+	best := 0.0
+	for i := range seq {
+		extent := map[string]bool{}
+		shared := 0
+		for j := i; j < len(seq); j++ {
+			if !extent[seq[j]] {
+				extent[seq[j]] = true
+				if query[seq[j]] {
+					shared++
+				}
+			}
+			sim := float64(shared) / float64(len(query)+len(extent)-shared)
+			best = max(best, sim)
+		}
+	}
+	return best
+}
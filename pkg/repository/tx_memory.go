@@ -0,0 +1,30 @@
+package repository
+
+import "context"
+
+type UnitOfWorkMemory struct {
+	store *memoryStore
+}
+
+// InTransaction runs fn with repositories over the shared store and puts the
+// store back the way it was if fn fails. Units of work run one at a time;
+// writes made outside of one while it runs are lost if it is rolled back.
+func (u *UnitOfWorkMemory) InTransaction(ctx context.Context, fn func(repos TxRepositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	u.store.txMu.Lock()
+	defer u.store.txMu.Unlock()
+
+	saved := u.store.snapshot()
+	err := fn(TxRepositories{
+		Group:         &GroupMemory{store: u.store},
+		Authorisation: &SongMemory{store: u.store},
+		SongDetails:   &SongDetailsMemory{store: u.store},
+	})
+	if err != nil {
+		u.store.restore(saved)
+		return err
+	}
+	return nil
+}
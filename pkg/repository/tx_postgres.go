@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// dbtx is what a repository needs to run queries. Both *sqlx.DB and *sqlx.Tx
+// satisfy it, so the same repository code runs standalone or inside a unit
+// of work.
+type dbtx interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txBeginner starts transactions, *sqlx.DB does.
+type txBeginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// inTx runs fn in a transaction of its own, or in the surrounding one when
+// db is already bound to a unit of work.
+func inTx(ctx context.Context, db dbtx, fn func(tx *sqlx.Tx) error) error {
+	var beginner txBeginner
+	switch db := db.(type) {
+	case *sqlx.Tx:
+		return fn(db)
+	case txBeginner:
+		beginner = db
+	default:
+		return fmt.Errorf("cannot begin a transaction on %T", db)
+	}
+	tx, err := beginner.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type UnitOfWorkPostgres struct {
+	db *sqlx.DB
+}
+
+func NewUnitOfWorkPostgres(db *sqlx.DB) *UnitOfWorkPostgres {
+	return &UnitOfWorkPostgres{db: db}
+}
+
+// InTransaction runs fn with repositories bound to a single transaction. It is
+// committed when fn returns nil and rolled back otherwise.
+func (u *UnitOfWorkPostgres) InTransaction(ctx context.Context, fn func(repos TxRepositories) error) error {
+	tx, err := u.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(TxRepositories{
+		Group:         &GroupPostgres{db: tx},
+		Authorisation: &SongPostgres{db: tx},
+		SongDetails:   &SongDetailPostgres{db: tx},
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		logrus.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type UnitOfWorkSQLite struct {
+	db *sqlx.DB
+}
+
+func NewUnitOfWorkSQLite(db *sqlx.DB) *UnitOfWorkSQLite {
+	return &UnitOfWorkSQLite{db: db}
+}
+
+// InTransaction runs fn with repositories bound to a single transaction. It is
+// committed when fn returns nil and rolled back otherwise.
+func (u *UnitOfWorkSQLite) InTransaction(ctx context.Context, fn func(repos TxRepositories) error) error {
+	tx, err := u.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(TxRepositories{
+		Group:         &GroupSQLite{db: tx},
+		Authorisation: &SongSQLite{db: tx},
+		SongDetails:   &SongDetailsSQLite{db: tx},
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		logrus.WithError(err).Error("Failed to commit transaction")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"time"
+	musiclibrary "time-tracker"
+)
+
+// ErrNotSupported is returned by storage backends that only implement the
+// core groups, songs and song details repositories.
+var ErrNotSupported error = &musiclibrary.Error{
+	Kind:    musiclibrary.ErrUnsupported,
+	Message: "not supported by this storage backend",
+}
+
+// unsupported stands in for every repository a backend does not implement,
+// so the server still starts and the affected endpoints fail cleanly.
+type unsupported struct{}
+
+func (unsupported) CreateAlbum(context.Context, musiclibrary.Album) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (unsupported) GetAllAlbums(context.Context, musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Album], error) {
+	return musiclibrary.Page[musiclibrary.Album]{}, ErrNotSupported
+}
+
+func (unsupported) GetAlbumById(context.Context, int) (musiclibrary.Album, error) {
+	return musiclibrary.Album{}, ErrNotSupported
+}
+
+func (unsupported) DeleteAlbum(context.Context, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) UpdateAlbum(context.Context, int, musiclibrary.UpdateAlbumInput) error {
+	return ErrNotSupported
+}
+
+func (unsupported) GetAlbumTracks(context.Context, int) ([]musiclibrary.AlbumTrack, error) {
+	return nil, ErrNotSupported
+}
+
+// GetSongAlbums reports no albums rather than failing, so songs can still be
+// fetched with include=albums from backends that do not store albums.
+func (unsupported) GetSongAlbums(context.Context, []int) ([]musiclibrary.SongAlbum, error) {
+	return nil, nil
+}
+
+func (unsupported) AddTrack(context.Context, int, musiclibrary.AlbumTrackInput) error {
+	return ErrNotSupported
+}
+
+func (unsupported) RemoveTrack(context.Context, int, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) CreateArtist(context.Context, musiclibrary.Artist) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (unsupported) GetAllArtists(context.Context, musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Artist], error) {
+	return musiclibrary.Page[musiclibrary.Artist]{}, ErrNotSupported
+}
+
+func (unsupported) GetArtistById(context.Context, int) (musiclibrary.Artist, error) {
+	return musiclibrary.Artist{}, ErrNotSupported
+}
+
+func (unsupported) DeleteArtist(context.Context, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) UpdateArtist(context.Context, int, musiclibrary.UpdateArtistInput) error {
+	return ErrNotSupported
+}
+
+func (unsupported) AddMember(context.Context, int, musiclibrary.MembershipInput) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (unsupported) RemoveMember(context.Context, int, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) GetGroupMembers(context.Context, int, string, string) ([]musiclibrary.Membership, error) {
+	return nil, ErrNotSupported
+}
+
+func (unsupported) GetArtistGroups(context.Context, int) ([]musiclibrary.Membership, error) {
+	return nil, ErrNotSupported
+}
+
+func (unsupported) CreateGenre(context.Context, musiclibrary.Genre) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (unsupported) GetAllGenres(context.Context, musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Genre], error) {
+	return musiclibrary.Page[musiclibrary.Genre]{}, ErrNotSupported
+}
+
+func (unsupported) DeleteGenre(context.Context, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) GetGenres(context.Context, musiclibrary.Taggable, int) ([]musiclibrary.Genre, error) {
+	return nil, ErrNotSupported
+}
+
+func (unsupported) AttachGenre(context.Context, musiclibrary.Taggable, int, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) DetachGenre(context.Context, musiclibrary.Taggable, int, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) GetAllTags(context.Context, musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Tag], error) {
+	return musiclibrary.Page[musiclibrary.Tag]{}, ErrNotSupported
+}
+
+func (unsupported) GetTags(context.Context, musiclibrary.Taggable, int) ([]musiclibrary.Tag, error) {
+	return nil, ErrNotSupported
+}
+
+func (unsupported) AttachTag(context.Context, musiclibrary.Taggable, int, string) error {
+	return ErrNotSupported
+}
+
+func (unsupported) DetachTag(context.Context, musiclibrary.Taggable, int, string) error {
+	return ErrNotSupported
+}
+
+func (unsupported) SearchLyrics(context.Context, string, musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.SearchResult], error) {
+	return musiclibrary.Page[musiclibrary.SearchResult]{}, ErrNotSupported
+}
+
+func (unsupported) GetTranslations(context.Context, int) ([]musiclibrary.Translation, error) {
+	return nil, ErrNotSupported
+}
+
+func (unsupported) GetTranslation(context.Context, int, string) (musiclibrary.Translation, error) {
+	return musiclibrary.Translation{}, ErrNotSupported
+}
+
+func (unsupported) GetOriginal(context.Context, int) (musiclibrary.Translation, error) {
+	return musiclibrary.Translation{}, ErrNotSupported
+}
+
+func (unsupported) CreateTranslation(context.Context, int, musiclibrary.Translation) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (unsupported) UpdateTranslation(context.Context, int, string, musiclibrary.UpdateTranslationInput) error {
+	return ErrNotSupported
+}
+
+func (unsupported) DeleteTranslation(context.Context, int, string) error {
+	return ErrNotSupported
+}
+
+func (unsupported) ImportSongs(context.Context, []musiclibrary.ImportRow, bool) ([]musiclibrary.ImportResult, error) {
+	return nil, ErrNotSupported
+}
+
+func (unsupported) RestoreArchive(context.Context, bool, func(ArchiveRestorer) error) error {
+	return ErrNotSupported
+}
+
+func (unsupported) Export(context.Context, ExportWriter) error {
+	return ErrNotSupported
+}
+
+func (unsupported) EnqueueJob(context.Context, string, []byte, int) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func (unsupported) ClaimJob(context.Context, time.Duration) (musiclibrary.Job, error) {
+	return musiclibrary.Job{}, ErrNotSupported
+}
+
+func (unsupported) CompleteJob(context.Context, int, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) RescheduleJob(context.Context, int, int, string, time.Duration) error {
+	return ErrNotSupported
+}
+
+func (unsupported) DeadLetterJob(context.Context, int, int, string) error {
+	return ErrNotSupported
+}
+
+func (unsupported) RequeueJob(context.Context, int) error {
+	return ErrNotSupported
+}
+
+func (unsupported) GetJobs(context.Context, string, musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Job], error) {
+	return musiclibrary.Page[musiclibrary.Job]{}, ErrNotSupported
+}
+
+func (unsupported) GetJobById(context.Context, int) (musiclibrary.Job, error) {
+	return musiclibrary.Job{}, ErrNotSupported
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	musiclibrary "time-tracker"
+)
+
+type memoryAPIKey struct {
+	musiclibrary.APIKey
+	hash string
+}
+
+type UserMemory struct {
+	store *memoryStore
+}
+
+func (r *UserMemory) CreateUser(ctx context.Context, user musiclibrary.User) (int, error) {
+	var id int
+	err := r.store.write(ctx, func() error {
+		for _, u := range r.store.users {
+			if u.Name == user.Name {
+				return musiclibrary.NewConflictError("user already exists")
+			}
+		}
+		r.store.lastUserId++
+		id = r.store.lastUserId
+		r.store.users[id] = musiclibrary.User{Id: id, Name: user.Name, Role: user.Role, CreatedAt: memoryNow()}
+		return nil
+	})
+	return id, err
+}
+
+func (r *UserMemory) GetUsers(ctx context.Context) ([]musiclibrary.User, error) {
+	var users []musiclibrary.User
+	err := r.store.read(ctx, func() error {
+		for _, user := range r.store.users {
+			users = append(users, user)
+		}
+		sort.Slice(users, func(i, j int) bool {
+			return users[i].Id < users[j].Id
+		})
+		return nil
+	})
+	return users, err
+}
+
+func (r *UserMemory) GetUserById(ctx context.Context, id int) (musiclibrary.User, error) {
+	var user musiclibrary.User
+	err := r.store.read(ctx, func() error {
+		var ok bool
+		if user, ok = r.store.users[id]; !ok {
+			return notFound("user")
+		}
+		return nil
+	})
+	return user, err
+}
+
+func (r *UserMemory) CreateAPIKey(ctx context.Context, key musiclibrary.APIKey, hash string) (int, error) {
+	var id int
+	err := r.store.write(ctx, func() error {
+		if _, ok := r.store.users[key.UserId]; !ok {
+			return musiclibrary.NewForeignKeyError(foreignKeyMessage("API key"))
+		}
+		for _, k := range r.store.apiKeys {
+			if k.hash == hash {
+				return musiclibrary.NewConflictError("API key already exists")
+			}
+		}
+		r.store.lastAPIKeyId++
+		id = r.store.lastAPIKeyId
+		key.Id, key.CreatedAt, key.LastUsedAt = id, memoryNow(), nil
+		r.store.apiKeys[id] = memoryAPIKey{APIKey: key, hash: hash}
+		return nil
+	})
+	return id, err
+}
+
+func (r *UserMemory) GetAPIKeys(ctx context.Context, userId int) ([]musiclibrary.APIKey, error) {
+	keys := []musiclibrary.APIKey{}
+	err := r.store.read(ctx, func() error {
+		for _, k := range r.store.apiKeys {
+			if k.UserId == userId {
+				keys = append(keys, k.APIKey)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].Id < keys[j].Id
+		})
+		return nil
+	})
+	return keys, err
+}
+
+func (r *UserMemory) DeleteAPIKey(ctx context.Context, userId, keyId int) error {
+	return r.store.write(ctx, func() error {
+		if k, ok := r.store.apiKeys[keyId]; !ok || k.UserId != userId {
+			return notFound("API key")
+		}
+		delete(r.store.apiKeys, keyId)
+		return nil
+	})
+}
+
+func (r *UserMemory) GetUserByAPIKey(ctx context.Context, hash string) (musiclibrary.User, error) {
+	var user musiclibrary.User
+	err := r.store.write(ctx, func() error {
+		for id, k := range r.store.apiKeys {
+			if k.hash != hash {
+				continue
+			}
+			now := memoryNow()
+			k.LastUsedAt = &now
+			r.store.apiKeys[id] = k
+			user = r.store.users[k.UserId]
+			return nil
+		}
+		return notFound("API key")
+	})
+	return user, err
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	userColumns   = `id, name, role, TO_CHAR(createdAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS createdat`
+	apiKeyColumns = `id, userId AS userid, name, prefix,
+	TO_CHAR(createdAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS createdat,
+	TO_CHAR(lastUsedAt, 'YYYY-MM-DD"T"HH24:MI:SS') AS lastusedat`
+)
+
+type UserPostgres struct {
+	db *sqlx.DB
+}
+
+func NewUserPostgres(db *sqlx.DB) *UserPostgres {
+	return &UserPostgres{db: db}
+}
+
+func (r *UserPostgres) CreateUser(ctx context.Context, user musiclibrary.User) (int, error) {
+	logrus.WithField("name", user.Name).Debug("Creating user")
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (name, role) VALUES ($1, $2) RETURNING id", usersTable)
+	row := r.db.QueryRowContext(ctx, query, user.Name, user.Role)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to create user")
+		return 0, dbError(err, "user")
+	}
+	logrus.WithField("id", id).Info("User created successfully")
+	return id, nil
+}
+
+func (r *UserPostgres) GetUsers(ctx context.Context) ([]musiclibrary.User, error) {
+	logrus.Debug("Fetching users")
+	var users []musiclibrary.User
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY id", userColumns, usersTable)
+	if err := r.db.SelectContext(ctx, &users, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch users")
+		return nil, dbError(err, "user")
+	}
+	logrus.WithField("count", len(users)).Info("Fetched users successfully")
+	return users, nil
+}
+
+func (r *UserPostgres) GetUserById(ctx context.Context, id int) (musiclibrary.User, error) {
+	logrus.WithField("id", id).Debug("Fetching user by ID")
+	var user musiclibrary.User
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", userColumns, usersTable)
+	err := r.db.GetContext(ctx, &user, query, id)
+	return user, dbError(err, "user")
+}
+
+func (r *UserPostgres) CreateAPIKey(ctx context.Context, key musiclibrary.APIKey, hash string) (int, error) {
+	logrus.WithField("userId", key.UserId).Debug("Creating API key")
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (userId, name, prefix, keyHash) VALUES ($1, $2, $3, $4) RETURNING id", apiKeysTable)
+	row := r.db.QueryRowContext(ctx, query, key.UserId, key.Name, key.Prefix, hash)
+	if err := row.Scan(&id); err != nil {
+		logrus.WithError(err).Error("Failed to create API key")
+		return 0, dbError(err, "API key")
+	}
+	logrus.WithField("id", id).Info("API key created successfully")
+	return id, nil
+}
+
+func (r *UserPostgres) GetAPIKeys(ctx context.Context, userId int) ([]musiclibrary.APIKey, error) {
+	logrus.WithField("userId", userId).Debug("Fetching API keys")
+	keys := []musiclibrary.APIKey{}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE userId = $1 ORDER BY id", apiKeyColumns, apiKeysTable)
+	if err := r.db.SelectContext(ctx, &keys, query, userId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch API keys")
+		return nil, dbError(err, "API key")
+	}
+	return keys, nil
+}
+
+func (r *UserPostgres) DeleteAPIKey(ctx context.Context, userId, keyId int) error {
+	logrus.WithField("id", keyId).Debug("Deleting API key")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND userId = $2", apiKeysTable)
+	res, err := r.db.ExecContext(ctx, query, keyId, userId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete API key")
+		return dbError(err, "API key")
+	}
+	if err := affected(res, "API key"); err != nil {
+		return err
+	}
+	logrus.WithField("id", keyId).Info("API key deleted successfully")
+	return nil
+}
+
+func (r *UserPostgres) GetUserByAPIKey(ctx context.Context, hash string) (musiclibrary.User, error) {
+	var user musiclibrary.User
+	query := fmt.Sprintf(`
+		WITH used AS (
+			UPDATE %s SET lastUsedAt = now() WHERE keyHash = $1 RETURNING userId
+		)
+		SELECT %s FROM %s WHERE id = (SELECT userId FROM used)`, apiKeysTable, userColumns, usersTable)
+	err := r.db.GetContext(ctx, &user, query, hash)
+	return user, dbError(err, "API key")
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	musiclibrary "time-tracker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+const apiKeyColumnsSQLite = `id, userid, name, prefix, createdat, lastusedat`
+
+type UserSQLite struct {
+	db *sqlx.DB
+}
+
+func NewUserSQLite(db *sqlx.DB) *UserSQLite {
+	return &UserSQLite{db: db}
+}
+
+func (r *UserSQLite) CreateUser(ctx context.Context, user musiclibrary.User) (int, error) {
+	logrus.WithField("name", user.Name).Debug("Creating user")
+	query := fmt.Sprintf("INSERT INTO %s (name, role) VALUES (?, ?)", usersTable)
+	res, err := r.db.ExecContext(ctx, query, user.Name, user.Role)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create user")
+		return 0, dbError(err, "user")
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	logrus.WithField("id", id).Info("User created successfully")
+	return int(id), nil
+}
+
+func (r *UserSQLite) GetUsers(ctx context.Context) ([]musiclibrary.User, error) {
+	logrus.Debug("Fetching users")
+	var users []musiclibrary.User
+	query := fmt.Sprintf("SELECT id, name, role, createdat FROM %s ORDER BY id", usersTable)
+	if err := r.db.SelectContext(ctx, &users, query); err != nil {
+		logrus.WithError(err).Error("Failed to fetch users")
+		return nil, dbError(err, "user")
+	}
+	logrus.WithField("count", len(users)).Info("Fetched users successfully")
+	return users, nil
+}
+
+func (r *UserSQLite) GetUserById(ctx context.Context, id int) (musiclibrary.User, error) {
+	logrus.WithField("id", id).Debug("Fetching user by ID")
+	var user musiclibrary.User
+	query := fmt.Sprintf("SELECT id, name, role, createdat FROM %s WHERE id = ?", usersTable)
+	err := r.db.GetContext(ctx, &user, query, id)
+	return user, dbError(err, "user")
+}
+
+func (r *UserSQLite) CreateAPIKey(ctx context.Context, key musiclibrary.APIKey, hash string) (int, error) {
+	logrus.WithField("userId", key.UserId).Debug("Creating API key")
+	query := fmt.Sprintf("INSERT INTO %s (userid, name, prefix, keyhash) VALUES (?, ?, ?, ?)", apiKeysTable)
+	res, err := r.db.ExecContext(ctx, query, key.UserId, key.Name, key.Prefix, hash)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create API key")
+		return 0, dbError(err, "API key")
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	logrus.WithField("id", id).Info("API key created successfully")
+	return int(id), nil
+}
+
+func (r *UserSQLite) GetAPIKeys(ctx context.Context, userId int) ([]musiclibrary.APIKey, error) {
+	logrus.WithField("userId", userId).Debug("Fetching API keys")
+	keys := []musiclibrary.APIKey{}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE userid = ? ORDER BY id", apiKeyColumnsSQLite, apiKeysTable)
+	if err := r.db.SelectContext(ctx, &keys, query, userId); err != nil {
+		logrus.WithError(err).Error("Failed to fetch API keys")
+		return nil, dbError(err, "API key")
+	}
+	return keys, nil
+}
+
+func (r *UserSQLite) DeleteAPIKey(ctx context.Context, userId, keyId int) error {
+	logrus.WithField("id", keyId).Debug("Deleting API key")
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ? AND userid = ?", apiKeysTable)
+	res, err := r.db.ExecContext(ctx, query, keyId, userId)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete API key")
+		return dbError(err, "API key")
+	}
+	if err := affected(res, "API key"); err != nil {
+		return err
+	}
+	logrus.WithField("id", keyId).Info("API key deleted successfully")
+	return nil
+}
+
+func (r *UserSQLite) GetUserByAPIKey(ctx context.Context, hash string) (musiclibrary.User, error) {
+	var user musiclibrary.User
+	query := fmt.Sprintf(`SELECT u.id, u.name, u.role, u.createdat FROM %s u JOIN %s k ON k.userid = u.id WHERE k.keyhash = ?`,
+		usersTable, apiKeysTable)
+	if err := r.db.GetContext(ctx, &user, query, hash); err != nil {
+		return user, dbError(err, "API key")
+	}
+	used := fmt.Sprintf(`UPDATE %s SET lastusedat = strftime('%%Y-%%m-%%dT%%H:%%M:%%S', 'now') WHERE keyhash = ?`, apiKeysTable)
+	if _, err := r.db.ExecContext(ctx, used, hash); err != nil {
+		logrus.WithError(err).Warn("Failed to record API key use")
+	}
+	return user, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+type AlbumService struct {
+	repo repository.Album
+}
+
+func NewAlbumService(repo repository.Album) *AlbumService {
+	return &AlbumService{repo: repo}
+}
+
+func (s *AlbumService) CreateAlbum(ctx context.Context, album musiclibrary.Album) (int, error) {
+	return s.repo.CreateAlbum(ctx, album)
+}
+
+func (s *AlbumService) GetAllAlbums(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Album], error) {
+	return s.repo.GetAllAlbums(ctx, page)
+}
+
+func (s *AlbumService) GetAlbumById(ctx context.Context, id int) (musiclibrary.Album, error) {
+	return s.repo.GetAlbumById(ctx, id)
+}
+
+func (s *AlbumService) DeleteAlbum(ctx context.Context, id int) error {
+	return s.repo.DeleteAlbum(ctx, id)
+}
+
+func (s *AlbumService) UpdateAlbum(ctx context.Context, id int, input musiclibrary.UpdateAlbumInput) error {
+	return s.repo.UpdateAlbum(ctx, id, input)
+}
+
+func (s *AlbumService) GetAlbumTracks(ctx context.Context, albumId int) ([]musiclibrary.AlbumTrack, error) {
+	return s.repo.GetAlbumTracks(ctx, albumId)
+}
+
+func (s *AlbumService) AddTrack(ctx context.Context, albumId int, input musiclibrary.AlbumTrackInput) error {
+	return s.repo.AddTrack(ctx, albumId, input)
+}
+
+func (s *AlbumService) RemoveTrack(ctx context.Context, albumId, songId int) error {
+	return s.repo.RemoveTrack(ctx, albumId, songId)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+type ArtistService struct {
+	repo repository.Artist
+}
+
+func NewArtistService(repo repository.Artist) *ArtistService {
+	return &ArtistService{repo: repo}
+}
+
+func (s *ArtistService) CreateArtist(ctx context.Context, artist musiclibrary.Artist) (int, error) {
+	return s.repo.CreateArtist(ctx, artist)
+}
+
+func (s *ArtistService) GetAllArtists(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Artist], error) {
+	return s.repo.GetAllArtists(ctx, page)
+}
+
+func (s *ArtistService) GetArtistById(ctx context.Context, id int) (musiclibrary.Artist, error) {
+	return s.repo.GetArtistById(ctx, id)
+}
+
+func (s *ArtistService) DeleteArtist(ctx context.Context, id int) error {
+	return s.repo.DeleteArtist(ctx, id)
+}
+
+func (s *ArtistService) UpdateArtist(ctx context.Context, id int, input musiclibrary.UpdateArtistInput) error {
+	return s.repo.UpdateArtist(ctx, id, input)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+	"time-tracker/pkg/songinfo"
+
+	"github.com/sirupsen/logrus"
+)
+
+type refreshSongDetailsPayload struct {
+	SongId int `json:"songId"`
+}
+
+// songEnricher fills in song details from the song info API, it runs as the
+// handler of refreshSongDetails jobs.
+type songEnricher struct {
+	songs   repository.Authorisation
+	groups  repository.Group
+	details repository.SongDetails
+	info    SongInfo
+}
+
+func (e *songEnricher) refreshSongDetails(ctx context.Context, payload []byte) error {
+	var p refreshSongDetailsPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return permanent(fmt.Errorf("invalid payload: %w", err))
+	}
+	log := logrus.WithField("song_id", p.SongId)
+
+	song, err := e.songs.GetSongById(ctx, p.SongId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return permanent(fmt.Errorf("song %d not found", p.SongId))
+	}
+	if err != nil {
+		return err
+	}
+	group, err := e.groups.GetGroupById(ctx, song.GroupId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return permanent(fmt.Errorf("group %d not found", song.GroupId))
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := e.info.Fetch(ctx, group.GroupName, song.SongName)
+	if errors.Is(err, songinfo.ErrNotFound) {
+		log.Info("Song info API has no details for song")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = e.details.UpdateSongDetails(ctx, song.Id, musiclibrary.UpdateSongDetailsInput{
+		ReleaseDate: info.ReleaseDate,
+		Text:        info.Text,
+		Link:        info.Link,
+		Author:      "song-info",
+		Comment:     "Filled in from the song info API",
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Song details filled in from song info API")
+	return nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+const (
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatCSV     = "csv"
+	ExportFormatArchive = "archive"
+
+	// ArchiveVersion is bumped whenever the archive layout changes in a way
+	// older readers cannot restore.
+	ArchiveVersion = 1
+)
+
+var ErrUnknownExportFormat = musiclibrary.NewValidationError("format", "must be ndjson, csv or archive")
+
+const (
+	recordHeader      = "header"
+	recordGroup       = "group"
+	recordSong        = "song"
+	recordSongDetails = "songDetails"
+)
+
+type exportRecord struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+type archiveHeader struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	ExportedAt string `json:"exportedAt"`
+}
+
+type ExportService struct {
+	repo repository.Export
+}
+
+func NewExportService(repo repository.Export) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// Export streams the library to w in the given format.
+func (s *ExportService) Export(ctx context.Context, format string, w io.Writer) error {
+	switch format {
+	case ExportFormatNDJSON:
+		return s.repo.Export(ctx, &ndjsonExporter{enc: json.NewEncoder(w)})
+	case ExportFormatCSV:
+		exporter := &csvExporter{zip: zip.NewWriter(w)}
+		if err := s.repo.Export(ctx, exporter); err != nil {
+			return err
+		}
+		return exporter.Close()
+	case ExportFormatArchive:
+		gz := gzip.NewWriter(w)
+		exporter := &ndjsonExporter{enc: json.NewEncoder(gz)}
+		err := exporter.enc.Encode(exportRecord{Type: recordHeader, Data: archiveHeader{
+			Format:     "music-library",
+			Version:    ArchiveVersion,
+			ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		}})
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Export(ctx, exporter); err != nil {
+			return err
+		}
+		return gz.Close()
+	}
+	return ErrUnknownExportFormat
+}
+
+// ndjsonExporter writes one {"type": ..., "data": ...} object per line.
+type ndjsonExporter struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonExporter) Group(group musiclibrary.Group) error {
+	return e.enc.Encode(exportRecord{Type: recordGroup, Data: group})
+}
+
+func (e *ndjsonExporter) Song(song musiclibrary.Song) error {
+	return e.enc.Encode(exportRecord{Type: recordSong, Data: song})
+}
+
+func (e *ndjsonExporter) SongDetails(details musiclibrary.SongDetails) error {
+	return e.enc.Encode(exportRecord{Type: recordSongDetails, Data: details})
+}
+
+// csvExporter writes a zip with one CSV file per entity. Records arrive grouped
+// by entity, so each file is finished before the next one is started.
+type csvExporter struct {
+	zip     *zip.Writer
+	csv     *csv.Writer
+	current string
+}
+
+var csvFiles = []struct {
+	name   string
+	header []string
+}{
+	{recordGroup, []string{"id", "groupName"}},
+	{recordSong, []string{"id", "songName", "groupId"}},
+	{recordSongDetails, []string{"id", "songId", "releaseDate", "text", "link"}},
+}
+
+// advance opens the CSV files up to and including kind, so that entities
+// without any rows still get a file with just the header.
+func (e *csvExporter) advance(kind string) error {
+	for e.current != kind {
+		next := 0
+		for i, file := range csvFiles {
+			if file.name == e.current {
+				next = i + 1
+			}
+		}
+		if next == len(csvFiles) {
+			return nil
+		}
+		if e.csv != nil {
+			e.csv.Flush()
+			if err := e.csv.Error(); err != nil {
+				return err
+			}
+		}
+		f, err := e.zip.Create(csvFiles[next].name + "s.csv")
+		if err != nil {
+			return err
+		}
+		e.csv = csv.NewWriter(f)
+		e.current = csvFiles[next].name
+		if err := e.csv.Write(csvFiles[next].header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvExporter) Group(group musiclibrary.Group) error {
+	if err := e.advance(recordGroup); err != nil {
+		return err
+	}
+	return e.csv.Write([]string{strconv.Itoa(group.Id), group.GroupName})
+}
+
+func (e *csvExporter) Song(song musiclibrary.Song) error {
+	if err := e.advance(recordSong); err != nil {
+		return err
+	}
+	return e.csv.Write([]string{strconv.Itoa(song.Id), song.SongName, strconv.Itoa(song.GroupId)})
+}
+
+func (e *csvExporter) SongDetails(details musiclibrary.SongDetails) error {
+	if err := e.advance(recordSongDetails); err != nil {
+		return err
+	}
+	return e.csv.Write([]string{
+		strconv.Itoa(details.Id), strconv.Itoa(details.SongId), details.ReleaseDate, details.Text, details.Link,
+	})
+}
+
+func (e *csvExporter) Close() error {
+	if err := e.advance(recordSongDetails); err != nil {
+		return err
+	}
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+	return e.zip.Close()
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+type GenreService struct {
+	repo repository.Genre
+}
+
+func NewGenreService(repo repository.Genre) *GenreService {
+	return &GenreService{repo: repo}
+}
+
+func (s *GenreService) CreateGenre(ctx context.Context, genre musiclibrary.Genre) (int, error) {
+	return s.repo.CreateGenre(ctx, genre)
+}
+
+func (s *GenreService) GetAllGenres(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Genre], error) {
+	return s.repo.GetAllGenres(ctx, page)
+}
+
+func (s *GenreService) DeleteGenre(ctx context.Context, id int) error {
+	return s.repo.DeleteGenre(ctx, id)
+}
+
+func (s *GenreService) GetGenres(ctx context.Context, target musiclibrary.Taggable, targetId int) ([]musiclibrary.Genre, error) {
+	return s.repo.GetGenres(ctx, target, targetId)
+}
+
+func (s *GenreService) AttachGenre(ctx context.Context, target musiclibrary.Taggable, targetId, genreId int) error {
+	return s.repo.AttachGenre(ctx, target, targetId, genreId)
+}
+
+func (s *GenreService) DetachGenre(ctx context.Context, target musiclibrary.Taggable, targetId, genreId int) error {
+	return s.repo.DetachGenre(ctx, target, targetId, genreId)
+}
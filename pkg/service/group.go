@@ -1,33 +1,57 @@
 package service
 
 import (
+	"context"
 	timetracker "time-tracker"
 	"time-tracker/pkg/repository"
 )
 
 type GroupServise struct {
-	repo repository.Group
+	repo  repository.Group
+	songs repository.Authorisation
 }
 
-func NewGroupService(repo repository.Group) *GroupServise {
-	return &GroupServise{repo: repo}
+func NewGroupService(repo repository.Group, songs repository.Authorisation) *GroupServise {
+	return &GroupServise{repo: repo, songs: songs}
 }
 
-func (s *GroupServise) CreateGroup(Group timetracker.Group) (int, error) {
-	return s.repo.CreateGroup(Group)
+func (s *GroupServise) CreateGroup(ctx context.Context, Group timetracker.Group) (int, error) {
+	return s.repo.CreateGroup(ctx, Group)
 }
 
-func (s *GroupServise) GetAllGroups() ([]timetracker.Group, error) {
-	return s.repo.GetAllGroups()
+// GetAllGroups is GetGroupsWithFilter without filters.
+func (s *GroupServise) GetAllGroups(ctx context.Context, page timetracker.PageRequest) (timetracker.Page[timetracker.Group], error) {
+	return s.repo.GetGroupsWithFilter(ctx, nil, page)
 }
 
-func (s *GroupServise) DeleteGroup(id int) error {
-	return s.repo.DeleteGroup(id)
+// GetGroupById returns a group with the related resources named in include,
+// only songs can be included.
+func (s *GroupServise) GetGroupById(ctx context.Context, id int, include []string) (timetracker.GroupWithRelations, error) {
+	includes, err := parseInclude(include, IncludeSongs)
+	if err != nil {
+		return timetracker.GroupWithRelations{}, err
+	}
+	group, err := s.repo.GetGroupById(ctx, id)
+	if err != nil {
+		return timetracker.GroupWithRelations{}, err
+	}
+
+	result := timetracker.GroupWithRelations{Group: group}
+	if includes[IncludeSongs] {
+		if result.Songs, err = s.songs.GetSongsByGroupIds(ctx, []int{id}); err != nil {
+			return timetracker.GroupWithRelations{}, err
+		}
+	}
+	return result, nil
+}
+
+func (s *GroupServise) DeleteGroup(ctx context.Context, id int) error {
+	return s.repo.DeleteGroup(ctx, id)
 }
 
-func (s *GroupServise) UpdateGroup(id int, input timetracker.UpdateGroupInput) error {
-	return s.repo.UpdateGroup(id, input)
+func (s *GroupServise) UpdateGroup(ctx context.Context, id int, input timetracker.UpdateGroupInput) error {
+	return s.repo.UpdateGroup(ctx, id, input)
 }
-func (s *GroupServise) GetGroupsWithFilter(filters map[string]string, page int, limit int) ([]timetracker.Group, error) {
-	return s.repo.GetGroupsWithFilter(filters, page, limit)
+func (s *GroupServise) GetGroupsWithFilter(ctx context.Context, filters map[string]string, page timetracker.PageRequest) (timetracker.Page[timetracker.Group], error) {
+	return s.repo.GetGroupsWithFilter(ctx, filters, page)
 }
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+const (
+	ImportFormatCSV     = "csv"
+	ImportFormatNDJSON  = "ndjson"
+	ImportFormatArchive = "archive"
+)
+
+var (
+	ErrUnknownImportFormat = musiclibrary.NewValidationError("format", "must be csv, ndjson or archive")
+	ErrInvalidArchive      = &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: "not a music library archive"}
+)
+
+// importColumns is the column order of a CSV file without a header row.
+var importColumns = []string{"group", "song", "releasedate", "link", "text"}
+
+type ImportService struct {
+	repo repository.Import
+}
+
+func NewImportService(repo repository.Import) *ImportService {
+	return &ImportService{repo: repo}
+}
+
+// Import reads songs from r and creates or updates them. Rows that cannot be
+// parsed or fail validation are reported as errors and never reach the
+// database, the rest are imported in a single transaction.
+func (s *ImportService) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (musiclibrary.ImportReport, error) {
+	var rows []musiclibrary.ImportRow
+	var results []musiclibrary.ImportResult
+	var err error
+	switch format {
+	case ImportFormatCSV:
+		rows, results, err = parseImportCSV(r)
+	case ImportFormatNDJSON:
+		rows, results, err = parseImportNDJSON(r)
+	case ImportFormatArchive:
+		results, err = s.restoreArchive(ctx, r, dryRun)
+		if err != nil {
+			return musiclibrary.ImportReport{}, err
+		}
+		return importReport(dryRun, results), nil
+	default:
+		return musiclibrary.ImportReport{}, ErrUnknownImportFormat
+	}
+	if err != nil {
+		return musiclibrary.ImportReport{}, err
+	}
+
+	valid := make([]musiclibrary.ImportRow, 0, len(rows))
+	for _, row := range rows {
+		if reason := validateImportRow(row); reason != "" {
+			results = append(results, musiclibrary.ImportResult{
+				Line: row.Line, Group: row.Group, Song: row.Song,
+				Status: repository.ImportError, Reason: reason,
+			})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	imported, err := s.repo.ImportSongs(ctx, valid, dryRun)
+	if err != nil {
+		return musiclibrary.ImportReport{}, err
+	}
+	results = append(results, imported...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+	return importReport(dryRun, results), nil
+}
+
+func importReport(dryRun bool, results []musiclibrary.ImportResult) musiclibrary.ImportReport {
+	report := musiclibrary.ImportReport{DryRun: dryRun, Rows: results}
+	for _, result := range results {
+		switch result.Status {
+		case repository.ImportCreated:
+			report.Created++
+		case repository.ImportUpdated:
+			report.Updated++
+		case repository.ImportSkipped:
+			report.Skipped++
+		default:
+			report.Errors++
+		}
+	}
+	return report
+}
+
+func validateImportRow(row musiclibrary.ImportRow) string {
+	if row.Group == "" {
+		return "group is required"
+	}
+	if row.Song == "" {
+		return "song is required"
+	}
+	if row.ReleaseDate != "" {
+		if _, err := time.Parse("2006-01-02", row.ReleaseDate); err != nil {
+			return "releaseDate must be YYYY-MM-DD"
+		}
+	}
+	return ""
+}
+
+// parseImportCSV reads group,song,releaseDate,link,text rows. A first row
+// naming those columns is used as a header and may list them in any order.
+func parseImportCSV(r io.Reader) ([]musiclibrary.ImportRow, []musiclibrary.ImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var rows []musiclibrary.ImportRow
+	var failed []musiclibrary.ImportResult
+	columns := importColumns
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				failed = append(failed, musiclibrary.ImportResult{
+					Line: parseErr.StartLine, Status: repository.ImportError, Reason: parseErr.Err.Error(),
+				})
+				continue
+			}
+			return nil, nil, err
+		}
+		// FieldPos is only valid for a record Read returned without error.
+		line, _ := reader.FieldPos(0)
+		if first && isImportHeader(record) {
+			columns = make([]string, len(record))
+			for i, name := range record {
+				columns[i] = strings.ToLower(strings.TrimSpace(name))
+			}
+			continue
+		}
+
+		values := map[string]string{}
+		for i, value := range record {
+			if i < len(columns) {
+				values[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, musiclibrary.ImportRow{
+			Line:        line,
+			Group:       values["group"],
+			Song:        values["song"],
+			ReleaseDate: values["releasedate"],
+			Link:        values["link"],
+			Text:        values["text"],
+		})
+	}
+	return rows, failed, nil
+}
+
+func isImportHeader(record []string) bool {
+	for _, name := range record {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "group", "song":
+			return true
+		}
+	}
+	return false
+}
+
+// parseImportNDJSON reads one JSON object per line.
+func parseImportNDJSON(r io.Reader) ([]musiclibrary.ImportRow, []musiclibrary.ImportResult, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	var rows []musiclibrary.ImportRow
+	var failed []musiclibrary.ImportResult
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		var row musiclibrary.ImportRow
+		if err := json.Unmarshal([]byte(raw), &row); err != nil {
+			failed = append(failed, musiclibrary.ImportResult{
+				Line: line, Status: repository.ImportError, Reason: fmt.Sprintf("invalid JSON: %v", err),
+			})
+			continue
+		}
+		row.Line = line
+		row.Group = strings.TrimSpace(row.Group)
+		row.Song = strings.TrimSpace(row.Song)
+		row.ReleaseDate = strings.TrimSpace(row.ReleaseDate)
+		row.Link = strings.TrimSpace(row.Link)
+		rows = append(rows, row)
+	}
+	return rows, failed, scanner.Err()
+}
+
+// maxArchiveSize bounds the unpacked size of an archive, the request body
+// limit only bounds what the client sends.
+var maxArchiveSize int64 = 1 << 30 // 1GB
+
+// archiveRecord is one line of an archive, see Export.
+type archiveRecord struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// archiveSong is a song of the archive restored so far, by its id in the
+// archive.
+type archiveSong struct {
+	id      int
+	result  int // index of the song in the results
+	created bool
+}
+
+// restoreArchive restores an archive written by Export record by record, so
+// only the ids of the groups and songs restored so far are kept in memory.
+// Details that differ from those of an existing song replace them, empty
+// ones included, so that the library ends up as it was exported.
+func (s *ImportService) restoreArchive(ctx context.Context, r io.Reader, dryRun bool) ([]musiclibrary.ImportResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	defer gz.Close()
+
+	dec := json.NewDecoder(&archiveLimitReader{r: gz, n: maxArchiveSize})
+	var record archiveRecord
+	if err := dec.Decode(&record); err != nil || record.Type != recordHeader {
+		return nil, ErrInvalidArchive
+	}
+	var header archiveHeader
+	if err := json.Unmarshal(record.Data, &header); err != nil || header.Version < 1 {
+		return nil, ErrInvalidArchive
+	}
+	if header.Version > ArchiveVersion {
+		return nil, fmt.Errorf("%w: version %d is newer than the supported version %d", ErrInvalidArchive, header.Version, ArchiveVersion)
+	}
+
+	var results []musiclibrary.ImportResult
+	err = s.repo.RestoreArchive(ctx, dryRun, func(w repository.ArchiveRestorer) error {
+		groups := map[int]int{}        // library ids by archive id
+		groupNames := map[int]string{} // by archive id
+		songs := map[int]archiveSong{} // by archive id
+		// The restorer matches names ignoring case, two groups or two songs
+		// of a group named alike would be merged and one of them lost.
+		restoredGroups := map[int]int{} // archive ids by library id
+		restoredSongs := map[int]int{}
+		for line := 2; ; line++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			record = archiveRecord{}
+			if err := dec.Decode(&record); errors.Is(err, io.EOF) {
+				return nil
+			} else if errors.Is(err, errArchiveTooLarge) {
+				return err
+			} else if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+			}
+
+			switch record.Type {
+			case recordGroup:
+				var group musiclibrary.Group
+				if err := json.Unmarshal(record.Data, &group); err != nil {
+					return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+				}
+				group.GroupName = strings.TrimSpace(group.GroupName)
+				if group.GroupName == "" {
+					return fmt.Errorf("%w: group %d has no name", ErrInvalidArchive, group.Id)
+				}
+				id, created, err := w.Group(group.GroupName)
+				if err != nil {
+					return err
+				}
+				if other, ok := restoredGroups[id]; ok {
+					return fmt.Errorf("%w: groups %d and %d are both named %q", ErrInvalidArchive, other, group.Id, group.GroupName)
+				}
+				restoredGroups[id] = group.Id
+				groups[group.Id], groupNames[group.Id] = id, group.GroupName
+				result := musiclibrary.ImportResult{Line: line, Group: group.GroupName, Status: repository.ImportCreated}
+				if !created {
+					result.Status, result.Reason = repository.ImportSkipped, "group already exists"
+				}
+				results = append(results, result)
+
+			case recordSong:
+				var song musiclibrary.Song
+				if err := json.Unmarshal(record.Data, &song); err != nil {
+					return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+				}
+				song.SongName = strings.TrimSpace(song.SongName)
+				groupId, ok := groups[song.GroupId]
+				if !ok {
+					return fmt.Errorf("%w: song %d belongs to group %d, which is not in the archive", ErrInvalidArchive, song.Id, song.GroupId)
+				}
+				if song.SongName == "" {
+					return fmt.Errorf("%w: song %d has no name", ErrInvalidArchive, song.Id)
+				}
+				id, created, err := w.Song(groupId, song.SongName)
+				if err != nil {
+					return err
+				}
+				if other, ok := restoredSongs[id]; ok {
+					return fmt.Errorf("%w: songs %d and %d of group %d are both named %q", ErrInvalidArchive, other, song.Id, song.GroupId, song.SongName)
+				}
+				restoredSongs[id] = song.Id
+				songs[song.Id] = archiveSong{id: id, result: len(results), created: created}
+				// An existing song is updated if its details turn out to differ.
+				result := musiclibrary.ImportResult{Line: line, Group: groupNames[song.GroupId], Song: song.SongName, SongId: id, Status: repository.ImportCreated}
+				if !created {
+					result.Status, result.Reason = repository.ImportSkipped, "already exists with the same details"
+				}
+				results = append(results, result)
+
+			case recordSongDetails:
+				var details musiclibrary.SongDetails
+				if err := json.Unmarshal(record.Data, &details); err != nil {
+					return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+				}
+				song, ok := songs[details.SongId]
+				if !ok {
+					return fmt.Errorf("%w: details %d belong to song %d, which is not in the archive", ErrInvalidArchive, details.Id, details.SongId)
+				}
+				if details.ReleaseDate != "" {
+					if _, err := time.Parse("2006-01-02", details.ReleaseDate); err != nil {
+						return fmt.Errorf("%w: song %d has an invalid release date %q", ErrInvalidArchive, details.SongId, details.ReleaseDate)
+					}
+				}
+				changed, err := w.SongDetails(song.id, details)
+				if err != nil {
+					return err
+				}
+				if changed && !song.created {
+					results[song.result].Status, results[song.result].Reason = repository.ImportUpdated, ""
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+var errArchiveTooLarge = fmt.Errorf("%w: too large once unpacked", ErrInvalidArchive)
+
+// archiveLimitReader fails with errArchiveTooLarge once more than n bytes
+// have been read, unlike io.LimitReader which just ends the input early.
+type archiveLimitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *archiveLimitReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// The archive may end right at the limit.
+		n, err := l.r.Read(make([]byte, 1))
+		if n > 0 {
+			return 0, errArchiveTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// testPostgresDSN names the environment variable with the URL of a Postgres
+// database, as for the repository contract. The archive round trip runs in
+// schemas of its own, which it drops when done.
+const testPostgresDSN = "TEST_POSTGRES_DSN"
+
+func TestMain(m *testing.M) {
+	logrus.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// fakeImport restores archives into maps, names are matched ignoring case
+// like the database does.
+type fakeImport struct {
+	repository.Import
+	groups  map[string]int
+	songs   map[string]int
+	details map[int]musiclibrary.SongDetails
+}
+
+func newFakeImport() *fakeImport {
+	return &fakeImport{groups: map[string]int{}, songs: map[string]int{}, details: map[int]musiclibrary.SongDetails{}}
+}
+
+func (f *fakeImport) RestoreArchive(ctx context.Context, dryRun bool, fn func(w repository.ArchiveRestorer) error) error {
+	return fn(f)
+}
+
+func (f *fakeImport) Group(name string) (int, bool, error) {
+	key := strings.ToLower(name)
+	if id, ok := f.groups[key]; ok {
+		return id, false, nil
+	}
+	f.groups[key] = len(f.groups) + 1
+	return f.groups[key], true, nil
+}
+
+func (f *fakeImport) Song(groupId int, name string) (int, bool, error) {
+	key := fmt.Sprintf("%d/%s", groupId, strings.ToLower(name))
+	if id, ok := f.songs[key]; ok {
+		return id, false, nil
+	}
+	f.songs[key] = len(f.songs) + 1
+	return f.songs[key], true, nil
+}
+
+func (f *fakeImport) SongDetails(songId int, details musiclibrary.SongDetails) (bool, error) {
+	details.Id, details.SongId = 0, songId
+	changed := f.details[songId] != details
+	f.details[songId] = details
+	return changed, nil
+}
+
+func archive(t *testing.T, records ...exportRecord) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	records = append([]exportRecord{{Type: recordHeader, Data: archiveHeader{Format: "music-library", Version: ArchiveVersion}}}, records...)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRestoreArchive(t *testing.T) {
+	repo := newFakeImport()
+	repo.groups["queen"] = 1
+	repo.songs["1/bohemian rhapsody"] = 1
+	repo.details[1] = musiclibrary.SongDetails{SongId: 1, Text: "N/A", Link: "N/A"}
+
+	data := archive(t,
+		exportRecord{Type: recordGroup, Data: musiclibrary.Group{Id: 7, GroupName: "Queen"}},
+		exportRecord{Type: recordGroup, Data: musiclibrary.Group{Id: 8, GroupName: "Muse"}},
+		exportRecord{Type: recordSong, Data: musiclibrary.Song{Id: 3, SongName: "Bohemian Rhapsody", GroupId: 7}},
+		exportRecord{Type: recordSong, Data: musiclibrary.Song{Id: 4, SongName: "Uprising", GroupId: 8}},
+		exportRecord{Type: recordSongDetails, Data: musiclibrary.SongDetails{Id: 1, SongId: 3, ReleaseDate: "1975-10-31"}},
+		exportRecord{Type: recordSongDetails, Data: musiclibrary.SongDetails{Id: 2, SongId: 4, Text: "Paranoia is in bloom"}},
+	)
+	report, err := NewImportService(repo).Import(context.Background(), ImportFormatArchive, bytes.NewReader(data), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 2 || report.Updated != 1 || report.Skipped != 1 || report.Errors != 0 {
+		t.Errorf("report = %+v, want 2 created, 1 updated and 1 skipped", report)
+	}
+	// The empty text and link of the existing song are cleared.
+	if got, want := repo.details[1], (musiclibrary.SongDetails{SongId: 1, ReleaseDate: "1975-10-31"}); got != want {
+		t.Errorf("details = %+v, want %+v", got, want)
+	}
+}
+
+func TestRestoreArchiveRejectsNamesAlike(t *testing.T) {
+	data := archive(t,
+		exportRecord{Type: recordGroup, Data: musiclibrary.Group{Id: 1, GroupName: "Queen"}},
+		exportRecord{Type: recordGroup, Data: musiclibrary.Group{Id: 2, GroupName: "QUEEN"}},
+	)
+	_, err := NewImportService(newFakeImport()).Import(context.Background(), ImportFormatArchive, bytes.NewReader(data), false)
+	if !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidArchive)
+	}
+}
+
+func TestRestoreArchiveTooLarge(t *testing.T) {
+	defer func(size int64) { maxArchiveSize = size }(maxArchiveSize)
+	maxArchiveSize = 1 << 20
+
+	// A few kilobytes of gzip that unpack to far more than the limit.
+	text := strings.Repeat("la ", 1<<20)
+	data := archive(t,
+		exportRecord{Type: recordGroup, Data: musiclibrary.Group{Id: 1, GroupName: "Queen"}},
+		exportRecord{Type: recordSong, Data: musiclibrary.Song{Id: 1, SongName: "Bohemian Rhapsody", GroupId: 1}},
+		exportRecord{Type: recordSongDetails, Data: musiclibrary.SongDetails{Id: 1, SongId: 1, Text: text}},
+	)
+	repo := newFakeImport()
+	_, err := NewImportService(repo).Import(context.Background(), ImportFormatArchive, bytes.NewReader(data), false)
+	if !errors.Is(err, errArchiveTooLarge) {
+		t.Fatalf("err = %v, want %v", err, errArchiveTooLarge)
+	}
+	if len(repo.details) != 0 {
+		t.Error("details of a too large archive were restored")
+	}
+}
+
+// TestArchiveRoundTrip exports a library as an archive, restores it into an
+// empty database and checks that exporting that gives the same library. It
+// then changes and clears details in the copy and checks that restoring the
+// archive again undoes that.
+func TestArchiveRoundTrip(t *testing.T) {
+	dsn := os.Getenv(testPostgresDSN)
+	if dsn == "" {
+		t.Skipf("%s is not set", testPostgresDSN)
+	}
+	ctx := context.Background()
+	source := repository.NewRepository(emptyPostgres(t, dsn, "archive_source"))
+	target := repository.NewRepository(emptyPostgres(t, dsn, "archive_target"))
+
+	queen, err := source.CreateGroup(ctx, musiclibrary.Group{GroupName: "Queen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.CreateGroup(ctx, musiclibrary.Group{GroupName: "Group Without Songs"}); err != nil {
+		t.Fatal(err)
+	}
+	details := []musiclibrary.UpdateSongDetailsInput{
+		{ReleaseDate: "1975-10-31", Text: "Is this the real life?\n\nIs this just fantasy?", Link: "https://example.com/bohemian"},
+		{Text: "We will, we will rock you"},
+		{},
+	}
+	for i, input := range details {
+		id, err := source.CreateSong(ctx, musiclibrary.Song{SongName: fmt.Sprintf("Song %d", i+1), GroupId: queen})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := source.RestoreSongDetails(ctx, id, input); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := exportArchive(t, source)
+
+	restore := func() {
+		t.Helper()
+		report, err := NewImportService(target.Import).Import(ctx, ImportFormatArchive, bytes.NewReader(want), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Errors > 0 {
+			t.Fatalf("restore reported errors: %+v", report.Rows)
+		}
+		if got := exportArchive(t, target); !reflect.DeepEqual(librarySnapshot(t, got), librarySnapshot(t, want)) {
+			t.Fatalf("restored library = %v, want %v", librarySnapshot(t, got), librarySnapshot(t, want))
+		}
+	}
+	restore()
+
+	songs, err := target.GetAllSongs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, song := range songs {
+		err := target.RestoreSongDetails(ctx, song.Id, musiclibrary.UpdateSongDetailsInput{ReleaseDate: "2000-01-01", Text: "changed", Link: "N/A"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	restore()
+}
+
+func exportArchive(t *testing.T, repos *repository.Repository) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := NewExportService(repos.Export).Export(context.Background(), ExportFormatArchive, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// librarySnapshot lists the groups and songs of an archive by name, ids
+// differ between databases.
+func librarySnapshot(t *testing.T, data []byte) []string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := map[int]string{}
+	songs := map[int]string{}
+	var snapshot []string
+	dec := json.NewDecoder(gz)
+	for {
+		var record archiveRecord
+		if err := dec.Decode(&record); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		switch record.Type {
+		case recordGroup:
+			var group musiclibrary.Group
+			json.Unmarshal(record.Data, &group)
+			groups[group.Id] = group.GroupName
+			snapshot = append(snapshot, "group "+group.GroupName)
+		case recordSong:
+			var song musiclibrary.Song
+			json.Unmarshal(record.Data, &song)
+			songs[song.Id] = groups[song.GroupId] + " / " + song.SongName
+		case recordSongDetails:
+			var d musiclibrary.SongDetails
+			json.Unmarshal(record.Data, &d)
+			snapshot = append(snapshot, fmt.Sprintf("song %s: %q %q %q", songs[d.SongId], d.ReleaseDate, d.Text, d.Link))
+		}
+	}
+	sort.Strings(snapshot)
+	return snapshot
+}
+
+// emptyPostgres migrates a new schema of the database at dsn and returns a
+// connection that works in it.
+func emptyPostgres(t *testing.T, dsn, schema string) *sqlx.DB {
+	t.Helper()
+	admin, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	if _, err := admin.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %[1]s CASCADE; CREATE SCHEMA %[1]s", schema)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if admin, err := sqlx.Connect("postgres", dsn); err == nil {
+			admin.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+			admin.Close()
+		}
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// public stays on the path for extensions installed there, pg_trgm.
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	u.RawQuery = query.Encode()
+
+	m, err := migrate.New("file://../../migrations", u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatal(err)
+	}
+	db, err := sqlx.Connect("postgres", u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+)
+
+// Related resources that can be embedded with include.
+const (
+	IncludeSongs   = "songs"
+	IncludeGroup   = "group"
+	IncludeDetails = "details"
+	IncludeAlbums  = "albums"
+)
+
+// parseInclude checks that every requested resource is one of allowed and
+// returns them as a set.
+func parseInclude(include []string, allowed ...string) (map[string]bool, error) {
+	set := make(map[string]bool, len(include))
+	for _, name := range include {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, a := range allowed {
+			if name == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, musiclibrary.NewValidationError("include",
+				fmt.Sprintf("unknown resource %q, must be one of %s", name, strings.Join(allowed, ", ")))
+		}
+		set[name] = true
+	}
+	return set, nil
+}
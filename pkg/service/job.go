@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+const JobRefreshSongDetails = "refreshSongDetails"
+
+const (
+	jobMaxAttempts = 5
+	// jobLease must outlast jobTimeout, otherwise a slow job is handed to a
+	// second worker while the first one is still on it.
+	jobLease      = 2 * time.Minute
+	jobTimeout    = time.Minute
+	jobBackoff    = 10 * time.Second
+	jobMaxBackoff = 30 * time.Minute
+	jobPoll       = time.Second
+)
+
+var (
+	ErrUnknownJobStatus = musiclibrary.NewValidationError("status", "must be queued, running, done or dead")
+	ErrJobNotDead       = musiclibrary.NewConflictError("only dead jobs can be retried")
+	ErrSongInfoDisabled = errors.New("song info API is not configured")
+)
+
+// JobHandler runs one job. Returning an error retries the job later unless
+// the error is wrapped with permanent.
+type JobHandler func(ctx context.Context, payload []byte) error
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// permanent marks a job error that retrying cannot fix, the job is
+// dead-lettered right away.
+func permanent(err error) error {
+	return permanentError{err}
+}
+
+type JobService struct {
+	repo     repository.Job
+	handlers map[string]JobHandler
+}
+
+func NewJobService(repo repository.Job) *JobService {
+	return &JobService{repo: repo, handlers: map[string]JobHandler{}}
+}
+
+// Handle registers the handler workers use for jobType.
+func (s *JobService) Handle(jobType string, handler JobHandler) {
+	s.handlers[jobType] = handler
+}
+
+func (s *JobService) EnqueueRefreshSongDetails(ctx context.Context, songId int) (int, error) {
+	if s.handlers[JobRefreshSongDetails] == nil {
+		return 0, ErrSongInfoDisabled
+	}
+	payload, err := json.Marshal(refreshSongDetailsPayload{SongId: songId})
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.EnqueueJob(ctx, JobRefreshSongDetails, payload, jobMaxAttempts)
+}
+
+func (s *JobService) GetJobs(ctx context.Context, status string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Job], error) {
+	switch status {
+	case "", repository.JobQueued, repository.JobRunning, repository.JobDone, repository.JobDead:
+	default:
+		return musiclibrary.Page[musiclibrary.Job]{}, ErrUnknownJobStatus
+	}
+	return s.repo.GetJobs(ctx, status, page)
+}
+
+func (s *JobService) GetJobById(ctx context.Context, id int) (musiclibrary.Job, error) {
+	return s.repo.GetJobById(ctx, id)
+}
+
+// RetryJob queues a dead job again.
+func (s *JobService) RetryJob(ctx context.Context, id int) error {
+	err := s.repo.RequeueJob(ctx, id)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if _, err := s.repo.GetJobById(ctx, id); err != nil {
+		return err
+	}
+	return ErrJobNotDead
+}
+
+// StartWorkers runs a pool of workers taking jobs from the queue until ctx
+// is cancelled.
+func (s *JobService) StartWorkers(ctx context.Context, workers int) {
+	logrus.WithField("workers", workers).Info("Starting job workers")
+	for i := 0; i < workers; i++ {
+		go s.work(ctx)
+	}
+}
+
+func (s *JobService) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := s.repo.ClaimJob(ctx, jobLease)
+		if err != nil {
+			// Either the queue is empty or the database is unreachable,
+			// both are worth a pause before trying again.
+			select {
+			case <-time.After(jobPoll):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		s.run(ctx, job)
+	}
+}
+
+func (s *JobService) run(ctx context.Context, job musiclibrary.Job) {
+	log := logrus.WithFields(logrus.Fields{
+		"job_id":  job.Id,
+		"type":    job.Type,
+		"attempt": job.Attempts,
+	})
+	log.Debug("Running job")
+
+	err := s.call(ctx, job)
+	// The outcome is recorded even when the workers are shutting down,
+	// otherwise the job would wait for its lease to run out.
+	ctx = context.WithoutCancel(ctx)
+	if err := s.finish(ctx, log, job, err); errors.Is(err, repository.ErrJobLost) {
+		log.Warn("Job lease ran out before it finished, its outcome is dropped")
+	}
+}
+
+// finish records the outcome of a job run, fenced by the claim it was
+// handed out with.
+func (s *JobService) finish(ctx context.Context, log *logrus.Entry, job musiclibrary.Job, err error) error {
+	if err == nil {
+		return s.repo.CompleteJob(ctx, job.Id, job.Claims)
+	}
+
+	log.WithError(err).Warn("Job failed")
+	var perm permanentError
+	if errors.As(err, &perm) || job.Attempts >= job.MaxAttempts {
+		return s.repo.DeadLetterJob(ctx, job.Id, job.Claims, err.Error())
+	}
+	return s.repo.RescheduleJob(ctx, job.Id, job.Claims, err.Error(), jobRetryDelay(job.Attempts))
+}
+
+func (s *JobService) call(ctx context.Context, job musiclibrary.Job) (err error) {
+	handler := s.handlers[job.Type]
+	if handler == nil {
+		return permanent(fmt.Errorf("no handler for job type %q", job.Type))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+	return handler(ctx, job.Payload)
+}
+
+// jobRetryDelay doubles the wait after every failed attempt: 10s, 20s, 40s...
+func jobRetryDelay(attempts int) time.Duration {
+	delay := jobBackoff
+	for i := 1; i < attempts && delay < jobMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, jobMaxBackoff)
+}
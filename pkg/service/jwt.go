@@ -0,0 +1,63 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+	musiclibrary "time-tracker"
+)
+
+var ErrInvalidToken = &musiclibrary.Error{Kind: musiclibrary.ErrUnauthorized, Message: "invalid or expired token"}
+
+// jwtHeader is the only header tokens are signed with and accepted with,
+// so a token cannot pick its own algorithm.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type tokenClaims struct {
+	Subject   int               `json:"sub"`
+	Name      string            `json:"name"`
+	Role      musiclibrary.Role `json:"role"`
+	IssuedAt  int64             `json:"iat"`
+	ExpiresAt int64             `json:"exp"`
+}
+
+// signToken makes a JWT signed with HMAC-SHA256.
+func signToken(claims tokenClaims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + jwtSignature(unsigned, secret), nil
+}
+
+// parseToken verifies a token made by signToken and returns its claims
+// unless it has expired.
+func parseToken(token string, secret []byte, now time.Time) (tokenClaims, error) {
+	var claims tokenClaims
+	header, rest, ok := strings.Cut(token, ".")
+	if !ok || header != jwtHeader {
+		return claims, ErrInvalidToken
+	}
+	payload, signature, ok := strings.Cut(rest, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(jwtSignature(header+"."+payload, secret))) {
+		return claims, ErrInvalidToken
+	}
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || json.Unmarshal(b, &claims) != nil {
+		return claims, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return claims, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func jwtSignature(unsigned string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+type LibraryService struct {
+	repo repository.Library
+}
+
+func NewLibraryService(repo repository.Library) *LibraryService {
+	return &LibraryService{repo: repo}
+}
+
+func (s *LibraryService) AddFavorite(ctx context.Context, userId, songId int) error {
+	return s.repo.AddFavorite(ctx, userId, songId)
+}
+
+func (s *LibraryService) RemoveFavorite(ctx context.Context, userId, songId int) error {
+	return s.repo.RemoveFavorite(ctx, userId, songId)
+}
+
+func (s *LibraryService) GetFavorites(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Favorite], error) {
+	return s.repo.GetFavorites(ctx, userId, page)
+}
+
+func (s *LibraryService) RateSong(ctx context.Context, userId, songId int, input musiclibrary.RatingInput) error {
+	return s.repo.RateSong(ctx, userId, songId, input.Rating)
+}
+
+func (s *LibraryService) RemoveRating(ctx context.Context, userId, songId int) error {
+	return s.repo.RemoveRating(ctx, userId, songId)
+}
+
+func (s *LibraryService) GetRatings(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Rating], error) {
+	return s.repo.GetRatings(ctx, userId, page)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	musiclibrary "time-tracker"
+)
+
+var (
+	ErrInvalidLrc      = &musiclibrary.Error{Kind: musiclibrary.ErrValidation, Message: "LRC contains no timestamped lines"}
+	ErrInvalidPosition = musiclibrary.NewValidationError("at", "must be mm:ss.xx or seconds")
+)
+
+var (
+	lrcTimestamp = regexp.MustCompile(`^\[(\d+):(\d{1,2}(?:[.:]\d{1,3})?)\]`)
+	lrcTag       = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+	lrcWord      = regexp.MustCompile(`<(\d+):(\d{1,2}(?:[.:]\d{1,3})?)>`)
+)
+
+// parseLrc reads simple and enhanced (word-level <mm:ss.xx>) LRC. Lines with
+// several timestamps are repeated at each of them and the [offset:] tag is
+// applied, so the result is sorted by the time a line should be shown.
+func parseLrc(lrc string) (musiclibrary.SyncedLyrics, error) {
+	lyrics := musiclibrary.SyncedLyrics{Tags: map[string]string{}}
+	offset := 0
+	for _, raw := range strings.Split(strings.ReplaceAll(lrc, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		var times []int
+		for {
+			m := lrcTimestamp.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+			times = append(times, lrcMillis(m[1], m[2]))
+			line = line[len(m[0]):]
+		}
+
+		if len(times) == 0 {
+			if m := lrcTag.FindStringSubmatch(line); m != nil {
+				key := strings.ToLower(m[1])
+				lyrics.Tags[key] = strings.TrimSpace(m[2])
+				if key == "offset" {
+					offset, _ = strconv.Atoi(strings.TrimPrefix(lyrics.Tags[key], "+"))
+				}
+			}
+			continue
+		}
+
+		text, words := parseLrcWords(line)
+		for _, t := range times {
+			lyrics.Lines = append(lyrics.Lines, musiclibrary.SyncedLine{TimeMs: t, Text: text, Words: words})
+		}
+	}
+
+	if len(lyrics.Lines) == 0 {
+		return lyrics, ErrInvalidLrc
+	}
+
+	// A positive offset makes the lyrics appear sooner.
+	for i := range lyrics.Lines {
+		lyrics.Lines[i].TimeMs = max(lyrics.Lines[i].TimeMs-offset, 0)
+		lyrics.Lines[i].Time = formatLrcTime(lyrics.Lines[i].TimeMs)
+		words := make([]musiclibrary.SyncedWord, len(lyrics.Lines[i].Words))
+		for j, w := range lyrics.Lines[i].Words {
+			w.TimeMs = max(w.TimeMs-offset, 0)
+			w.Time = formatLrcTime(w.TimeMs)
+			words[j] = w
+		}
+		if len(words) > 0 {
+			lyrics.Lines[i].Words = words
+		}
+	}
+	delete(lyrics.Tags, "offset")
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool {
+		return lyrics.Lines[i].TimeMs < lyrics.Lines[j].TimeMs
+	})
+	return lyrics, nil
+}
+
+func parseLrcWords(line string) (string, []musiclibrary.SyncedWord) {
+	marks := lrcWord.FindAllStringSubmatchIndex(line, -1)
+	if marks == nil {
+		return strings.TrimSpace(line), nil
+	}
+	words := make([]musiclibrary.SyncedWord, 0, len(marks))
+	for i, m := range marks {
+		end := len(line)
+		if i+1 < len(marks) {
+			end = marks[i+1][0]
+		}
+		word := strings.TrimSpace(line[m[1]:end])
+		if word == "" {
+			continue
+		}
+		words = append(words, musiclibrary.SyncedWord{
+			TimeMs: lrcMillis(line[m[2]:m[3]], line[m[4]:m[5]]),
+			Text:   word,
+		})
+	}
+	text := strings.Join(strings.Fields(lrcWord.ReplaceAllString(line, " ")), " ")
+	return text, words
+}
+
+// formatLrc renders lyrics back to LRC, dropping the word timings unless
+// enhanced is set.
+func formatLrc(lyrics musiclibrary.SyncedLyrics, enhanced bool) string {
+	var b strings.Builder
+	keys := make([]string, 0, len(lyrics.Tags))
+	for key := range lyrics.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "[%s:%s]\n", key, lyrics.Tags[key])
+	}
+	for _, line := range lyrics.Lines {
+		fmt.Fprintf(&b, "[%s]", line.Time)
+		if enhanced && len(line.Words) > 0 {
+			for i, w := range line.Words {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				fmt.Fprintf(&b, "<%s>%s", w.Time, w.Text)
+			}
+		} else {
+			b.WriteString(line.Text)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// syncedPosition finds the line active at position at and the next lines after it.
+func syncedPosition(lyrics musiclibrary.SyncedLyrics, at, next int) musiclibrary.SyncedPosition {
+	position := musiclibrary.SyncedPosition{At: formatLrcTime(at), Next: []musiclibrary.SyncedLine{}}
+	i := sort.Search(len(lyrics.Lines), func(i int) bool {
+		return lyrics.Lines[i].TimeMs > at
+	})
+	if i > 0 {
+		current := lyrics.Lines[i-1]
+		position.Current = &current
+	}
+	end := min(i+next, len(lyrics.Lines))
+	position.Next = append(position.Next, lyrics.Lines[i:end]...)
+	return position
+}
+
+// parsePosition accepts a playback position as mm:ss.xx or plain seconds.
+func parsePosition(at string) (int, error) {
+	at = strings.TrimSpace(at)
+	if minutes, seconds, ok := strings.Cut(at, ":"); ok {
+		m, err := strconv.Atoi(minutes)
+		if err != nil || m < 0 {
+			return 0, ErrInvalidPosition
+		}
+		s, err := strconv.ParseFloat(seconds, 64)
+		if err != nil || s < 0 || s >= 60 {
+			return 0, ErrInvalidPosition
+		}
+		return m*60000 + int(s*1000+0.5), nil
+	}
+	s, err := strconv.ParseFloat(at, 64)
+	if err != nil || s < 0 {
+		return 0, ErrInvalidPosition
+	}
+	return int(s*1000 + 0.5), nil
+}
+
+func lrcMillis(minutes, seconds string) int {
+	m, _ := strconv.Atoi(minutes)
+	sec, frac, _ := strings.Cut(strings.Replace(seconds, ":", ".", 1), ".")
+	s, _ := strconv.Atoi(sec)
+	ms := 0
+	if frac != "" {
+		ms, _ = strconv.Atoi((frac + "00")[:3])
+	}
+	return m*60000 + s*1000 + ms
+}
+
+func formatLrcTime(ms int) string {
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, ms/1000%60, ms%1000/10)
+}
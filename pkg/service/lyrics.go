@@ -0,0 +1,107 @@
+package service
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	musiclibrary "time-tracker"
+)
+
+var ErrInvalidSectionType = musiclibrary.NewValidationError("type", "unknown lyrics section type")
+
+// sectionTypes are the section kinds a header such as [Verse 2] or
+// [Pre-Chorus] is classified as. Anything else is reported as "other".
+var sectionTypes = map[string]bool{
+	"intro":        true,
+	"verse":        true,
+	"pre-chorus":   true,
+	"chorus":       true,
+	"refrain":      true,
+	"hook":         true,
+	"bridge":       true,
+	"interlude":    true,
+	"instrumental": true,
+	"outro":        true,
+	"other":        true,
+}
+
+var sectionHeader = regexp.MustCompile(`^\[([^\]]+)\]$`)
+var sectionName = regexp.MustCompile(`^(.*?)\s*(\d+)?$`)
+
+// parseSections splits lyrics into typed sections using the [Header] lines
+// in the text. Lyrics without any headers are treated as numbered verses
+// separated by blank lines.
+func parseSections(text string) []musiclibrary.LyricsSection {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if !hasSectionHeaders(text) {
+		return paragraphSections(text)
+	}
+
+	var sections []musiclibrary.LyricsSection
+	var current *musiclibrary.LyricsSection
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if m := sectionHeader.FindStringSubmatch(line); m != nil {
+			sections = append(sections, newSection(m[1]))
+			current = &sections[len(sections)-1]
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if current == nil {
+			sections = append(sections, musiclibrary.LyricsSection{Type: "other"})
+			current = &sections[len(sections)-1]
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	return sections
+}
+
+func hasSectionHeaders(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if sectionHeader.MatchString(strings.TrimSpace(line)) {
+			return true
+		}
+	}
+	return false
+}
+
+// newSection classifies a header label. "Verse 2: Freddie Mercury" becomes a
+// verse numbered 2, the label is kept as written.
+func newSection(label string) musiclibrary.LyricsSection {
+	section := musiclibrary.LyricsSection{Type: "other", Label: label, Lines: []string{}}
+	name := strings.TrimSpace(strings.SplitN(label, ":", 2)[0])
+	m := sectionName.FindStringSubmatch(strings.ToLower(name))
+	kind := strings.Join(strings.Fields(strings.ReplaceAll(m[1], "-", " ")), "-")
+	if sectionTypes[kind] {
+		section.Type = kind
+	}
+	if m[2] != "" {
+		section.Number, _ = strconv.Atoi(m[2])
+	}
+	return section
+}
+
+func paragraphSections(text string) []musiclibrary.LyricsSection {
+	var sections []musiclibrary.LyricsSection
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		var lines []string
+		for _, line := range strings.Split(paragraph, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		number := len(sections) + 1
+		sections = append(sections, musiclibrary.LyricsSection{
+			Type:   "verse",
+			Number: number,
+			Label:  "Verse " + strconv.Itoa(number),
+			Lines:  lines,
+		})
+	}
+	return sections
+}
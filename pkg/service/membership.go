@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+var ErrInvalidDate = musiclibrary.NewValidationError("at", "must be YYYY, YYYY-MM or YYYY-MM-DD")
+
+type MembershipService struct {
+	repo repository.Membership
+}
+
+func NewMembershipService(repo repository.Membership) *MembershipService {
+	return &MembershipService{repo: repo}
+}
+
+func (s *MembershipService) AddMember(ctx context.Context, groupId int, input musiclibrary.MembershipInput) (int, error) {
+	return s.repo.AddMember(ctx, groupId, input)
+}
+
+func (s *MembershipService) RemoveMember(ctx context.Context, groupId, membershipId int) error {
+	return s.repo.RemoveMember(ctx, groupId, membershipId)
+}
+
+// GetGroupMembers lists the members of a group. A non-empty at narrows the
+// list to the people who played in the group at some point of that year,
+// month or day.
+func (s *MembershipService) GetGroupMembers(ctx context.Context, groupId int, at string) ([]musiclibrary.Membership, error) {
+	if at == "" {
+		return s.repo.GetGroupMembers(ctx, groupId, "", "")
+	}
+	from, to, err := dateRange(at)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetGroupMembers(ctx, groupId, from, to)
+}
+
+func (s *MembershipService) GetArtistGroups(ctx context.Context, artistId int) ([]musiclibrary.Membership, error) {
+	return s.repo.GetArtistGroups(ctx, artistId)
+}
+
+// dateRange expands a partial date into the first and last day it covers.
+func dateRange(at string) (string, string, error) {
+	const layout = "2006-01-02"
+	if t, err := time.Parse("2006", at); err == nil {
+		return t.Format(layout), t.AddDate(1, 0, -1).Format(layout), nil
+	}
+	if t, err := time.Parse("2006-01", at); err == nil {
+		return t.Format(layout), t.AddDate(0, 1, -1).Format(layout), nil
+	}
+	if t, err := time.Parse(layout, at); err == nil {
+		return t.Format(layout), t.Format(layout), nil
+	}
+	return "", "", ErrInvalidDate
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+const (
+	// scrobbleAfter is how long, in seconds, a play must last to count as a
+	// scrobble, unless half the song is shorter.
+	scrobbleAfter = 4 * 60
+	// maxPlays caps the plays of one submission.
+	maxPlays = 1000
+	// playClockSkew is how far in the future plays may be reported, to
+	// allow for the clocks of clients running ahead.
+	playClockSkew = 5 * time.Minute
+
+	defaultStatsLimit  = 10
+	maxStatsLimit      = 100
+	defaultStatsWindow = 30 * 24 * time.Hour
+	// maxDailyStatsDays caps the days listed by GetPlaysPerDay.
+	maxDailyStatsDays = 366
+
+	// playedAtLayout is the format plays are stored and compared in.
+	playedAtLayout = "2006-01-02T15:04:05"
+)
+
+var ErrTooManyPlays = musiclibrary.NewValidationError("plays", fmt.Sprintf("must be at most %d at once", maxPlays))
+
+type PlayService struct {
+	repo repository.Play
+}
+
+func NewPlayService(repo repository.Play) *PlayService {
+	return &PlayService{repo: repo}
+}
+
+// RecordPlays records plays of a user, such as a batch saved while offline.
+// Invalid plays are reported as errors and never reach the database, the rest
+// are recorded in one transaction. Plays submitted before are reported as
+// duplicates, so a failed submission can safely be sent again.
+func (s *PlayService) RecordPlays(ctx context.Context, userId int, inputs []musiclibrary.PlayInput) (musiclibrary.PlayReport, error) {
+	if len(inputs) > maxPlays {
+		return musiclibrary.PlayReport{}, ErrTooManyPlays
+	}
+
+	now := time.Now().UTC()
+	results := make([]musiclibrary.PlayResult, len(inputs))
+	var plays []musiclibrary.Play
+	var indexes []int
+	for i, input := range inputs {
+		play, reason := newPlay(userId, input, now)
+		if reason != "" {
+			results[i] = musiclibrary.PlayResult{Index: i, SongId: input.SongId, Status: repository.PlayError, Reason: reason}
+			continue
+		}
+		plays = append(plays, play)
+		indexes = append(indexes, i)
+	}
+
+	if len(plays) > 0 {
+		recorded, err := s.repo.RecordPlays(ctx, plays)
+		if err != nil {
+			return musiclibrary.PlayReport{}, err
+		}
+		for j, result := range recorded {
+			result.Index = indexes[j]
+			results[result.Index] = result
+		}
+	}
+
+	report := musiclibrary.PlayReport{Plays: results}
+	for _, result := range results {
+		switch result.Status {
+		case repository.PlayRecorded:
+			report.Recorded++
+			if result.Scrobble {
+				report.Scrobbles++
+			}
+		case repository.PlayDuplicate:
+			report.Duplicates++
+		default:
+			report.Errors++
+		}
+	}
+	return report, nil
+}
+
+// newPlay checks a play reported by a user and decides whether it is a
+// scrobble, returning why it is invalid otherwise.
+func newPlay(userId int, input musiclibrary.PlayInput, now time.Time) (musiclibrary.Play, string) {
+	if input.SongId < 1 {
+		return musiclibrary.Play{}, "songId is required"
+	}
+	if input.Duration < 0 || input.SongDuration < 0 {
+		return musiclibrary.Play{}, "duration and songDuration must not be negative"
+	}
+	if len(input.Client) > 255 {
+		return musiclibrary.Play{}, "client must be at most 255 characters"
+	}
+	playedAt := now
+	if input.PlayedAt != "" {
+		t, err := time.Parse(time.RFC3339, input.PlayedAt)
+		if err != nil {
+			return musiclibrary.Play{}, "playedAt must be an RFC 3339 timestamp"
+		}
+		if t.After(now.Add(playClockSkew)) {
+			return musiclibrary.Play{}, "playedAt must not be in the future"
+		}
+		playedAt = t.UTC()
+	}
+	return musiclibrary.Play{
+		UserId:       userId,
+		SongId:       input.SongId,
+		PlayedAt:     playedAt.Format(playedAtLayout),
+		Duration:     input.Duration,
+		SongDuration: input.SongDuration,
+		Client:       input.Client,
+		Scrobble:     isScrobble(input.Duration, input.SongDuration),
+	}, ""
+}
+
+// isScrobble applies the usual rule: a play counts once half the song or 4
+// minutes, whichever comes first, were listened to. Without the length of
+// the song only the 4 minutes can tell.
+func isScrobble(duration, songDuration int) bool {
+	return duration >= scrobbleAfter || (songDuration > 0 && 2*duration >= songDuration)
+}
+
+func (s *PlayService) GetTopSongs(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.SongStat, error) {
+	query, err := statsQuery(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetTopSongs(ctx, query)
+}
+
+func (s *PlayService) GetTopGroups(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.GroupStat, error) {
+	query, err := statsQuery(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetTopGroups(ctx, query)
+}
+
+// GetPlaysPerDay counts the scrobbles of every day of the window, days
+// without any included.
+func (s *PlayService) GetPlaysPerDay(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.DayStat, error) {
+	query, err := statsQuery(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	from, _ := time.Parse(playedAtLayout, query.From)
+	to, _ := time.Parse(playedAtLayout, query.To)
+	first := from.Truncate(24 * time.Hour)
+	last := to.Add(-time.Second).Truncate(24 * time.Hour)
+	if days := int(last.Sub(first).Hours()/24) + 1; days > maxDailyStatsDays {
+		return nil, musiclibrary.NewValidationError("from", fmt.Sprintf("must be at most %d days before to", maxDailyStatsDays))
+	}
+
+	counted, err := s.repo.GetPlaysPerDay(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	plays := make(map[string]int, len(counted))
+	for _, day := range counted {
+		plays[day.Day] = day.Plays
+	}
+	stats := []musiclibrary.DayStat{}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		stats = append(stats, musiclibrary.DayStat{Day: key, Plays: plays[key]})
+	}
+	return stats, nil
+}
+
+// statsQuery checks the window and limit of query and fills in the
+// defaults: the 30 days up to now and the top 10.
+func statsQuery(query musiclibrary.StatsQuery, now time.Time) (musiclibrary.StatsQuery, error) {
+	to := now.UTC()
+	if query.To != "" {
+		t, err := parseStatsTime(query.To)
+		if err != nil {
+			return query, musiclibrary.NewValidationError("to", "must be a date or an RFC 3339 timestamp")
+		}
+		to = t
+	}
+	from := to.Add(-defaultStatsWindow)
+	if query.From != "" {
+		t, err := parseStatsTime(query.From)
+		if err != nil {
+			return query, musiclibrary.NewValidationError("from", "must be a date or an RFC 3339 timestamp")
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return query, musiclibrary.NewValidationError("from", "must be before to")
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultStatsLimit
+	}
+	if query.Limit < 1 || query.Limit > maxStatsLimit {
+		return query, musiclibrary.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxStatsLimit))
+	}
+	// Plays are stored to the second, rounding To up keeps those of the
+	// second it falls in.
+	query.From = from.Format(playedAtLayout)
+	query.To = to.Add(time.Second - 1).Truncate(time.Second).Format(playedAtLayout)
+	return query, nil
+}
+
+// parseStatsTime reads a date, meaning its midnight in UTC, or an RFC 3339
+// timestamp.
+func parseStatsTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t.UTC(), err
+}
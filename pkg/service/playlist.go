@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"io"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+// errPlaylistNotFound is also returned for playlists of other users, whose
+// existence is nobody else's business.
+var errPlaylistNotFound = &musiclibrary.Error{Kind: musiclibrary.ErrNotFound, Message: "playlist not found"}
+
+// PlaylistService keeps the playlists of each user to themselves: every
+// method takes the user asking and only touches their playlists.
+type PlaylistService struct {
+	repo repository.Playlist
+}
+
+func NewPlaylistService(repo repository.Playlist) *PlaylistService {
+	return &PlaylistService{repo: repo}
+}
+
+func (s *PlaylistService) CreatePlaylist(ctx context.Context, userId int, playlist musiclibrary.Playlist) (int, error) {
+	playlist.UserId = userId
+	return s.repo.CreatePlaylist(ctx, playlist)
+}
+
+func (s *PlaylistService) GetPlaylists(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Playlist], error) {
+	return s.repo.GetPlaylists(ctx, userId, page)
+}
+
+func (s *PlaylistService) GetPlaylistById(ctx context.Context, userId, id int) (musiclibrary.PlaylistWithItems, error) {
+	playlist, err := s.ownPlaylist(ctx, userId, id)
+	if err != nil {
+		return musiclibrary.PlaylistWithItems{}, err
+	}
+	items, err := s.repo.GetPlaylistItems(ctx, id)
+	if err != nil {
+		return musiclibrary.PlaylistWithItems{}, err
+	}
+	return musiclibrary.PlaylistWithItems{Playlist: playlist, Items: items}, nil
+}
+
+func (s *PlaylistService) UpdatePlaylist(ctx context.Context, userId, id int, input musiclibrary.UpdatePlaylistInput) error {
+	if _, err := s.ownPlaylist(ctx, userId, id); err != nil {
+		return err
+	}
+	return s.repo.UpdatePlaylist(ctx, id, input)
+}
+
+func (s *PlaylistService) DeletePlaylist(ctx context.Context, userId, id int) error {
+	if _, err := s.ownPlaylist(ctx, userId, id); err != nil {
+		return err
+	}
+	return s.repo.DeletePlaylist(ctx, id)
+}
+
+// AddPlaylistItem adds a song at the position of the input, or at the end
+// without one, and returns the id of the new item.
+func (s *PlaylistService) AddPlaylistItem(ctx context.Context, userId, id int, input musiclibrary.PlaylistItemInput) (int, error) {
+	if _, err := s.ownPlaylist(ctx, userId, id); err != nil {
+		return 0, err
+	}
+	position := 0
+	if input.Position != nil {
+		position = *input.Position
+	}
+	return s.repo.InsertPlaylistItem(ctx, id, input.SongId, position)
+}
+
+func (s *PlaylistService) RemovePlaylistItem(ctx context.Context, userId, id, itemId int) error {
+	if _, err := s.ownPlaylist(ctx, userId, id); err != nil {
+		return err
+	}
+	return s.repo.RemovePlaylistItem(ctx, id, itemId)
+}
+
+func (s *PlaylistService) ReorderPlaylist(ctx context.Context, userId, id int, input musiclibrary.ReorderPlaylistInput) error {
+	if _, err := s.ownPlaylist(ctx, userId, id); err != nil {
+		return err
+	}
+	return s.repo.ReorderPlaylist(ctx, id, input.ItemIds)
+}
+
+// ExportPlaylist writes one of my playlists to w in format, with the links
+// of its songs as their locations.
+func (s *PlaylistService) ExportPlaylist(ctx context.Context, userId, id int, format string, w io.Writer) error {
+	playlist, err := s.ownPlaylist(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+	tracks, err := s.repo.GetPlaylistTracks(ctx, id)
+	if err != nil {
+		return err
+	}
+	return encodePlaylist(w, format, playlistFile{Title: playlist.Name, Annotation: playlist.Description, Tracks: tracks})
+}
+
+// ImportPlaylist reads a playlist file from r and creates a playlist of the
+// songs its entries match, in order, named name or else after the file.
+// Entries are matched by location against the links of songs, then by
+// artist and title against groups and songs, both compared by
+// normalizeName. An entry with only a title matches a song with that title
+// when there is just one. The report lists every entry and what it matched.
+func (s *PlaylistService) ImportPlaylist(ctx context.Context, userId int, format, name string, r io.Reader, dryRun bool) (musiclibrary.PlaylistImportReport, error) {
+	file, err := decodePlaylist(r, format)
+	if err != nil {
+		return musiclibrary.PlaylistImportReport{}, err
+	}
+	if name == "" {
+		name = file.Title
+	}
+	if name == "" {
+		return musiclibrary.PlaylistImportReport{}, musiclibrary.NewValidationError("name", "is required when the file has no title")
+	}
+
+	catalog, err := s.repo.GetTrackCatalog(ctx)
+	if err != nil {
+		return musiclibrary.PlaylistImportReport{}, err
+	}
+	match := newTrackMatcher(catalog)
+
+	report := musiclibrary.PlaylistImportReport{DryRun: dryRun, Name: name, Entries: []musiclibrary.PlaylistImportEntry{}}
+	var songIds []int
+	for i, track := range file.Tracks {
+		entry := musiclibrary.PlaylistImportEntry{
+			Entry: i + 1, Artist: track.Artist, Title: track.Title, Location: track.Location,
+			Status: playlistEntryUnmatched,
+		}
+		if songId, ok := match(track); ok {
+			entry.Status, entry.SongId = playlistEntryMatched, songId
+			songIds = append(songIds, songId)
+			report.Matched++
+		} else {
+			report.Unmatched++
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	playlist := musiclibrary.Playlist{UserId: userId, Name: name, Description: file.Annotation}
+	if report.PlaylistId, err = s.repo.ImportPlaylist(ctx, playlist, songIds); err != nil {
+		return musiclibrary.PlaylistImportReport{}, err
+	}
+	return report, nil
+}
+
+const (
+	playlistEntryMatched   = "matched"
+	playlistEntryUnmatched = "unmatched"
+)
+
+// newTrackMatcher indexes catalog for ImportPlaylist. Where several songs
+// share a link or a name, the one with the lowest id wins.
+func newTrackMatcher(catalog []musiclibrary.PlaylistTrack) func(track musiclibrary.PlaylistTrack) (int, bool) {
+	byLocation := map[string]int{}
+	byName := map[[2]string]int{}
+	byTitle := map[string][]int{}
+	for _, song := range catalog {
+		if _, ok := byLocation[song.Location]; song.Location != "" && !ok {
+			byLocation[song.Location] = song.SongId
+		}
+		key := [2]string{normalizeName(song.Artist), normalizeName(song.Title)}
+		if _, ok := byName[key]; !ok {
+			byName[key] = song.SongId
+		}
+		byTitle[key[1]] = append(byTitle[key[1]], song.SongId)
+	}
+
+	return func(track musiclibrary.PlaylistTrack) (int, bool) {
+		if id, ok := byLocation[track.Location]; track.Location != "" && ok {
+			return id, true
+		}
+		artist, title := normalizeName(track.Artist), normalizeName(track.Title)
+		if title == "" {
+			return 0, false
+		}
+		if artist != "" {
+			id, ok := byName[[2]string{artist, title}]
+			return id, ok
+		}
+		if ids := byTitle[title]; len(ids) == 1 {
+			return ids[0], true
+		}
+		return 0, false
+	}
+}
+
+func (s *PlaylistService) ownPlaylist(ctx context.Context, userId, id int) (musiclibrary.Playlist, error) {
+	playlist, err := s.repo.GetPlaylistById(ctx, id)
+	if err != nil {
+		return playlist, err
+	}
+	if playlist.UserId != userId {
+		return musiclibrary.Playlist{}, errPlaylistNotFound
+	}
+	return playlist, nil
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	musiclibrary "time-tracker"
+	"unicode"
+)
+
+const (
+	PlaylistFormatM3U8 = "m3u8"
+	PlaylistFormatXSPF = "xspf"
+	PlaylistFormatJSPF = "jspf"
+)
+
+const xspfNamespace = "http://xspf.org/ns/0/"
+
+var ErrUnknownPlaylistFormat = musiclibrary.NewValidationError("format", "must be m3u8, xspf or jspf")
+
+// playlistFile is what the playlist formats have in common: a title, a
+// description and tracks in order. The song ids of the tracks are not kept.
+type playlistFile struct {
+	Title      string
+	Annotation string
+	Tracks     []musiclibrary.PlaylistTrack
+}
+
+func encodePlaylist(w io.Writer, format string, file playlistFile) error {
+	switch format {
+	case PlaylistFormatM3U8:
+		return encodeM3U8(w, file)
+	case PlaylistFormatXSPF:
+		return encodeXSPF(w, file)
+	case PlaylistFormatJSPF:
+		return encodeJSPF(w, file)
+	}
+	return ErrUnknownPlaylistFormat
+}
+
+func decodePlaylist(r io.Reader, format string) (playlistFile, error) {
+	switch format {
+	case PlaylistFormatM3U8, PlaylistFormatXSPF, PlaylistFormatJSPF:
+	default:
+		return playlistFile{}, ErrUnknownPlaylistFormat
+	}
+	// Playlists are small, reading them whole keeps read errors, such as
+	// the body being too large, apart from malformed files.
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return playlistFile{}, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	var file playlistFile
+	switch format {
+	case PlaylistFormatM3U8:
+		file, err = decodeM3U8(data)
+	case PlaylistFormatXSPF:
+		file, err = decodeXSPF(data)
+	case PlaylistFormatJSPF:
+		file, err = decodeJSPF(data)
+	}
+	if err != nil {
+		return playlistFile{}, &musiclibrary.Error{
+			Kind:    musiclibrary.ErrValidation,
+			Message: fmt.Sprintf("not a valid %s playlist", strings.ToUpper(format)),
+			Err:     err,
+		}
+	}
+	return file, nil
+}
+
+// encodeM3U8 writes an extended M3U playlist. Every entry needs a location
+// line, songs without a link get "artist - title" instead, which players
+// treat as a missing file and decodeM3U8 matches by name.
+func encodeM3U8(w io.Writer, file playlistFile) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	if file.Title != "" {
+		fmt.Fprintf(bw, "#PLAYLIST:%s\n", oneLine(file.Title))
+	}
+	for _, track := range file.Tracks {
+		name := oneLine(joinArtistTitle(track.Artist, track.Title))
+		location := oneLine(track.Location)
+		if location == "" {
+			location = name
+		}
+		fmt.Fprintf(bw, "#EXTINF:-1,%s\n%s\n", name, location)
+	}
+	return bw.Flush()
+}
+
+// decodeM3U8 reads plain and extended M3U playlists. Entries without an
+// #EXTINF line take artist and title from the file name of their location.
+func decodeM3U8(data []byte) (playlistFile, error) {
+	var file playlistFile
+	var info *musiclibrary.PlaylistTrack
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			file.Title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			if info != nil {
+				file.Tracks = append(file.Tracks, *info)
+			}
+			// The duration and any attributes come before the first comma.
+			_, name, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			artist, title := splitArtistTitle(name)
+			info = &musiclibrary.PlaylistTrack{Artist: artist, Title: title}
+		case strings.HasPrefix(line, "#"):
+		default:
+			track := musiclibrary.PlaylistTrack{Location: line}
+			if info != nil {
+				track.Artist, track.Title = info.Artist, info.Title
+			} else {
+				track.Artist, track.Title = splitArtistTitle(locationName(line))
+			}
+			file.Tracks = append(file.Tracks, track)
+			info = nil
+		}
+	}
+	if info != nil {
+		file.Tracks = append(file.Tracks, *info)
+	}
+	return file, scanner.Err()
+}
+
+type xspfPlaylist struct {
+	XMLName    xml.Name `xml:"playlist"`
+	Xmlns      string   `xml:"xmlns,attr,omitempty"`
+	Version    string   `xml:"version,attr"`
+	Title      string   `xml:"title,omitempty"`
+	Annotation string   `xml:"annotation,omitempty"`
+	TrackList  struct {
+		Tracks []xspfTrack `xml:"track"`
+	} `xml:"trackList"`
+}
+
+type xspfTrack struct {
+	Locations []string `xml:"location"`
+	Creator   string   `xml:"creator,omitempty"`
+	Title     string   `xml:"title,omitempty"`
+}
+
+func encodeXSPF(w io.Writer, file playlistFile) error {
+	playlist := xspfPlaylist{Xmlns: xspfNamespace, Version: "1", Title: file.Title, Annotation: file.Annotation}
+	for _, track := range file.Tracks {
+		playlist.TrackList.Tracks = append(playlist.TrackList.Tracks, xspfTrack{
+			Locations: optional(track.Location),
+			Creator:   track.Artist,
+			Title:     track.Title,
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(playlist); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func decodeXSPF(data []byte) (playlistFile, error) {
+	var playlist xspfPlaylist
+	if err := xml.Unmarshal(data, &playlist); err != nil {
+		return playlistFile{}, err
+	}
+	file := playlistFile{Title: strings.TrimSpace(playlist.Title), Annotation: strings.TrimSpace(playlist.Annotation)}
+	for _, track := range playlist.TrackList.Tracks {
+		file.Tracks = append(file.Tracks, fileTrack(track.Creator, track.Title, track.Locations))
+	}
+	return file, nil
+}
+
+type jspfDocument struct {
+	Playlist jspfPlaylist `json:"playlist"`
+}
+
+type jspfPlaylist struct {
+	Title      string      `json:"title,omitempty"`
+	Annotation string      `json:"annotation,omitempty"`
+	Track      []jspfTrack `json:"track"`
+}
+
+type jspfTrack struct {
+	Location jspfLocations `json:"location,omitempty"`
+	Creator  string        `json:"creator,omitempty"`
+	Title    string        `json:"title,omitempty"`
+}
+
+// jspfLocations is a list of URIs as the JSPF draft has it, but also
+// accepts the single string some players write instead.
+type jspfLocations []string
+
+func (l *jspfLocations) UnmarshalJSON(data []byte) error {
+	var location string
+	if err := json.Unmarshal(data, &location); err == nil {
+		*l = optional(location)
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+func encodeJSPF(w io.Writer, file playlistFile) error {
+	playlist := jspfPlaylist{Title: file.Title, Annotation: file.Annotation, Track: []jspfTrack{}}
+	for _, track := range file.Tracks {
+		playlist.Track = append(playlist.Track, jspfTrack{
+			Location: optional(track.Location),
+			Creator:  track.Artist,
+			Title:    track.Title,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jspfDocument{Playlist: playlist})
+}
+
+func decodeJSPF(data []byte) (playlistFile, error) {
+	var document jspfDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return playlistFile{}, err
+	}
+	file := playlistFile{
+		Title:      strings.TrimSpace(document.Playlist.Title),
+		Annotation: strings.TrimSpace(document.Playlist.Annotation),
+	}
+	for _, track := range document.Playlist.Track {
+		file.Tracks = append(file.Tracks, fileTrack(track.Creator, track.Title, track.Location))
+	}
+	return file, nil
+}
+
+// fileTrack makes a track of an XSPF or JSPF entry, which may list several
+// locations of the same song. Only the first is kept.
+func fileTrack(creator, title string, locations []string) musiclibrary.PlaylistTrack {
+	track := musiclibrary.PlaylistTrack{Artist: strings.TrimSpace(creator), Title: strings.TrimSpace(title)}
+	if len(locations) > 0 {
+		track.Location = strings.TrimSpace(locations[0])
+	}
+	if track.Title == "" && track.Location != "" {
+		track.Artist, track.Title = splitArtistTitle(locationName(track.Location))
+	}
+	return track
+}
+
+func joinArtistTitle(artist, title string) string {
+	if artist == "" {
+		return title
+	}
+	return artist + " - " + title
+}
+
+// splitArtistTitle splits "artist - title" at the first dash, a name
+// without one is taken as the title alone.
+func splitArtistTitle(name string) (artist, title string) {
+	artist, title, ok := strings.Cut(name, " - ")
+	if !ok {
+		return "", strings.TrimSpace(name)
+	}
+	return strings.TrimSpace(artist), strings.TrimSpace(title)
+}
+
+// locationName is the file name of a path or URL without its extension.
+func locationName(location string) string {
+	if u, err := url.Parse(location); err == nil && len(u.Scheme) > 1 {
+		location = u.Path
+	} else {
+		location = strings.ReplaceAll(location, `\`, "/")
+	}
+	name := path.Base(location)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// normalizeName folds case and drops punctuation, so that "AC/DC" matches
+// "ac dc" and "Don't Stop Me Now" matches "Dont stop me now".
+func normalizeName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == '\'' || r == '’':
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// oneLine keeps s on a line of its own in an M3U file.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+func optional(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+type RevisionService struct {
+	repo    repository.Revision
+	details repository.SongDetails
+}
+
+func NewRevisionService(repo repository.Revision, details repository.SongDetails) *RevisionService {
+	return &RevisionService{repo: repo, details: details}
+}
+
+func (s *RevisionService) GetRevisions(ctx context.Context, songId int) ([]musiclibrary.Revision, error) {
+	return s.repo.GetRevisions(ctx, songId)
+}
+
+func (s *RevisionService) GetRevision(ctx context.Context, songId, revision int) (musiclibrary.Revision, error) {
+	return s.repo.GetRevision(ctx, songId, revision)
+}
+
+// DiffRevisions returns a unified diff between two revisions. Release date
+// and link are rendered as header lines above the lyrics so that changes to
+// them show up in the diff as well.
+func (s *RevisionService) DiffRevisions(ctx context.Context, songId, from, to int) (string, error) {
+	a, err := s.repo.GetRevision(ctx, songId, from)
+	if err != nil {
+		return "", err
+	}
+	b, err := s.repo.GetRevision(ctx, songId, to)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(
+		fmt.Sprintf("revision %d", from),
+		fmt.Sprintf("revision %d", to),
+		revisionLines(a), revisionLines(b),
+	), nil
+}
+
+// RollbackRevision restores the details of an earlier revision, clearing
+// those it did not have. The rollback is itself recorded as a new revision,
+// history is never rewritten.
+func (s *RevisionService) RollbackRevision(ctx context.Context, songId, revision int, author string) error {
+	rev, err := s.repo.GetRevision(ctx, songId, revision)
+	if err != nil {
+		return err
+	}
+	return s.details.RestoreSongDetails(ctx, songId, musiclibrary.UpdateSongDetailsInput{
+		ReleaseDate: rev.ReleaseDate,
+		Text:        rev.Text,
+		Link:        rev.Link,
+		Author:      author,
+		Comment:     fmt.Sprintf("Rollback to revision %d", revision),
+	})
+}
+
+func revisionLines(rev musiclibrary.Revision) []string {
+	lines := []string{
+		"releaseDate: " + rev.ReleaseDate,
+		"link: " + rev.Link,
+		"",
+	}
+	return append(lines, strings.Split(strings.ReplaceAll(rev.Text, "\r\n", "\n"), "\n")...)
+}
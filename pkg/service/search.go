@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"strings"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+type SearchService struct {
+	repo repository.Search
+}
+
+func NewSearchService(repo repository.Search) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) SearchLyrics(ctx context.Context, q string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.SearchResult], error) {
+	return s.repo.SearchLyrics(ctx, strings.TrimSpace(q), page)
+}
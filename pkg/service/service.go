@@ -1,42 +1,227 @@
 package service
 
 import (
+	"context"
+	"io"
 	musiclibrary "time-tracker"
 	"time-tracker/pkg/repository"
+	"time-tracker/pkg/songinfo"
 )
 
 type Group interface {
-	CreateGroup(group musiclibrary.Group) (int, error)
-	GetAllGroups() ([]musiclibrary.Group, error)
-	DeleteGroup(id int) error
-	UpdateGroup(id int, input musiclibrary.UpdateGroupInput) error
-	GetGroupsWithFilter(map[string]string, int, int) ([]musiclibrary.Group, error)
+	CreateGroup(ctx context.Context, group musiclibrary.Group) (int, error)
+	GetAllGroups(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Group], error)
+	GetGroupById(ctx context.Context, id int, include []string) (musiclibrary.GroupWithRelations, error)
+	DeleteGroup(ctx context.Context, id int) error
+	UpdateGroup(ctx context.Context, id int, input musiclibrary.UpdateGroupInput) error
+	GetGroupsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Group], error)
+}
+
+// SongInfo looks up the details of a song in an external catalogue, see
+// songinfo.Client.
+type SongInfo interface {
+	Fetch(ctx context.Context, group, song string) (songinfo.Info, error)
 }
 
 type Song interface {
-	CreateSong(song musiclibrary.Song) (int, error)
-	GetAllSongs() ([]musiclibrary.Song, error)
-	DeleteSong(id int) error
-	UpdateSong(id int, input musiclibrary.UpdateSongInput) error
-	GetSongsWithFilter(map[string]string, int, int) ([]musiclibrary.Song, error)
+	CreateSong(ctx context.Context, input musiclibrary.CreateSongInput) (int, error)
+	GetAllSongs(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Song], error)
+	GetSongById(ctx context.Context, id int, include []string) (musiclibrary.SongWithRelations, error)
+	DeleteSong(ctx context.Context, id int) error
+	UpdateSong(ctx context.Context, id int, input musiclibrary.UpdateSongInput) error
+	GetSongsWithFilter(ctx context.Context, filters map[string]string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Song], error)
 }
 
 type SongDetails interface {
-	GetSongDetailsById(songId int) ([]musiclibrary.SongDetails, error)
-	UpdateSongDetails(id int, input musiclibrary.UpdateSongDetailsInput) error
-	GetSongText(songId int, page int, limit int) ([]string, error)
+	GetSongDetailsById(ctx context.Context, songId int) ([]musiclibrary.SongDetails, error)
+	UpdateSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error
+	GetSongText(ctx context.Context, songId int, page int, limit int) ([]string, error)
+	GetSongSections(ctx context.Context, songId int, sectionType string) ([]musiclibrary.LyricsSection, error)
+	UploadLrc(ctx context.Context, songId int, lrc string) error
+	GetSyncedLyrics(ctx context.Context, songId int) (musiclibrary.SyncedLyrics, error)
+	GetSyncedPosition(ctx context.Context, songId int, at string, next int) (musiclibrary.SyncedPosition, error)
+	ExportLrc(ctx context.Context, songId int, enhanced bool) (string, error)
+}
+
+type Album interface {
+	CreateAlbum(ctx context.Context, album musiclibrary.Album) (int, error)
+	GetAllAlbums(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Album], error)
+	GetAlbumById(ctx context.Context, id int) (musiclibrary.Album, error)
+	DeleteAlbum(ctx context.Context, id int) error
+	UpdateAlbum(ctx context.Context, id int, input musiclibrary.UpdateAlbumInput) error
+	GetAlbumTracks(ctx context.Context, albumId int) ([]musiclibrary.AlbumTrack, error)
+	AddTrack(ctx context.Context, albumId int, input musiclibrary.AlbumTrackInput) error
+	RemoveTrack(ctx context.Context, albumId, songId int) error
+}
+
+type Artist interface {
+	CreateArtist(ctx context.Context, artist musiclibrary.Artist) (int, error)
+	GetAllArtists(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Artist], error)
+	GetArtistById(ctx context.Context, id int) (musiclibrary.Artist, error)
+	DeleteArtist(ctx context.Context, id int) error
+	UpdateArtist(ctx context.Context, id int, input musiclibrary.UpdateArtistInput) error
+}
+
+type Membership interface {
+	AddMember(ctx context.Context, groupId int, input musiclibrary.MembershipInput) (int, error)
+	RemoveMember(ctx context.Context, groupId, membershipId int) error
+	GetGroupMembers(ctx context.Context, groupId int, at string) ([]musiclibrary.Membership, error)
+	GetArtistGroups(ctx context.Context, artistId int) ([]musiclibrary.Membership, error)
+}
+
+type Genre interface {
+	CreateGenre(ctx context.Context, genre musiclibrary.Genre) (int, error)
+	GetAllGenres(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Genre], error)
+	DeleteGenre(ctx context.Context, id int) error
+	GetGenres(ctx context.Context, target musiclibrary.Taggable, targetId int) ([]musiclibrary.Genre, error)
+	AttachGenre(ctx context.Context, target musiclibrary.Taggable, targetId, genreId int) error
+	DetachGenre(ctx context.Context, target musiclibrary.Taggable, targetId, genreId int) error
+}
+
+type Tag interface {
+	GetAllTags(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Tag], error)
+	GetTags(ctx context.Context, target musiclibrary.Taggable, targetId int) ([]musiclibrary.Tag, error)
+	AttachTag(ctx context.Context, target musiclibrary.Taggable, targetId int, name string) error
+	DetachTag(ctx context.Context, target musiclibrary.Taggable, targetId int, name string) error
+}
+
+type Search interface {
+	SearchLyrics(ctx context.Context, q string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.SearchResult], error)
+}
+
+type Translation interface {
+	GetTranslations(ctx context.Context, songId int) ([]musiclibrary.Translation, error)
+	GetTranslation(ctx context.Context, songId int, lang string) (musiclibrary.Translation, error)
+	CreateTranslation(ctx context.Context, songId int, translation musiclibrary.Translation) (int, error)
+	UpdateTranslation(ctx context.Context, songId int, lang string, input musiclibrary.UpdateTranslationInput) error
+	DeleteTranslation(ctx context.Context, songId int, lang string) error
+	GetTranslatedText(ctx context.Context, songId int, lang string, page, limit int) ([]string, error)
+	GetParallelText(ctx context.Context, songId int, lang string, page, limit int) ([]musiclibrary.VersePair, error)
+}
+
+type Revision interface {
+	GetRevisions(ctx context.Context, songId int) ([]musiclibrary.Revision, error)
+	GetRevision(ctx context.Context, songId, revision int) (musiclibrary.Revision, error)
+	DiffRevisions(ctx context.Context, songId, from, to int) (string, error)
+	RollbackRevision(ctx context.Context, songId, revision int, author string) error
+}
+
+type Import interface {
+	Import(ctx context.Context, format string, r io.Reader, dryRun bool) (musiclibrary.ImportReport, error)
+}
+
+type Export interface {
+	Export(ctx context.Context, format string, w io.Writer) error
+}
+
+type Job interface {
+	EnqueueRefreshSongDetails(ctx context.Context, songId int) (int, error)
+	GetJobs(ctx context.Context, status string, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Job], error)
+	GetJobById(ctx context.Context, id int) (musiclibrary.Job, error)
+	RetryJob(ctx context.Context, id int) error
+	StartWorkers(ctx context.Context, workers int)
+}
+
+// User manages users and their API keys and issues and checks the access
+// tokens the API is used with.
+type User interface {
+	CreateUser(ctx context.Context, user musiclibrary.User) (int, error)
+	GetUsers(ctx context.Context) ([]musiclibrary.User, error)
+	CreateAPIKey(ctx context.Context, userId int, input musiclibrary.APIKeyInput) (musiclibrary.NewAPIKey, error)
+	GetAPIKeys(ctx context.Context, userId int) ([]musiclibrary.APIKey, error)
+	DeleteAPIKey(ctx context.Context, userId, keyId int) error
+	IssueToken(ctx context.Context, apiKey string) (musiclibrary.Token, error)
+	Authenticate(token string) (musiclibrary.User, error)
+	EnsureAdminKey(ctx context.Context, key string) error
+}
+
+// Library keeps the favorites and ratings of a user.
+type Library interface {
+	AddFavorite(ctx context.Context, userId, songId int) error
+	RemoveFavorite(ctx context.Context, userId, songId int) error
+	GetFavorites(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Favorite], error)
+	RateSong(ctx context.Context, userId, songId int, input musiclibrary.RatingInput) error
+	RemoveRating(ctx context.Context, userId, songId int) error
+	GetRatings(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Rating], error)
+}
+
+type Playlist interface {
+	CreatePlaylist(ctx context.Context, userId int, playlist musiclibrary.Playlist) (int, error)
+	GetPlaylists(ctx context.Context, userId int, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Playlist], error)
+	GetPlaylistById(ctx context.Context, userId, id int) (musiclibrary.PlaylistWithItems, error)
+	UpdatePlaylist(ctx context.Context, userId, id int, input musiclibrary.UpdatePlaylistInput) error
+	DeletePlaylist(ctx context.Context, userId, id int) error
+	AddPlaylistItem(ctx context.Context, userId, id int, input musiclibrary.PlaylistItemInput) (int, error)
+	RemovePlaylistItem(ctx context.Context, userId, id, itemId int) error
+	ReorderPlaylist(ctx context.Context, userId, id int, input musiclibrary.ReorderPlaylistInput) error
+	ExportPlaylist(ctx context.Context, userId, id int, format string, w io.Writer) error
+	ImportPlaylist(ctx context.Context, userId int, format, name string, r io.Reader, dryRun bool) (musiclibrary.PlaylistImportReport, error)
+}
+
+type Play interface {
+	RecordPlays(ctx context.Context, userId int, inputs []musiclibrary.PlayInput) (musiclibrary.PlayReport, error)
+	GetTopSongs(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.SongStat, error)
+	GetTopGroups(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.GroupStat, error)
+	GetPlaysPerDay(ctx context.Context, query musiclibrary.StatsQuery) ([]musiclibrary.DayStat, error)
+}
+
+// Similar finds songs with similar lyrics.
+type Similar interface {
+	GetSimilarSongs(ctx context.Context, songId, limit int) ([]musiclibrary.SimilarSong, error)
+	StartIndexer(ctx context.Context)
 }
 
 type Service struct {
 	Group
 	Song
 	SongDetails
+	Album
+	Artist
+	Membership
+	Genre
+	Tag
+	Search
+	Translation
+	Revision
+	Import
+	Export
+	Job
+	User
+	Library
+	Playlist
+	Play
+	Similar
 }
 
-func NewService(repos *repository.Repository) *Service {
+// NewService wires the services over repos. info may be nil, new songs are
+// then created with empty details and no enrichment jobs are queued. auth
+// configures the access tokens.
+func NewService(repos *repository.Repository, info SongInfo, auth AuthConfig) *Service {
+	jobs := NewJobService(repos.Job)
+	if info != nil {
+		enricher := &songEnricher{songs: repos.Authorisation, groups: repos.Group, details: repos.SongDetails, info: info}
+		jobs.Handle(JobRefreshSongDetails, enricher.refreshSongDetails)
+	}
+
 	return &Service{
-		Group:       NewGroupService(repos.Group),
-		Song:        NewAuthService(repos.Authorisation),
+		Group:       NewGroupService(repos.Group, repos.Authorisation),
+		Song:        NewAuthService(repos.Authorisation, repos.Group, repos.SongDetails, repos.Album, repos.Play, repos.UnitOfWork, jobs),
 		SongDetails: NewSongDetailsService(repos.SongDetails),
+		Album:       NewAlbumService(repos.Album),
+		Artist:      NewArtistService(repos.Artist),
+		Membership:  NewMembershipService(repos.Membership),
+		Genre:       NewGenreService(repos.Genre),
+		Tag:         NewTagService(repos.Tag),
+		Search:      NewSearchService(repos.Search),
+		Translation: NewTranslationService(repos.Translation, repos.SongDetails),
+		Revision:    NewRevisionService(repos.Revision, repos.SongDetails),
+		Import:      NewImportService(repos.Import),
+		Export:      NewExportService(repos.Export),
+		Job:         jobs,
+		User:        NewUserService(repos.User, auth),
+		Library:     NewLibraryService(repos.Library),
+		Playlist:    NewPlaylistService(repos.Playlist),
+		Play:        NewPlayService(repos.Play),
+		Similar:     NewSimilarService(repos.Similarity),
 	}
 }
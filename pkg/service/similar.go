@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 50
+)
+
+// A similar song scores its lyrics similarity raised by these fractions when
+// it shares the group, a genre or the era of the song, so that of songs with
+// much the same lyrics those closer in style come first. An era is a decade.
+const (
+	groupBoost = 0.25
+	genreBoost = 0.15
+	eraBoost   = 0.1
+	maxBoost   = 1 + groupBoost + genreBoost + eraBoost
+)
+
+var errSongNotFound = &musiclibrary.Error{Kind: musiclibrary.ErrNotFound, Message: "song not found"}
+
+// stopWords are left out of the index, they occur in nearly all lyrics.
+// Apostrophes are dropped before the lookup, see normalizeName.
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		a an and are as at be been but by can could did do does dont for from
+		got had has have he her him his how i if im in into is it its ive just
+		me my no not now of on or our out she so than that the their them then
+		there they this to too up us was we were what when where who will with
+		would you youre your yours all am any oh ooh ah uh yeah hey la na da
+		gonna wanna cause let lets get go come ill id youll youve whats thats`) {
+		stopWords[word] = true
+	}
+}
+
+// The index is refreshed in the background every similarIndexInterval, and
+// sooner when a song that is not indexed yet is asked for, but never more
+// often than every similarIndexMinGap.
+const (
+	similarIndexInterval = time.Minute
+	similarIndexMinGap   = 5 * time.Second
+)
+
+// SimilarService finds songs with similar lyrics. It keeps a TF-IDF index of
+// the library in memory, built from the term counts the repository keeps.
+// A background indexer counts the terms of songs that are new or whose text
+// changed and applies them and the deleted songs to the index, so requests
+// only ever read it.
+type SimilarService struct {
+	repo  repository.Similarity
+	stale chan struct{}
+
+	mu    sync.Mutex
+	index *tfidfIndex // nil until first loaded
+}
+
+func NewSimilarService(repo repository.Similarity) *SimilarService {
+	return &SimilarService{repo: repo, stale: make(chan struct{}, 1)}
+}
+
+// StartIndexer keeps the index up to date until ctx is cancelled. Until its
+// first pass is done no song has similar songs.
+func (s *SimilarService) StartIndexer(ctx context.Context) {
+	logrus.Info("Starting similar songs indexer")
+	go func() {
+		for {
+			if err := s.refresh(ctx); err != nil && ctx.Err() == nil {
+				logrus.WithError(err).Error("Failed to refresh similar songs index")
+			}
+			select {
+			case <-time.After(similarIndexMinGap):
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-time.After(similarIndexInterval - similarIndexMinGap):
+			case <-s.stale:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// GetSimilarSongs returns up to limit songs whose lyrics resemble those of
+// songId, best first. Songs sharing no terms with it are left out.
+func (s *SimilarService) GetSimilarSongs(ctx context.Context, songId, limit int) ([]musiclibrary.SimilarSong, error) {
+	if limit == 0 {
+		limit = defaultSimilarLimit
+	}
+	if limit < 1 || limit > maxSimilarLimit {
+		return nil, musiclibrary.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", maxSimilarLimit))
+	}
+
+	matches := s.match(songId, limit)
+
+	ids := []int{songId}
+	for _, m := range matches {
+		ids = append(ids, m.songId)
+	}
+	list, err := s.repo.GetSongFeatures(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	features := make(map[int]musiclibrary.SongFeatures, len(list))
+	for _, f := range list {
+		features[f.SongId] = f
+	}
+	song, ok := features[songId]
+	if !ok {
+		return nil, errSongNotFound
+	}
+
+	similar := make([]musiclibrary.SimilarSong, 0, len(matches))
+	for _, m := range matches {
+		// Songs deleted since the index was loaded drop out with the next
+		// refresh.
+		if other, ok := features[m.songId]; ok {
+			similar = append(similar, similarSong(song, other, m.score))
+		}
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].Score > similar[j].Score
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
+
+// match returns the songs whose lyrics are closest to those of songId: the
+// best limit of them, and any other that could still overtake them with the
+// boosts. A song missing from the index has the indexer look for new songs.
+func (s *SimilarService) match(songId, limit int) []tfidfMatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index == nil || s.index.songs[songId] == nil {
+		select {
+		case s.stale <- struct{}{}:
+		default:
+		}
+		return nil
+	}
+	matches := s.index.similar(songId)
+	if len(matches) <= limit {
+		return matches
+	}
+	floor := matches[limit-1].score
+	n := limit
+	for n < len(matches) && matches[n].score*maxBoost >= floor {
+		n++
+	}
+	return matches[:n]
+}
+
+// refresh loads the index on its first run. Every run then counts the terms
+// of the songs without term counts, adds them to the index and drops the
+// songs whose counts are gone, deleted songs and songs whose text changed
+// meanwhile, which the next run adds again. Only the indexer changes the
+// index, so it reads it without holding mu.
+func (s *SimilarService) refresh(ctx context.Context) error {
+	if s.index == nil {
+		vectors, err := s.repo.GetSongVectors(ctx)
+		if err != nil {
+			return err
+		}
+		index := newTFIDFIndex()
+		for _, vector := range vectors {
+			index.set(vector.SongId, vector.Terms)
+		}
+		index.updateNorms()
+		s.mu.Lock()
+		s.index = index
+		s.mu.Unlock()
+		logrus.WithField("songs", len(vectors)).Info("Similar songs index loaded")
+	}
+
+	songs, err := s.repo.GetUnindexedSongs(ctx)
+	if err != nil {
+		return err
+	}
+	indexed := make(map[int]map[string]int, len(songs))
+	for _, song := range songs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		terms := termCounts(song.Text)
+		saved, err := s.repo.SaveSongVector(ctx, musiclibrary.SongVector{SongId: song.SongId, Terms: terms}, song.Text)
+		if err != nil {
+			return err
+		}
+		// A song whose text changed meanwhile is picked up next time.
+		if saved {
+			indexed[song.SongId] = terms
+		}
+	}
+
+	songIds := make([]int, 0, len(s.index.songs))
+	for songId := range s.index.songs {
+		songIds = append(songIds, songId)
+	}
+	removed, err := s.repo.GetRemovedSongs(ctx, songIds)
+	if err != nil {
+		return err
+	}
+
+	if len(indexed) == 0 && len(removed) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	for songId, terms := range indexed {
+		s.index.set(songId, terms)
+	}
+	for _, songId := range removed {
+		if _, ok := indexed[songId]; !ok {
+			s.index.remove(songId)
+		}
+	}
+	// The norms change with the document frequencies, they are computed
+	// here rather than by the next request.
+	s.index.updateNorms()
+	s.mu.Unlock()
+	logrus.WithFields(logrus.Fields{
+		"indexed": len(indexed),
+		"removed": len(removed),
+	}).Info("Similar songs index updated")
+	return nil
+}
+
+func similarSong(song, other musiclibrary.SongFeatures, lyrics float64) musiclibrary.SimilarSong {
+	similar := musiclibrary.SimilarSong{
+		SongId:       other.SongId,
+		SongName:     other.SongName,
+		GroupId:      other.GroupId,
+		GroupName:    other.GroupName,
+		ReleaseDate:  other.ReleaseDate,
+		Lyrics:       lyrics,
+		SameGroup:    song.GroupId == other.GroupId,
+		SharedGenres: sharedGenres(song.Genres, other.Genres),
+		SameEra:      sameEra(song.ReleaseDate, other.ReleaseDate),
+	}
+	boost := 1.0
+	if similar.SameGroup {
+		boost += groupBoost
+	}
+	if similar.SharedGenres > 0 {
+		boost += genreBoost
+	}
+	if similar.SameEra {
+		boost += eraBoost
+	}
+	similar.Score = lyrics * boost
+	return similar
+}
+
+func sharedGenres(a, b []int) int {
+	genres := make(map[int]bool, len(a))
+	for _, id := range a {
+		genres[id] = true
+	}
+	shared := 0
+	for _, id := range b {
+		if genres[id] {
+			shared++
+		}
+	}
+	return shared
+}
+
+// sameEra reports whether two release dates fall in the same decade. Songs
+// without a release date share no era.
+func sameEra(a, b string) bool {
+	x, err := time.Parse("2006-01-02", a)
+	if err != nil {
+		return false
+	}
+	y, err := time.Parse("2006-01-02", b)
+	if err != nil {
+		return false
+	}
+	return x.Year()/10 == y.Year()/10
+}
+
+// termCounts counts the words of lyrics, leaving out section headers such
+// as [Chorus], stop words and single letters.
+func termCounts(text string) map[string]int {
+	counts := map[string]int{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if sectionHeader.MatchString(line) {
+			continue
+		}
+		for _, word := range strings.Fields(normalizeName(line)) {
+			if utf8.RuneCountInString(word) < 2 || stopWords[word] {
+				continue
+			}
+			counts[word]++
+		}
+	}
+	return counts
+}
+
+type tfidfMatch struct {
+	songId int
+	score  float64
+}
+
+// tfidfIndex weighs the term counts of songs by TF-IDF. Adding or removing
+// a song touches only its own terms, the vector norms, which depend on the
+// document frequencies of all terms, are computed again when next needed.
+type tfidfIndex struct {
+	songs    map[int]map[string]int // term counts by song id
+	postings map[string]map[int]int // term counts by term, then song id
+	norms    map[int]float64        // nil when out of date
+}
+
+func newTFIDFIndex() *tfidfIndex {
+	return &tfidfIndex{songs: map[int]map[string]int{}, postings: map[string]map[int]int{}}
+}
+
+// set replaces the term counts of a song. Songs without terms are left out,
+// they are similar to nothing.
+func (x *tfidfIndex) set(songId int, terms map[string]int) {
+	x.remove(songId)
+	if len(terms) == 0 {
+		return
+	}
+	x.songs[songId] = terms
+	for term, count := range terms {
+		if x.postings[term] == nil {
+			x.postings[term] = map[int]int{}
+		}
+		x.postings[term][songId] = count
+	}
+	x.norms = nil
+}
+
+func (x *tfidfIndex) remove(songId int) {
+	terms, ok := x.songs[songId]
+	if !ok {
+		return
+	}
+	for term := range terms {
+		delete(x.postings[term], songId)
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	delete(x.songs, songId)
+	x.norms = nil
+}
+
+// idf is smoothed, so terms found in every song still count a little.
+func (x *tfidfIndex) idf(term string) float64 {
+	return math.Log(1 + float64(len(x.songs))/float64(len(x.postings[term])))
+}
+
+// tf dampens repeated words, a chorus sung four times is not four times as
+// telling.
+func tf(count int) float64 {
+	return 1 + math.Log(float64(count))
+}
+
+func (x *tfidfIndex) updateNorms() {
+	if x.norms != nil {
+		return
+	}
+	idf := make(map[string]float64, len(x.postings))
+	for term := range x.postings {
+		idf[term] = x.idf(term)
+	}
+	x.norms = make(map[int]float64, len(x.songs))
+	for songId, terms := range x.songs {
+		var sum float64
+		for term, count := range terms {
+			w := tf(count) * idf[term]
+			sum += w * w
+		}
+		x.norms[songId] = math.Sqrt(sum)
+	}
+}
+
+// similar returns the cosine similarity of songId to every song it shares
+// a term with, most similar first.
+func (x *tfidfIndex) similar(songId int) []tfidfMatch {
+	terms, ok := x.songs[songId]
+	if !ok {
+		return nil
+	}
+	x.updateNorms()
+
+	dots := map[int]float64{}
+	for term, count := range terms {
+		idf := x.idf(term)
+		w := tf(count) * idf
+		for other, otherCount := range x.postings[term] {
+			if other != songId {
+				dots[other] += w * tf(otherCount) * idf
+			}
+		}
+	}
+
+	norm := x.norms[songId]
+	matches := make([]tfidfMatch, 0, len(dots))
+	for other, dot := range dots {
+		if dot > 0 && norm > 0 && x.norms[other] > 0 {
+			matches = append(matches, tfidfMatch{songId: other, score: dot / (norm * x.norms[other])})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].songId < matches[j].songId
+	})
+	return matches
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+// TestSimilarIndexUpdates checks that every refresh of the index applies new
+// lyrics, changed lyrics and deleted songs without reloading it.
+func TestSimilarIndexUpdates(t *testing.T) {
+	ctx := context.Background()
+	repos := repository.NewMemoryRepository()
+	group, err := repos.CreateGroup(ctx, musiclibrary.Group{GroupName: "Queen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	texts := []string{
+		"Mama, just killed a man\nPut a gun against his head",
+		"Mama, ooh, didn't mean to make you cry\nPut a gun to his head",
+		"Thunderbolt and lightning, very, very frightening me",
+	}
+	var songs []int
+	for i, text := range texts {
+		id, err := repos.CreateSong(ctx, musiclibrary.Song{SongName: string(rune('A' + i)), GroupId: group})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.UpdateSongDetails(ctx, id, musiclibrary.UpdateSongDetailsInput{Text: text}); err != nil {
+			t.Fatal(err)
+		}
+		songs = append(songs, id)
+	}
+
+	s := NewSimilarService(repos.Similarity)
+	similarTo := func(songId int) []int {
+		t.Helper()
+		if err := s.refresh(ctx); err != nil {
+			t.Fatal(err)
+		}
+		similar, err := s.GetSimilarSongs(ctx, songId, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]int, 0, len(similar))
+		for _, song := range similar {
+			ids = append(ids, song.SongId)
+		}
+		return ids
+	}
+	if got := similarTo(songs[0]); len(got) != 1 || got[0] != songs[1] {
+		t.Fatalf("similar to song %d = %v, want [%d]", songs[0], got, songs[1])
+	}
+	index := s.index
+
+	err = repos.UpdateSongDetails(ctx, songs[2], musiclibrary.UpdateSongDetailsInput{Text: "Mama, life had just begun"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := similarTo(songs[2]); len(got) != 2 {
+		t.Fatalf("similar to song %d with changed lyrics = %v, want two songs", songs[2], got)
+	}
+
+	if err := repos.DeleteSong(ctx, songs[1]); err != nil {
+		t.Fatal(err)
+	}
+	if got := similarTo(songs[0]); len(got) != 1 || got[0] != songs[2] {
+		t.Fatalf("similar to song %d after a deletion = %v, want [%d]", songs[0], got, songs[2])
+	}
+	if _, ok := s.index.songs[songs[1]]; ok {
+		t.Errorf("deleted song %d is still indexed", songs[1])
+	}
+	if s.index != index {
+		t.Error("the index was loaded again, want it updated in place")
+	}
+}
@@ -1,33 +1,163 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
 	timetracker "time-tracker"
 	"time-tracker/pkg/repository"
+
+	"github.com/sirupsen/logrus"
 )
 
+var ErrInvalidReleaseDate = timetracker.NewValidationError("releaseDate", "must be YYYY-MM-DD")
+
 type AuthServise struct {
-	repo repository.Authorisation
+	repo    repository.Authorisation
+	groups  repository.Group
+	details repository.SongDetails
+	albums  repository.Album
+	plays   repository.Play
+	uow     repository.UnitOfWork
+	jobs    Job
 }
 
-func NewAuthService(repo repository.Authorisation) *AuthServise {
-	return &AuthServise{repo: repo}
+func NewAuthService(repo repository.Authorisation, groups repository.Group, details repository.SongDetails,
+	albums repository.Album, plays repository.Play, uow repository.UnitOfWork, jobs Job) *AuthServise {
+	return &AuthServise{repo: repo, groups: groups, details: details, albums: albums, plays: plays, uow: uow, jobs: jobs}
 }
 
-func (s *AuthServise) CreateSong(song timetracker.Song) (int, error) {
-	return s.repo.CreateSong(song)
+// CreateSong creates a song together with its details in one transaction. A
+// missing group is reported as a foreign key error on groupId. When no details are given a
+// job is queued to fill them in from the song info API, the song is created
+// even if the job cannot be queued.
+func (s *AuthServise) CreateSong(ctx context.Context, input timetracker.CreateSongInput) (int, error) {
+	if input.ReleaseDate != "" {
+		if _, err := time.Parse("2006-01-02", input.ReleaseDate); err != nil {
+			return 0, ErrInvalidReleaseDate
+		}
+	}
+
+	var id int
+	err := s.uow.InTransaction(ctx, func(repos repository.TxRepositories) error {
+		// Checked first so a missing group is reported against the groupId
+		// field rather than as a bare foreign key violation.
+		if _, err := repos.GetGroupById(ctx, input.GroupId); errors.Is(err, timetracker.ErrNotFound) {
+			return &timetracker.Error{
+				Kind:    timetracker.ErrForeignKey,
+				Message: "invalid input",
+				Fields:  []timetracker.FieldError{{Field: "groupId", Message: fmt.Sprintf("group %d does not exist", input.GroupId)}},
+				Err:     err,
+			}
+		} else if err != nil {
+			return err
+		}
+		var err error
+		id, err = repos.CreateSong(ctx, timetracker.Song{SongName: input.SongName, GroupId: input.GroupId})
+		if err != nil {
+			return err
+		}
+		return repos.UpdateSongDetails(ctx, id, timetracker.UpdateSongDetailsInput{
+			ReleaseDate: input.ReleaseDate,
+			Text:        input.Text,
+			Link:        input.Link,
+			Comment:     "Created with the song",
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if input.ReleaseDate == "" && input.Text == "" && input.Link == "" {
+		if _, err := s.jobs.EnqueueRefreshSongDetails(ctx, id); err != nil && !errors.Is(err, ErrSongInfoDisabled) {
+			logrus.WithError(err).WithField("song_id", id).Warn("Failed to queue song details refresh")
+		}
+	}
+	return id, nil
 }
 
-func (s *AuthServise) GetAllSongs() ([]timetracker.Song, error) {
-	return s.repo.GetAllSongs()
+// GetAllSongs is GetSongsWithFilter without filters.
+func (s *AuthServise) GetAllSongs(ctx context.Context, page timetracker.PageRequest) (timetracker.Page[timetracker.Song], error) {
+	return s.GetSongsWithFilter(ctx, nil, page)
 }
 
-func (s *AuthServise) DeleteSong(id int) error {
-	return s.repo.DeleteSong(id)
+// GetSongById returns a song with the related resources named in include:
+// its group, details and the albums it appears on. Every included resource
+// takes one query whatever the number of rows.
+func (s *AuthServise) GetSongById(ctx context.Context, id int, include []string) (timetracker.SongWithRelations, error) {
+	includes, err := parseInclude(include, IncludeGroup, IncludeDetails, IncludeAlbums)
+	if err != nil {
+		return timetracker.SongWithRelations{}, err
+	}
+	song, err := s.repo.GetSongById(ctx, id)
+	if err != nil {
+		return timetracker.SongWithRelations{}, err
+	}
+	songs := []timetracker.Song{song}
+	if err := s.setLastPlayed(ctx, songs); err != nil {
+		return timetracker.SongWithRelations{}, err
+	}
+	song = songs[0]
+
+	result := timetracker.SongWithRelations{Song: song}
+	if includes[IncludeGroup] {
+		group, err := s.groups.GetGroupById(ctx, song.GroupId)
+		if err != nil {
+			return timetracker.SongWithRelations{}, err
+		}
+		result.Group = &group
+	}
+	if includes[IncludeDetails] {
+		details, err := s.details.GetSongDetailsById(ctx, id)
+		if err != nil {
+			return timetracker.SongWithRelations{}, err
+		}
+		if len(details) > 0 {
+			result.Details = &details[0]
+		}
+	}
+	if includes[IncludeAlbums] {
+		if result.Albums, err = s.albums.GetSongAlbums(ctx, []int{id}); err != nil {
+			return timetracker.SongWithRelations{}, err
+		}
+	}
+	return result, nil
 }
 
-func (s *AuthServise) UpdateSong(id int, input timetracker.UpdateSongInput) error {
-	return s.repo.UpdateSong(id, input)
+func (s *AuthServise) DeleteSong(ctx context.Context, id int) error {
+	return s.repo.DeleteSong(ctx, id)
 }
-func (s *AuthServise) GetSongsWithFilter(filters map[string]string, page int, limit int) ([]timetracker.Song, error) {
-	return s.repo.GetSongsWithFilter(filters, page, limit)
+
+func (s *AuthServise) UpdateSong(ctx context.Context, id int, input timetracker.UpdateSongInput) error {
+	return s.repo.UpdateSong(ctx, id, input)
+}
+func (s *AuthServise) GetSongsWithFilter(ctx context.Context, filters map[string]string, page timetracker.PageRequest) (timetracker.Page[timetracker.Song], error) {
+	songs, err := s.repo.GetSongsWithFilter(ctx, filters, page)
+	if err != nil {
+		return songs, err
+	}
+	return songs, s.setLastPlayed(ctx, songs.Items)
+}
+
+// setLastPlayed fills in when each of songs was last scrobbled, in one
+// query for all of them.
+func (s *AuthServise) setLastPlayed(ctx context.Context, songs []timetracker.Song) error {
+	if len(songs) == 0 {
+		return nil
+	}
+	ids := make([]int, len(songs))
+	for i, song := range songs {
+		ids[i] = song.Id
+	}
+	lastPlayed, err := s.plays.GetLastPlayed(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i, song := range songs {
+		if at, ok := lastPlayed[song.Id]; ok {
+			songs[i].LastPlayedAt = &at
+		}
+	}
+	return nil
 }
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"strings"
 	musiclibrary "time-tracker"
 	"time-tracker/pkg/repository"
 )
@@ -13,13 +15,75 @@ func NewSongDetailsService(repo repository.SongDetails) *SongDetailsService {
 	return &SongDetailsService{repo: repo}
 }
 
-func (s *SongDetailsService) GetSongDetailsById(songId int) ([]musiclibrary.SongDetails, error) {
-	return s.repo.GetSongDetailsById(songId)
+func (s *SongDetailsService) GetSongDetailsById(ctx context.Context, songId int) ([]musiclibrary.SongDetails, error) {
+	return s.repo.GetSongDetailsById(ctx, songId)
 }
 
-func (s *SongDetailsService) UpdateSongDetails(id int, input musiclibrary.UpdateSongDetailsInput) error {
-	return s.repo.UpdateSongDetails(id, input)
+func (s *SongDetailsService) UpdateSongDetails(ctx context.Context, id int, input musiclibrary.UpdateSongDetailsInput) error {
+	return s.repo.UpdateSongDetails(ctx, id, input)
 }
-func (s *SongDetailsService) GetSongText(songId int, page int, limit int) ([]string, error) {
-	return s.repo.GetSongText(songId, page, limit)
+func (s *SongDetailsService) GetSongText(ctx context.Context, songId int, page int, limit int) ([]string, error) {
+	return s.repo.GetSongText(ctx, songId, page, limit)
+}
+
+// GetSongSections returns the lyrics of a song split into typed sections,
+// optionally only the sections of sectionType.
+func (s *SongDetailsService) GetSongSections(ctx context.Context, songId int, sectionType string) ([]musiclibrary.LyricsSection, error) {
+	sectionType = strings.ToLower(strings.TrimSpace(sectionType))
+	if sectionType != "" && !sectionTypes[sectionType] {
+		return nil, ErrInvalidSectionType
+	}
+	text, err := s.repo.GetLyrics(ctx, songId)
+	if err != nil {
+		return nil, err
+	}
+	sections := parseSections(text)
+	if sectionType == "" {
+		return sections, nil
+	}
+	filtered := make([]musiclibrary.LyricsSection, 0)
+	for _, section := range sections {
+		if section.Type == sectionType {
+			filtered = append(filtered, section)
+		}
+	}
+	return filtered, nil
+}
+
+// UploadLrc validates and stores time-synced lyrics for a song.
+func (s *SongDetailsService) UploadLrc(ctx context.Context, songId int, lrc string) error {
+	if _, err := parseLrc(lrc); err != nil {
+		return err
+	}
+	return s.repo.SaveLrc(ctx, songId, lrc)
+}
+
+func (s *SongDetailsService) GetSyncedLyrics(ctx context.Context, songId int) (musiclibrary.SyncedLyrics, error) {
+	lrc, err := s.repo.GetLrc(ctx, songId)
+	if err != nil {
+		return musiclibrary.SyncedLyrics{}, err
+	}
+	return parseLrc(lrc)
+}
+
+// GetSyncedPosition returns the line shown at playback position at together
+// with the next lines to come.
+func (s *SongDetailsService) GetSyncedPosition(ctx context.Context, songId int, at string, next int) (musiclibrary.SyncedPosition, error) {
+	ms, err := parsePosition(at)
+	if err != nil {
+		return musiclibrary.SyncedPosition{}, err
+	}
+	lyrics, err := s.GetSyncedLyrics(ctx, songId)
+	if err != nil {
+		return musiclibrary.SyncedPosition{}, err
+	}
+	return syncedPosition(lyrics, ms, next), nil
+}
+
+func (s *SongDetailsService) ExportLrc(ctx context.Context, songId int, enhanced bool) (string, error) {
+	lyrics, err := s.GetSyncedLyrics(ctx, songId)
+	if err != nil {
+		return "", err
+	}
+	return formatLrc(lyrics, enhanced), nil
 }
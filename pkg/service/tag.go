@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"strings"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+)
+
+type TagService struct {
+	repo repository.Tag
+}
+
+func NewTagService(repo repository.Tag) *TagService {
+	return &TagService{repo: repo}
+}
+
+func (s *TagService) GetAllTags(ctx context.Context, page musiclibrary.PageRequest) (musiclibrary.Page[musiclibrary.Tag], error) {
+	return s.repo.GetAllTags(ctx, page)
+}
+
+func (s *TagService) GetTags(ctx context.Context, target musiclibrary.Taggable, targetId int) ([]musiclibrary.Tag, error) {
+	return s.repo.GetTags(ctx, target, targetId)
+}
+
+func (s *TagService) AttachTag(ctx context.Context, target musiclibrary.Taggable, targetId int, name string) error {
+	return s.repo.AttachTag(ctx, target, targetId, strings.TrimSpace(name))
+}
+
+func (s *TagService) DetachTag(ctx context.Context, target musiclibrary.Taggable, targetId int, name string) error {
+	return s.repo.DetachTag(ctx, target, targetId, strings.TrimSpace(name))
+}
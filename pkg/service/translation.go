@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+
+	"golang.org/x/text/language"
+)
+
+var ErrInvalidLanguage = musiclibrary.NewValidationError("language", "must be a BCP-47 tag such as en, ru or pt-BR")
+
+type TranslationService struct {
+	repo    repository.Translation
+	details repository.SongDetails
+}
+
+func NewTranslationService(repo repository.Translation, details repository.SongDetails) *TranslationService {
+	return &TranslationService{repo: repo, details: details}
+}
+
+func (s *TranslationService) GetTranslations(ctx context.Context, songId int) ([]musiclibrary.Translation, error) {
+	return s.repo.GetTranslations(ctx, songId)
+}
+
+func (s *TranslationService) GetTranslation(ctx context.Context, songId int, lang string) (musiclibrary.Translation, error) {
+	tag, err := canonicalLanguage(lang)
+	if err != nil {
+		return musiclibrary.Translation{}, err
+	}
+	return s.repo.GetTranslation(ctx, songId, tag)
+}
+
+func (s *TranslationService) CreateTranslation(ctx context.Context, songId int, translation musiclibrary.Translation) (int, error) {
+	tag, err := canonicalLanguage(translation.Language)
+	if err != nil {
+		return 0, err
+	}
+	translation.Language = tag
+	return s.repo.CreateTranslation(ctx, songId, translation)
+}
+
+func (s *TranslationService) UpdateTranslation(ctx context.Context, songId int, lang string, input musiclibrary.UpdateTranslationInput) error {
+	tag, err := canonicalLanguage(lang)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateTranslation(ctx, songId, tag, input)
+}
+
+func (s *TranslationService) DeleteTranslation(ctx context.Context, songId int, lang string) error {
+	tag, err := canonicalLanguage(lang)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTranslation(ctx, songId, tag)
+}
+
+// GetTranslatedText pages through the verses of one translation the same way
+// GetSongText pages through the original lyrics.
+func (s *TranslationService) GetTranslatedText(ctx context.Context, songId int, lang string, page, limit int) ([]string, error) {
+	translation, err := s.GetTranslation(ctx, songId, lang)
+	if err != nil {
+		return nil, err
+	}
+	return pageVerses(splitVerses(translation.Text), page, limit), nil
+}
+
+// GetParallelText aligns the original lyrics with a translation verse by
+// verse. When the two have a different number of verses the missing side of
+// a pair is left empty.
+func (s *TranslationService) GetParallelText(ctx context.Context, songId int, lang string, page, limit int) ([]musiclibrary.VersePair, error) {
+	translation, err := s.GetTranslation(ctx, songId, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.originalText(ctx, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	originals := splitVerses(original)
+	translated := splitVerses(translation.Text)
+	pairs := make([]musiclibrary.VersePair, max(len(originals), len(translated)))
+	for i := range pairs {
+		if i < len(originals) {
+			pairs[i].Original = originals[i]
+		}
+		if i < len(translated) {
+			pairs[i].Translation = translated[i]
+		}
+	}
+	return pagePairs(pairs, page, limit), nil
+}
+
+// originalText prefers the translation marked as original and falls back to
+// the lyrics stored in song details.
+func (s *TranslationService) originalText(ctx context.Context, songId int) (string, error) {
+	original, err := s.repo.GetOriginal(ctx, songId)
+	if err == nil {
+		return original.Text, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	return s.details.GetLyrics(ctx, songId)
+}
+
+func canonicalLanguage(lang string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(lang))
+	if err != nil {
+		return "", ErrInvalidLanguage
+	}
+	return tag.String(), nil
+}
+
+func splitVerses(text string) []string {
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n")
+}
+
+func pageVerses(verses []string, page, limit int) []string {
+	start, end := pageBounds(len(verses), page, limit)
+	if start >= end {
+		return nil
+	}
+	return verses[start:end]
+}
+
+func pagePairs(pairs []musiclibrary.VersePair, page, limit int) []musiclibrary.VersePair {
+	start, end := pageBounds(len(pairs), page, limit)
+	if start >= end {
+		return nil
+	}
+	return pairs[start:end]
+}
+
+func pageBounds(total, page, limit int) (int, int) {
+	start := (page - 1) * limit
+	end := start + limit
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	return start, end
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+	musiclibrary "time-tracker"
+	"time-tracker/pkg/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultTokenTTL is how long an access token is valid unless
+	// AuthConfig says otherwise.
+	DefaultTokenTTL = 15 * time.Minute
+
+	apiKeyPrefix = "mlk_"
+	// apiKeyShown is how much of a key is kept in the clear to tell keys
+	// apart, the prefix and 8 of its 64 hex digits. Of a shorter bootstrap
+	// key only a quarter is kept.
+	apiKeyShown = len(apiKeyPrefix) + 8
+	// minAPIKeyLength applies to the bootstrap key, which is not generated.
+	minAPIKeyLength = 16
+
+	bootstrapAdmin = "admin"
+)
+
+var ErrInvalidAPIKey = &musiclibrary.Error{Kind: musiclibrary.ErrUnauthorized, Message: "invalid API key"}
+
+// AuthConfig configures the access tokens issued for API keys.
+type AuthConfig struct {
+	// Secret signs the tokens. Tokens issued before it changes are
+	// rejected.
+	Secret   []byte
+	TokenTTL time.Duration
+}
+
+type UserService struct {
+	repo   repository.User
+	config AuthConfig
+	now    func() time.Time
+}
+
+func NewUserService(repo repository.User, config AuthConfig) *UserService {
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = DefaultTokenTTL
+	}
+	return &UserService{repo: repo, config: config, now: time.Now}
+}
+
+func (s *UserService) CreateUser(ctx context.Context, user musiclibrary.User) (int, error) {
+	return s.repo.CreateUser(ctx, user)
+}
+
+func (s *UserService) GetUsers(ctx context.Context) ([]musiclibrary.User, error) {
+	return s.repo.GetUsers(ctx)
+}
+
+// CreateAPIKey generates a key for a user. Only its hash is stored, the key
+// itself is returned this once.
+func (s *UserService) CreateAPIKey(ctx context.Context, userId int, input musiclibrary.APIKeyInput) (musiclibrary.NewAPIKey, error) {
+	if _, err := s.repo.GetUserById(ctx, userId); err != nil {
+		return musiclibrary.NewAPIKey{}, err
+	}
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return musiclibrary.NewAPIKey{}, err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(random)
+	return s.saveAPIKey(ctx, userId, input.Name, key)
+}
+
+func (s *UserService) saveAPIKey(ctx context.Context, userId int, name, key string) (musiclibrary.NewAPIKey, error) {
+	apiKey := musiclibrary.APIKey{UserId: userId, Name: name, Prefix: key[:min(apiKeyShown, len(key)/4)]}
+	id, err := s.repo.CreateAPIKey(ctx, apiKey, hashAPIKey(key))
+	if err != nil {
+		return musiclibrary.NewAPIKey{}, err
+	}
+	apiKey.Id = id
+	return musiclibrary.NewAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+func (s *UserService) GetAPIKeys(ctx context.Context, userId int) ([]musiclibrary.APIKey, error) {
+	if _, err := s.repo.GetUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAPIKeys(ctx, userId)
+}
+
+func (s *UserService) DeleteAPIKey(ctx context.Context, userId, keyId int) error {
+	return s.repo.DeleteAPIKey(ctx, userId, keyId)
+}
+
+// IssueToken exchanges an API key for a short-lived access token carrying
+// the user's role.
+func (s *UserService) IssueToken(ctx context.Context, apiKey string) (musiclibrary.Token, error) {
+	user, err := s.repo.GetUserByAPIKey(ctx, hashAPIKey(apiKey))
+	if errors.Is(err, musiclibrary.ErrNotFound) {
+		return musiclibrary.Token{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return musiclibrary.Token{}, err
+	}
+	now := s.now()
+	token, err := signToken(tokenClaims{
+		Subject:   user.Id,
+		Name:      user.Name,
+		Role:      user.Role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.config.TokenTTL).Unix(),
+	}, s.config.Secret)
+	if err != nil {
+		return musiclibrary.Token{}, err
+	}
+	logrus.WithField("userId", user.Id).Info("Access token issued")
+	return musiclibrary.Token{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.config.TokenTTL.Seconds()),
+	}, nil
+}
+
+// Authenticate returns the user an access token was issued to, as they were
+// when it was issued.
+func (s *UserService) Authenticate(token string) (musiclibrary.User, error) {
+	claims, err := parseToken(token, s.config.Secret, s.now())
+	if err != nil {
+		return musiclibrary.User{}, err
+	}
+	return musiclibrary.User{Id: claims.Subject, Name: claims.Name, Role: claims.Role}, nil
+}
+
+// EnsureAdminKey makes key a key of the admin user, creating the user if
+// needed, so a fresh installation has someone to create the other users.
+func (s *UserService) EnsureAdminKey(ctx context.Context, key string) error {
+	if len(key) < minAPIKeyLength {
+		return musiclibrary.NewValidationError("ADMIN_API_KEY", "must be at least 16 characters")
+	}
+	if _, err := s.repo.GetUserByAPIKey(ctx, hashAPIKey(key)); !errors.Is(err, musiclibrary.ErrNotFound) {
+		return err
+	}
+
+	users, err := s.repo.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+	adminId := 0
+	for _, user := range users {
+		if user.Name == bootstrapAdmin {
+			adminId = user.Id
+		}
+	}
+	if adminId == 0 {
+		if adminId, err = s.repo.CreateUser(ctx, musiclibrary.User{Name: bootstrapAdmin, Role: musiclibrary.RoleAdmin}); err != nil {
+			return err
+		}
+	}
+	if _, err := s.saveAPIKey(ctx, adminId, "ADMIN_API_KEY", key); err != nil {
+		return err
+	}
+	logrus.WithField("userId", adminId).Info("Admin API key registered")
+	return nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
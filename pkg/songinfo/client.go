@@ -0,0 +1,189 @@
+// Package songinfo talks to the external song info API, GET /info?group=&song=
+// answering with the release date, lyrics and a link for a song.
+package songinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrNotFound    = errors.New("song info not found")
+	ErrCircuitOpen = errors.New("song info API is unavailable, circuit breaker is open")
+)
+
+// Info is the payload returned by the song info API.
+type Info struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+type Config struct {
+	BaseURL string
+	// Timeout bounds a single HTTP attempt, retries get their own timeout.
+	Timeout    time.Duration
+	MaxRetries int
+	// Backoff is the delay before the first retry, it doubles on every
+	// following retry up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// FailureThreshold consecutive failures open the circuit for Cooldown,
+	// during which calls fail fast with ErrCircuitOpen.
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+func DefaultConfig(baseURL string) Config {
+	return Config{
+		BaseURL:          baseURL,
+		Timeout:          3 * time.Second,
+		MaxRetries:       3,
+		Backoff:          200 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	breaker *breaker
+}
+
+func NewClient(cfg Config) *Client {
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		breaker: newBreaker(cfg.FailureThreshold, cfg.Cooldown),
+	}
+}
+
+// retryableError marks failures that may succeed when the request is repeated:
+// transport errors, timeouts, 429 and 5xx responses and unreadable answers.
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+// Fetch looks up a song, retrying transient failures with exponential backoff.
+func (c *Client) Fetch(ctx context.Context, group, song string) (Info, error) {
+	var err error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.backoff(attempt)
+			logrus.WithFields(logrus.Fields{
+				"attempt": attempt,
+				"delay":   delay,
+			}).WithError(err).Warn("Retrying song info request")
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return Info{}, ctx.Err()
+			}
+		}
+
+		if !c.breaker.allow() {
+			return Info{}, ErrCircuitOpen
+		}
+		var info Info
+		var answered bool
+		info, answered, err = c.fetch(ctx, group, song)
+		var retryable retryableError
+		switch {
+		case errors.As(err, &retryable):
+			c.breaker.failure()
+		case answered:
+			c.breaker.success()
+			return info, err
+		default:
+			// The caller gave up or the request could not be made, which
+			// says nothing about the API.
+			c.breaker.release()
+			return Info{}, err
+		}
+	}
+	return Info{}, err
+}
+
+// fetch makes a single request. It reports whether the API answered it with
+// a response that was read in full, only such answers show that it is up.
+func (c *Client) fetch(ctx context.Context, group, song string) (Info, bool, error) {
+	query := url.Values{}
+	query.Set("group", group)
+	query.Set("song", song)
+	endpoint := strings.TrimRight(c.cfg.BaseURL, "/") + "/info?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Info{}, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Info{}, false, ctx.Err()
+		}
+		return Info{}, false, retryableError{err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return Info{}, true, ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return Info{}, false, retryableError{fmt.Errorf("song info API responded %s", resp.Status)}
+	default:
+		return Info{}, true, fmt.Errorf("song info API responded %s", resp.Status)
+	}
+
+	var info Info
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&info); err != nil {
+		if ctx.Err() != nil {
+			return Info{}, false, ctx.Err()
+		}
+		// A garbled or cut off answer is as good as none.
+		return Info{}, false, retryableError{fmt.Errorf("decoding song info: %w", err)}
+	}
+	info.ReleaseDate = normalizeDate(info.ReleaseDate)
+	return info, true, nil
+}
+
+// backoff returns the delay before the given retry with up to 20% jitter so
+// that clients failing together do not retry together.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.cfg.Backoff << (attempt - 1)
+	if c.cfg.MaxBackoff > 0 && (delay > c.cfg.MaxBackoff || delay <= 0) {
+		delay = c.cfg.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay - time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// normalizeDate accepts the DD.MM.YYYY dates the API uses as well as ISO
+// dates and returns YYYY-MM-DD, or the value unchanged if it is neither.
+func normalizeDate(date string) string {
+	date = strings.TrimSpace(date)
+	for _, layout := range []string{"02.01.2006", "2006-01-02"} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return date
+}
@@ -0,0 +1,260 @@
+package songinfo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMain(m *testing.M) {
+	// Retries and the breaker log on purpose.
+	logrus.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// stubServer serves a Stub that knows one song. Every request is counted,
+// and while fail is above zero the request is answered with status instead
+// of reaching the stub.
+type stubServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	fail     atomic.Int32
+	status   int
+}
+
+func newStubServer(t *testing.T, status int) *stubServer {
+	stub := NewStub()
+	stub.Add("Muse", "Supermassive Black Hole", Info{
+		ReleaseDate: "16.07.2006",
+		Text:        "Ooh baby, don't you know I suffer?",
+		Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	})
+	s := &stubServer{status: status}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if s.fail.Add(-1) >= 0 {
+			w.WriteHeader(s.status)
+			return
+		}
+		stub.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func testConfig(baseURL string) Config {
+	cfg := DefaultConfig(baseURL)
+	cfg.Backoff = time.Millisecond
+	cfg.MaxBackoff = 5 * time.Millisecond
+	cfg.FailureThreshold = 0
+	return cfg
+}
+
+func TestFetchStub(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable)
+	client := NewClient(testConfig(server.URL))
+
+	info, err := client.Fetch(context.Background(), "muse", "supermassive black hole")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ReleaseDate != "2006-07-16" {
+		t.Errorf("release date = %q, want 2006-07-16", info.ReleaseDate)
+	}
+	if info.Link == "" || info.Text == "" {
+		t.Errorf("info = %+v, want text and link", info)
+	}
+}
+
+func TestFetchRetriesServerErrors(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		server := newStubServer(t, status)
+		server.fail.Store(2)
+		client := NewClient(testConfig(server.URL))
+
+		if _, err := client.Fetch(context.Background(), "Muse", "Supermassive Black Hole"); err != nil {
+			t.Fatalf("%d: %v", status, err)
+		}
+		if n := server.requests.Load(); n != 3 {
+			t.Errorf("%d: %d requests, want 3", status, n)
+		}
+	}
+}
+
+func TestFetchGivesUpAfterMaxRetries(t *testing.T) {
+	server := newStubServer(t, http.StatusBadGateway)
+	server.fail.Store(100)
+	cfg := testConfig(server.URL)
+	cfg.MaxRetries = 2
+	client := NewClient(cfg)
+
+	var retryable retryableError
+	if _, err := client.Fetch(context.Background(), "Muse", "Supermassive Black Hole"); !errors.As(err, &retryable) {
+		t.Fatalf("err = %v, want the last server error", err)
+	}
+	if n := server.requests.Load(); n != 3 {
+		t.Errorf("%d requests, want 3", n)
+	}
+}
+
+func TestFetchStopsRetryingWhenCanceled(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable)
+	server.fail.Store(100)
+	cfg := testConfig(server.URL)
+	cfg.Backoff = time.Hour
+	cfg.MaxBackoff = time.Hour
+	client := NewClient(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Fetch(ctx, "Muse", "Supermassive Black Hole"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := server.requests.Load(); n != 1 {
+		t.Errorf("%d requests, want 1", n)
+	}
+}
+
+func TestFetchDoesNotRetryClientErrors(t *testing.T) {
+	server := newStubServer(t, http.StatusServiceUnavailable)
+	client := NewClient(testConfig(server.URL))
+
+	if _, err := client.Fetch(context.Background(), "Muse", "Unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want %v", err, ErrNotFound)
+	}
+	// The stub rejects a request without a song as a bad request.
+	if _, err := client.Fetch(context.Background(), "Muse", ""); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want a bad request error", err)
+	}
+	if n := server.requests.Load(); n != 2 {
+		t.Errorf("%d requests, want 2", n)
+	}
+}
+
+func TestFetchOpensCircuit(t *testing.T) {
+	server := newStubServer(t, http.StatusInternalServerError)
+	server.fail.Store(2)
+	cfg := testConfig(server.URL)
+	cfg.MaxRetries = 0
+	cfg.FailureThreshold = 2
+	cfg.Cooldown = 50 * time.Millisecond
+	client := NewClient(cfg)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Fetch(ctx, "Muse", "Supermassive Black Hole"); err == nil {
+			t.Fatalf("call %d succeeded, want a server error", i+1)
+		}
+	}
+	if _, err := client.Fetch(ctx, "Muse", "Supermassive Black Hole"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want %v", err, ErrCircuitOpen)
+	}
+	if n := server.requests.Load(); n != 2 {
+		t.Errorf("%d requests, want 2, an open circuit must not reach the server", n)
+	}
+
+	// After the cooldown a probe goes through and its success closes the
+	// circuit again.
+	time.Sleep(cfg.Cooldown)
+	for i := 0; i < 2; i++ {
+		if _, err := client.Fetch(ctx, "Muse", "Supermassive Black Hole"); err != nil {
+			t.Fatalf("call %d after the cooldown: %v", i+1, err)
+		}
+	}
+}
+
+func TestFetchReopensCircuitOnFailedProbe(t *testing.T) {
+	server := newStubServer(t, http.StatusInternalServerError)
+	server.fail.Store(3)
+	cfg := testConfig(server.URL)
+	cfg.MaxRetries = 0
+	cfg.FailureThreshold = 2
+	cfg.Cooldown = 50 * time.Millisecond
+	client := NewClient(cfg)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		client.Fetch(ctx, "Muse", "Supermassive Black Hole")
+	}
+	time.Sleep(cfg.Cooldown)
+	var retryable retryableError
+	if _, err := client.Fetch(ctx, "Muse", "Supermassive Black Hole"); !errors.As(err, &retryable) {
+		t.Fatalf("probe err = %v, want a server error", err)
+	}
+	if _, err := client.Fetch(ctx, "Muse", "Supermassive Black Hole"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want %v after a failed probe", err, ErrCircuitOpen)
+	}
+	if n := server.requests.Load(); n != 3 {
+		t.Errorf("%d requests, want 3", n)
+	}
+}
+
+func TestBreakerLetsOneProbeThrough(t *testing.T) {
+	b := newBreaker(1, 10*time.Millisecond)
+	b.failure()
+	if b.allow() {
+		t.Fatal("open breaker allowed a call")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker allowed no probe after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("breaker allowed a second call while probing")
+	}
+	b.success()
+	if !b.allow() || !b.allow() {
+		t.Fatal("breaker stayed open after a successful probe")
+	}
+}
+
+func TestFetchCancelledProbeKeepsCircuitOpen(t *testing.T) {
+	// The server fails every request but the third, the probe, which it
+	// holds until the client gives up.
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 3 {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	cfg := testConfig(server.URL)
+	cfg.MaxRetries = 0
+	cfg.FailureThreshold = 2
+	cfg.Cooldown = 50 * time.Millisecond
+	client := NewClient(cfg)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		client.Fetch(ctx, "Muse", "Supermassive Black Hole")
+	}
+	time.Sleep(cfg.Cooldown)
+	probeCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Fetch(probeCtx, "Muse", "Supermassive Black Hole"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("probe err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The cancelled probe neither closed the circuit nor kept the next call
+	// from probing, whose failure opens the circuit again.
+	var retryable retryableError
+	if _, err := client.Fetch(ctx, "Muse", "Supermassive Black Hole"); !errors.As(err, &retryable) {
+		t.Fatalf("second probe err = %v, want a server error", err)
+	}
+	if _, err := client.Fetch(ctx, "Muse", "Supermassive Black Hole"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want %v after a failed probe", err, ErrCircuitOpen)
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("%d requests, want 4", n)
+	}
+}
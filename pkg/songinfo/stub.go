@@ -0,0 +1,57 @@
+package songinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Stub is an in-process implementation of the song info API contract. It is
+// served by cmd/songinfo-stub and can be mounted on an httptest.Server so the
+// enrichment flow runs without the real API.
+type Stub struct {
+	mu    sync.RWMutex
+	songs map[string]Info
+}
+
+func NewStub() *Stub {
+	return &Stub{songs: map[string]Info{}}
+}
+
+// Add registers the answer for a group and song, matched case-insensitively.
+func (s *Stub) Add(group, song string, info Info) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.songs[stubKey(group, song)] = info
+}
+
+func (s *Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/info" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	group, song := r.URL.Query().Get("group"), r.URL.Query().Get("song")
+	if group == "" || song == "" {
+		http.Error(w, "group and song are required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	info, ok := s.songs[stubKey(group, song)]
+	s.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+func stubKey(group, song string) string {
+	return strings.ToLower(strings.TrimSpace(group)) + "\x00" + strings.ToLower(strings.TrimSpace(song))
+}
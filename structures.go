@@ -1,14 +1,40 @@
 package musiclibrary
 
+import "encoding/json"
+
 type Group struct {
-	Id        int    `json:"id" db:"id"`
-	GroupName string `json:"groupName" db:"groupname" binding:"required"`
+	Id        int      `json:"id" db:"id"`
+	GroupName string   `json:"groupName" db:"groupname" binding:"required"`
+	Score     *float64 `json:"score,omitempty" db:"score"`
 }
 
 type Song struct {
-	Id       int    `json:"id" db:"id"`
-	SongName string `json:"songName" db:"songname" binding:"required"`
-	GroupId  int    `json:"groupId" db:"groupid"`
+	Id       int      `json:"id" db:"id"`
+	SongName string   `json:"songName" db:"songname" binding:"required"`
+	GroupId  int      `json:"groupId" db:"groupid"`
+	Score    *float64 `json:"score,omitempty" db:"score"`
+	// Rating is the average rating, only set when songs are sorted by it,
+	// and 0 for songs nobody rated.
+	Rating *float64 `json:"rating,omitempty" db:"rating"`
+	// LastPlayedAt is when the song was last scrobbled by anyone, left out
+	// for songs never scrobbled.
+	LastPlayedAt *string `json:"lastPlayedAt,omitempty" db:"-" example:"2026-10-18T12:00:00"`
+}
+
+// GroupWithRelations is a group with the related resources asked for with
+// include. Songs is left out unless it was included.
+type GroupWithRelations struct {
+	Group
+	Songs []Song `json:"songs,omitempty"`
+}
+
+// SongWithRelations is a song with the related resources asked for with
+// include. Each of them is left out unless it was included.
+type SongWithRelations struct {
+	Song
+	Group   *Group       `json:"group,omitempty"`
+	Details *SongDetails `json:"details,omitempty"`
+	Albums  []SongAlbum  `json:"albums,omitempty"`
 }
 
 type UpdateGroupInput struct {
@@ -25,14 +51,19 @@ type UpdateSongInput struct {
 }
 
 type CreateSongInput struct {
-	SongName string `json:"songName" binding:"required" example:"Enter Sandman"`
-	GroupId  int    `json:"groupId" binding:"required"`
+	SongName    string `json:"songName" binding:"required" example:"Enter Sandman"`
+	GroupId     int    `json:"groupId" binding:"required"`
+	ReleaseDate string `json:"releaseDate,omitempty" example:"1991-07-29"`
+	Text        string `json:"text,omitempty" example:"Say your prayers, little one"`
+	Link        string `json:"link,omitempty" example:"https://example.com/enter-sandman"`
 }
 
 type UpdateSongDetailsInput struct {
 	ReleaseDate string `json:"releaseDate" example:"2024-01-01"`
 	Text        string `json:"text" example:"Song lyrics here"`
 	Link        string `json:"link" example:"https://example.com/song"`
+	Author      string `json:"author" example:"editor@example.com"`
+	Comment     string `json:"comment" example:"Fixed typo in the second verse"`
 }
 type SongDetails struct {
 	Id          int    `json:"id" db:"id"`
@@ -52,3 +83,486 @@ type SongDetailsT struct {
 	SongId int    `json:"songId" db:"songid"`
 	Text   string `json:"text" db:"text"`
 }
+
+type Album struct {
+	Id          int    `json:"id" db:"id"`
+	Title       string `json:"title" db:"title" binding:"required" example:"Master of Puppets"`
+	ReleaseDate string `json:"releaseDate" db:"releasedate" example:"1986-03-03"`
+	Label       string `json:"label" db:"label" example:"Elektra"`
+	CoverLink   string `json:"coverLink" db:"coverlink" example:"https://example.com/master-of-puppets.jpg"`
+}
+
+// SongAlbum is an album a song appears on together with the song's position
+// on it.
+type SongAlbum struct {
+	Album
+	SongId      int `json:"songId" db:"songid"`
+	DiscNumber  int `json:"discNumber" db:"discnumber"`
+	TrackNumber int `json:"trackNumber" db:"tracknumber"`
+}
+
+type UpdateAlbumInput struct {
+	Title       *string `json:"title" example:"Master of Puppets"`
+	ReleaseDate *string `json:"releaseDate" example:"1986-03-03"`
+	Label       *string `json:"label" example:"Elektra"`
+	CoverLink   *string `json:"coverLink" example:"https://example.com/master-of-puppets.jpg"`
+}
+
+type AlbumTrack struct {
+	AlbumId     int    `json:"albumId" db:"albumid"`
+	SongId      int    `json:"songId" db:"songid"`
+	SongName    string `json:"songName" db:"songname"`
+	DiscNumber  int    `json:"discNumber" db:"discnumber"`
+	TrackNumber int    `json:"trackNumber" db:"tracknumber"`
+}
+
+type AlbumTrackInput struct {
+	SongId      int `json:"songId" binding:"required"`
+	DiscNumber  int `json:"discNumber" example:"1"`
+	TrackNumber int `json:"trackNumber" binding:"required" example:"1"`
+}
+
+type Artist struct {
+	Id        int    `json:"id" db:"id"`
+	Name      string `json:"name" db:"name" binding:"required" example:"James Hetfield"`
+	BirthDate string `json:"birthDate" db:"birthdate" example:"1963-08-03"`
+}
+
+type UpdateArtistInput struct {
+	Name      *string `json:"name" example:"James Hetfield"`
+	BirthDate *string `json:"birthDate" example:"1963-08-03"`
+}
+
+type Membership struct {
+	Id         int    `json:"id" db:"id"`
+	GroupId    int    `json:"groupId" db:"groupid"`
+	GroupName  string `json:"groupName" db:"groupname"`
+	ArtistId   int    `json:"artistId" db:"artistid"`
+	ArtistName string `json:"artistName" db:"artistname"`
+	Role       string `json:"role" db:"role"`
+	StartDate  string `json:"startDate" db:"startdate"`
+	EndDate    string `json:"endDate" db:"enddate"`
+}
+
+type MembershipInput struct {
+	ArtistId  int    `json:"artistId" binding:"required"`
+	Role      string `json:"role" example:"vocals, rhythm guitar"`
+	StartDate string `json:"startDate" example:"1981-10-28"`
+	EndDate   string `json:"endDate" example:""`
+}
+
+// Taggable names the kind of entity genres and tags can be attached to.
+type Taggable string
+
+const (
+	TaggableSong  Taggable = "song"
+	TaggableGroup Taggable = "group"
+)
+
+type Genre struct {
+	Id       int    `json:"id" db:"id"`
+	Name     string `json:"name" db:"name" binding:"required" example:"Thrash Metal"`
+	ParentId *int   `json:"parentId" db:"parentid"`
+}
+
+type Tag struct {
+	Id   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+}
+
+type SearchResult struct {
+	SongId    int      `json:"songId" db:"songid"`
+	SongName  string   `json:"songName" db:"songname"`
+	GroupId   int      `json:"groupId" db:"groupid"`
+	GroupName string   `json:"groupName" db:"groupname"`
+	Rank      float64  `json:"rank" db:"rank"`
+	Snippet   string   `json:"snippet" db:"snippet"`
+	Lines     []string `json:"lines" db:"-"`
+}
+
+type LyricsSection struct {
+	Type   string   `json:"type" example:"verse"`
+	Number int      `json:"number,omitempty" example:"1"`
+	Label  string   `json:"label" example:"Verse 1"`
+	Lines  []string `json:"lines"`
+}
+
+type SyncedWord struct {
+	TimeMs int    `json:"timeMs" example:"83500"`
+	Time   string `json:"time" example:"01:23.50"`
+	Text   string `json:"text" example:"Mama"`
+}
+
+type SyncedLine struct {
+	TimeMs int          `json:"timeMs" example:"83500"`
+	Time   string       `json:"time" example:"01:23.50"`
+	Text   string       `json:"text" example:"Mama, just killed a man"`
+	Words  []SyncedWord `json:"words,omitempty"`
+}
+
+type SyncedLyrics struct {
+	Tags  map[string]string `json:"tags"`
+	Lines []SyncedLine      `json:"lines"`
+}
+
+type SyncedPosition struct {
+	At      string       `json:"at" example:"01:23.50"`
+	Current *SyncedLine  `json:"current"`
+	Next    []SyncedLine `json:"next"`
+}
+
+type Translation struct {
+	Id         int    `json:"id" db:"id"`
+	SongId     int    `json:"songId" db:"songid"`
+	Language   string `json:"language" db:"language" binding:"required" example:"ru"`
+	IsOriginal bool   `json:"isOriginal" db:"isoriginal"`
+	Translator string `json:"translator" db:"translator" example:"Ivan Petrov"`
+	Text       string `json:"text" db:"text" binding:"required"`
+}
+
+type UpdateTranslationInput struct {
+	IsOriginal *bool   `json:"isOriginal"`
+	Translator *string `json:"translator" example:"Ivan Petrov"`
+	Text       *string `json:"text"`
+}
+
+type VersePair struct {
+	Original    string `json:"original"`
+	Translation string `json:"translation"`
+}
+
+type Revision struct {
+	Id          int    `json:"id" db:"id"`
+	SongId      int    `json:"songId" db:"songid"`
+	Revision    int    `json:"revision" db:"revision"`
+	ReleaseDate string `json:"releaseDate" db:"releasedate"`
+	Text        string `json:"text,omitempty" db:"text"`
+	Link        string `json:"link" db:"link"`
+	Author      string `json:"author" db:"author"`
+	Comment     string `json:"comment" db:"comment"`
+	CreatedAt   string `json:"createdAt" db:"createdat"`
+}
+
+type RollbackInput struct {
+	Author string `json:"author" example:"editor@example.com"`
+}
+
+type ImportRow struct {
+	Line        int    `json:"-"`
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	ReleaseDate string `json:"releaseDate"`
+	Link        string `json:"link"`
+	Text        string `json:"text"`
+}
+
+type ImportResult struct {
+	Line   int    `json:"line" example:"2"`
+	Group  string `json:"group" example:"Queen"`
+	Song   string `json:"song" example:"Bohemian Rhapsody"`
+	Status string `json:"status" example:"created" enums:"created,updated,skipped,error"`
+	Reason string `json:"reason,omitempty" example:"already exists with the same details"`
+	SongId int    `json:"songId,omitempty" example:"3"`
+}
+
+type ImportReport struct {
+	DryRun  bool           `json:"dryRun"`
+	Created int            `json:"created"`
+	Updated int            `json:"updated"`
+	Skipped int            `json:"skipped"`
+	Errors  int            `json:"errors"`
+	Rows    []ImportResult `json:"rows"`
+}
+
+type Job struct {
+	Id          int             `json:"id" db:"id"`
+	Type        string          `json:"type" db:"type"`
+	Payload     json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	Status      string          `json:"status" db:"status" example:"queued"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	MaxAttempts int             `json:"maxAttempts" db:"maxattempts"`
+	Claims      int             `json:"-" db:"claims"`
+	LastError   string          `json:"lastError,omitempty" db:"lasterror"`
+	RunAt       string          `json:"runAt" db:"runat"`
+	CreatedAt   string          `json:"createdAt" db:"createdat"`
+	UpdatedAt   string          `json:"updatedAt" db:"updatedat"`
+}
+
+// PageRequest selects a page of a list. After is the cursor returned as Next
+// by the previous page, empty for the first one. Offset skips rows after the
+// cursor and only serves the deprecated page parameter. WithTotal asks for
+// the number of rows in the whole list, which costs an extra count.
+type PageRequest struct {
+	After     string
+	Limit     int
+	Offset    int
+	WithTotal bool
+}
+
+// Page is one page of a list. Next is the cursor of the following page,
+// empty on the last one. Total is only set when it was asked for.
+type Page[T any] struct {
+	Items []T
+	Next  string
+	Total *int
+}
+
+// Role decides what a user may do: viewers read, editors also change the
+// library and admins also manage users.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+var roleRanks = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleAdmin: 3}
+
+// Allows reports whether r grants at least the rights of required.
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+type User struct {
+	Id        int    `json:"id" db:"id"`
+	Name      string `json:"name" db:"name" binding:"required" example:"alice"`
+	Role      Role   `json:"role" db:"role" binding:"required,oneof=viewer editor admin" example:"editor"`
+	CreatedAt string `json:"createdAt" db:"createdat"`
+}
+
+// APIKey describes a key without the key itself, which is only stored
+// hashed. Prefix is the start of the key, to tell keys apart.
+type APIKey struct {
+	Id         int     `json:"id" db:"id"`
+	UserId     int     `json:"userId" db:"userid"`
+	Name       string  `json:"name" db:"name" example:"deploy script"`
+	Prefix     string  `json:"prefix" db:"prefix" example:"mlk_3f9a"`
+	CreatedAt  string  `json:"createdAt" db:"createdat"`
+	LastUsedAt *string `json:"lastUsedAt" db:"lastusedat"`
+}
+
+type APIKeyInput struct {
+	Name string `json:"name" binding:"required" example:"deploy script"`
+}
+
+// NewAPIKey is a key just created, the only time Key is shown.
+type NewAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"mlk_3f9a0c1e..."`
+}
+
+type TokenInput struct {
+	APIKey string `json:"apiKey" binding:"required" example:"mlk_3f9a0c1e..."`
+}
+
+type Token struct {
+	AccessToken string `json:"accessToken"`
+	TokenType   string `json:"tokenType" example:"Bearer"`
+	ExpiresIn   int    `json:"expiresIn" example:"900"`
+}
+
+// Favorite is a song a user starred.
+type Favorite struct {
+	SongId    int    `json:"songId" db:"songid"`
+	SongName  string `json:"songName" db:"songname"`
+	CreatedAt string `json:"createdAt" db:"createdat"`
+}
+
+// Rating is how a user rated a song, from 1 to 5.
+type Rating struct {
+	SongId    int    `json:"songId" db:"songid"`
+	SongName  string `json:"songName" db:"songname"`
+	Rating    int    `json:"rating" db:"rating" example:"4"`
+	UpdatedAt string `json:"updatedAt" db:"updatedat"`
+}
+
+type RatingInput struct {
+	Rating int `json:"rating" binding:"required,min=1,max=5" example:"4"`
+}
+
+// Playlist is an ordered list of songs kept by a user for themselves.
+type Playlist struct {
+	Id          int    `json:"id" db:"id"`
+	UserId      int    `json:"userId" db:"userid"`
+	Name        string `json:"name" db:"name" binding:"required" example:"Road trip"`
+	Description string `json:"description" db:"description" example:"Loud and long"`
+	CreatedAt   string `json:"createdAt" db:"createdat"`
+}
+
+type UpdatePlaylistInput struct {
+	Name        *string `json:"name" example:"Road trip"`
+	Description *string `json:"description" example:"Loud and long"`
+}
+
+// PlaylistItem is a song on a playlist. The same song can be on a playlist
+// more than once, each time as an item of its own. Position counts from 1.
+type PlaylistItem struct {
+	Id       int    `json:"id" db:"id"`
+	Position int    `json:"position" db:"position"`
+	SongId   int    `json:"songId" db:"songid"`
+	SongName string `json:"songName" db:"songname"`
+}
+
+// PlaylistWithItems is a playlist with its items in order.
+type PlaylistWithItems struct {
+	Playlist
+	Items []PlaylistItem `json:"items"`
+}
+
+// PlaylistItemInput adds a song at Position, moving the items from there on
+// down, or at the end without a position.
+type PlaylistItemInput struct {
+	SongId   int  `json:"songId" binding:"required" example:"1"`
+	Position *int `json:"position" binding:"omitempty,min=1" example:"2"`
+}
+
+// ReorderPlaylistInput lists every item of a playlist in its new order.
+type ReorderPlaylistInput struct {
+	ItemIds []int `json:"itemIds" binding:"required" example:"3,1,2"`
+}
+
+// PlaylistTrack is a song as playlist files describe it: who plays it, its
+// title and where to find it.
+type PlaylistTrack struct {
+	SongId   int    `json:"songId" db:"songid"`
+	Artist   string `json:"artist" db:"artist"`
+	Title    string `json:"title" db:"title"`
+	Location string `json:"location" db:"location"`
+}
+
+// PlaylistImportEntry is one entry of an imported playlist file. Entry counts
+// the entries of the file from 1.
+type PlaylistImportEntry struct {
+	Entry    int    `json:"entry" example:"3"`
+	Artist   string `json:"artist" example:"Queen"`
+	Title    string `json:"title" example:"Bohemian Rhapsody"`
+	Location string `json:"location,omitempty" example:"https://www.youtube.com/watch?v=fJ9rUzIMcZQ"`
+	Status   string `json:"status" example:"matched" enums:"matched,unmatched"`
+	SongId   int    `json:"songId,omitempty" example:"3"`
+}
+
+type PlaylistImportReport struct {
+	DryRun     bool                  `json:"dryRun"`
+	PlaylistId int                   `json:"playlistId,omitempty" example:"2"`
+	Name       string                `json:"name" example:"Road trip"`
+	Matched    int                   `json:"matched"`
+	Unmatched  int                   `json:"unmatched"`
+	Entries    []PlaylistImportEntry `json:"entries"`
+}
+
+// PlayInput reports a play of a song. PlayedAt is RFC 3339 and defaults to
+// now. Duration is how long the song was listened to and SongDuration how
+// long it is, both in seconds. Without SongDuration only plays of at least 4
+// minutes count as scrobbles.
+type PlayInput struct {
+	SongId       int    `json:"songId" example:"1"`
+	PlayedAt     string `json:"playedAt" example:"2026-10-18T12:00:00Z"`
+	Duration     int    `json:"duration" example:"200"`
+	SongDuration int    `json:"songDuration" example:"331"`
+	Client       string `json:"client" example:"web"`
+}
+
+// Play is a play as recorded. Scrobble tells whether it counts towards
+// statistics.
+type Play struct {
+	Id           int    `json:"id" db:"id"`
+	UserId       int    `json:"userId" db:"userid"`
+	SongId       int    `json:"songId" db:"songid"`
+	PlayedAt     string `json:"playedAt" db:"playedat"`
+	Duration     int    `json:"duration" db:"duration"`
+	SongDuration int    `json:"songDuration" db:"songduration"`
+	Client       string `json:"client" db:"client"`
+	Scrobble     bool   `json:"scrobble" db:"scrobble"`
+}
+
+// PlayResult tells what became of one play of a submission. Index counts the
+// plays of the submission from 0.
+type PlayResult struct {
+	Index    int    `json:"index" example:"0"`
+	SongId   int    `json:"songId" example:"1"`
+	Status   string `json:"status" example:"recorded" enums:"recorded,duplicate,error"`
+	Reason   string `json:"reason,omitempty" example:"song does not exist"`
+	PlayId   int    `json:"playId,omitempty" example:"7"`
+	Scrobble bool   `json:"scrobble"`
+}
+
+type PlayReport struct {
+	Recorded   int          `json:"recorded"`
+	Scrobbles  int          `json:"scrobbles"`
+	Duplicates int          `json:"duplicates"`
+	Errors     int          `json:"errors"`
+	Plays      []PlayResult `json:"plays"`
+}
+
+// StatsQuery selects the scrobbles statistics are made of: those from From
+// up to but not including To, of one user or everyone when UserId is 0.
+// Limit caps the number of top songs or groups. From and To come as dates or
+// RFC 3339 timestamps and reach the repositories as UTC timestamps in the
+// format plays are stored in.
+type StatsQuery struct {
+	From   string
+	To     string
+	UserId int
+	Limit  int
+}
+
+type SongStat struct {
+	SongId    int    `json:"songId" db:"songid" example:"1"`
+	SongName  string `json:"songName" db:"songname" example:"Enter Sandman"`
+	GroupId   int    `json:"groupId" db:"groupid" example:"1"`
+	GroupName string `json:"groupName" db:"groupname" example:"Metallica"`
+	Plays     int    `json:"plays" db:"plays" example:"42"`
+}
+
+type GroupStat struct {
+	GroupId   int    `json:"groupId" db:"groupid" example:"1"`
+	GroupName string `json:"groupName" db:"groupname" example:"Metallica"`
+	Plays     int    `json:"plays" db:"plays" example:"42"`
+}
+
+// DayStat counts the scrobbles of a day, in UTC.
+type DayStat struct {
+	Day   string `json:"day" db:"day" example:"2026-10-18"`
+	Plays int    `json:"plays" db:"plays" example:"12"`
+}
+
+// SongVector holds how often each term occurs in the lyrics of a song, the
+// similar songs index weighs them by TF-IDF.
+type SongVector struct {
+	SongId int
+	Terms  map[string]int
+}
+
+// SongLyrics is the text of a song that has no vector yet.
+type SongLyrics struct {
+	SongId int    `db:"songid"`
+	Text   string `db:"text"`
+}
+
+// SongFeatures is what similar songs are boosted by besides their lyrics:
+// the group, the genres of the song and its group, and the release date.
+type SongFeatures struct {
+	SongId      int
+	SongName    string
+	GroupId     int
+	GroupName   string
+	ReleaseDate string
+	Genres      []int
+}
+
+// SimilarSong is a song whose lyrics resemble those of another. Lyrics is
+// the cosine similarity of their TF-IDF vectors, Score the similarity after
+// the boosts for a shared group, genre or era.
+type SimilarSong struct {
+	SongId       int     `json:"songId" example:"4"`
+	SongName     string  `json:"songName" example:"The Unforgiven"`
+	GroupId      int     `json:"groupId" example:"1"`
+	GroupName    string  `json:"groupName" example:"Metallica"`
+	ReleaseDate  string  `json:"releaseDate,omitempty" example:"1991-10-28"`
+	Score        float64 `json:"score" example:"0.42"`
+	Lyrics       float64 `json:"lyrics" example:"0.31"`
+	SameGroup    bool    `json:"sameGroup" example:"true"`
+	SharedGenres int     `json:"sharedGenres" example:"1"`
+	SameEra      bool    `json:"sameEra" example:"true"`
+}